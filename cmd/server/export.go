@@ -0,0 +1,95 @@
+// CLAUDE:SUMMARY CLI subcommand packaging selected dictionaries into a signed, versioned offline bundle for air-gapped clients (see dict.OpenBundle), and generating its Ed25519 signing keys.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func cmdExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dictsDir := fs.String("dicts-dir", "dicts", "path to dictionaries directory")
+	dicts := fs.String("dicts", "", "comma-separated dictionary IDs to export (default: all)")
+	version := fs.String("version", "", "bundle version (default: UTC timestamp)")
+	output := fs.String("o", "", "output file (default: touchstone-<version>.tsb)")
+	keyPath := fs.String("key", "", "hex Ed25519 private key file signing the bundle")
+	genKey := fs.String("genkey", "", "write a new key pair to <path>.key and <path>.pub, then exit")
+	_ = fs.Parse(args)
+
+	if *genKey != "" {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "generate key: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*genKey+".key", []byte(hex.EncodeToString(priv)+"\n"), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "write key: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*genKey+".pub", []byte(hex.EncodeToString(pub)+"\n"), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write public key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Key pair written to %s.key and %s.pub\n", *genKey, *genKey)
+		return
+	}
+
+	var key ed25519.PrivateKey
+	if *keyPath != "" {
+		raw, err := os.ReadFile(*keyPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read key: %v\n", err)
+			os.Exit(1)
+		}
+		b, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil || len(b) != ed25519.PrivateKeySize {
+			fmt.Fprintf(os.Stderr, "read key: %s is not a hex Ed25519 private key\n", *keyPath)
+			os.Exit(1)
+		}
+		key = ed25519.PrivateKey(b)
+	}
+
+	if *version == "" {
+		*version = time.Now().UTC().Format("20060102T150405Z")
+	}
+	if *output == "" {
+		*output = "touchstone-" + *version + ".tsb"
+	}
+	var ids []string
+	for _, id := range strings.Split(*dicts, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create bundle: %v\n", err)
+		os.Exit(1)
+	}
+	bm, err := dict.WriteBundle(f, *dictsDir, ids, *version, key)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(*output)
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, d := range bm.Dicts {
+		fmt.Printf("  %s %s (%d files)\n", d.ID, d.Version, len(d.Files))
+	}
+	signed := "unsigned"
+	if bm.Signed {
+		signed = "signed"
+	}
+	fmt.Printf("\nBundle %s written to %s: %d dictionaries, %s\n", bm.Version, *output, len(bm.Dicts), signed)
+}
@@ -3,12 +3,16 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/hazyhaar/touchstone-registry/pkg/admin"
 	"github.com/hazyhaar/touchstone-registry/pkg/importer"
 )
 
@@ -17,8 +21,21 @@ func cmdImport(args []string) {
 	source := fs.String("source", "", "adapter ID to import (e.g. insee-prenoms-fr)")
 	all := fs.Bool("all", false, "import all available sources")
 	outputDir := fs.String("output-dir", "dicts", "output directory for dictionaries")
+	force := fs.Bool("force", false, "rebuild in full, even when the source is unchanged or publishes deltas")
+	sha := fs.String("sha256", "", "store the expected SHA-256 of the --source download (\"none\" clears it)")
+	adaptersDir := fs.String("adapters-dir", "", "directory of declarative adapter specs (*.yaml) to load")
+	adminDBPath := fs.String("admin-db", "", "admin database holding the quality gates of the sources (default <output-dir>/admin.db)")
 	_ = fs.Parse(args)
 
+	if *adaptersDir != "" {
+		n, err := importer.LoadSpecs(*adaptersDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erreur chargement adaptateurs: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d adaptateurs declaratifs charges depuis %s\n", n, *adaptersDir)
+	}
+
 	// Open source DB and seed defaults.
 	sourcesDBPath := filepath.Join(*outputDir, "sources.db")
 	sdb, err := importer.OpenSourceDB(sourcesDBPath)
@@ -27,15 +44,69 @@ func cmdImport(args []string) {
 		os.Exit(1)
 	}
 
-	if runErr := runImport(sdb, *all, *source, *outputDir); runErr != nil {
+	if *sha != "" {
+		if *source == "" {
+			sdb.Close()
+			fmt.Fprintln(os.Stderr, "--sha256 requiert --source")
+			os.Exit(1)
+		}
+		if err := sdb.Seed(importer.All()); err == nil {
+			if *sha == "none" {
+				*sha = ""
+			}
+			err = sdb.SetSHA256(*source, *sha)
+		}
+		if err != nil {
+			sdb.Close()
+			fmt.Fprintf(os.Stderr, "Erreur SHA-256: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *adminDBPath == "" {
+		*adminDBPath = filepath.Join(*outputDir, "admin.db")
+	}
+	gates, closeGates, err := openGates(*adminDBPath)
+	if err != nil {
+		sdb.Close()
+		fmt.Fprintf(os.Stderr, "Erreur ouverture admin.db: %v\n", err)
+		os.Exit(1)
+	}
+
+	if runErr := runImport(sdb, gates, *all, *source, *outputDir, *force); runErr != nil {
+		closeGates()
 		sdb.Close()
 		fmt.Fprintf(os.Stderr, "%v\n", runErr)
 		os.Exit(1)
 	}
+	closeGates()
 	sdb.Close()
 }
 
-func runImport(sdb *importer.SourceDB, all bool, source, outputDir string) error {
+// gatesFunc returns the quality gates of the source imported by an adapter,
+// nil when it has none.
+type gatesFunc func(adapterID string) (*importer.Gates, error)
+
+// openGates reads the quality gates set in the admin panel from the admin
+// database at path, so that CLI imports check them as scheduled imports do.
+// Without an admin database, no source has gates.
+func openGates(path string) (gatesFunc, func(), error) {
+	none := func(string) (*importer.Gates, error) { return nil, nil }
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return none, func() {}, nil
+	}
+	db, err := sql.Open("sqlite", path+"?_txlock=immediate&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := admin.InitSchema(db); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return admin.NewService(db, nil).AdapterGates, func() { db.Close() }, nil
+}
+
+func runImport(sdb *importer.SourceDB, gates gatesFunc, all bool, source, outputDir string, force bool) error {
 	if err := sdb.Seed(importer.All()); err != nil {
 		return fmt.Errorf("Erreur seed sources: %w", err)
 	}
@@ -53,8 +124,10 @@ func runImport(sdb *importer.SourceDB, all bool, source, outputDir string) error
 		}
 		fmt.Println()
 		fmt.Println("Usage :")
-		fmt.Println("  touchstone import --source <id> [--output-dir <dir>]")
-		fmt.Println("  touchstone import --all [--output-dir <dir>]")
+		fmt.Println("  touchstone import --source <id> [--output-dir <dir>] [--force]")
+		fmt.Println("  touchstone import --all [--output-dir <dir>] [--force]")
+		fmt.Println("  touchstone import --source <id> --sha256 <hex|none>")
+		fmt.Println("  touchstone import --adapters-dir <dir> ...  (specs YAML, voir pkg/importer/specs)")
 		return nil
 	}
 
@@ -63,17 +136,19 @@ func runImport(sdb *importer.SourceDB, all bool, source, outputDir string) error
 
 	if all {
 		for _, a := range importer.All() {
-			url, urlErr := sdb.GetURL(a.ID())
-			if urlErr != nil {
-				fmt.Fprintf(os.Stderr, "[%s] ERREUR (URL): %v\n", a.ID(), urlErr)
-				continue
-			}
 			fmt.Printf("[%s] Import en cours...\n", a.ID())
-			if importErr := a.Import(ctx, url, outputDir); importErr != nil {
+			g, importErr := gates(a.ID())
+			if importErr == nil {
+				importErr = importer.Run(ctx, sdb, a, outputDir, importer.RunOptions{Progress: progressPrinter(), Force: force, Gates: g, Logger: slog.Default()})
+			}
+			switch {
+			case errors.Is(importErr, importer.ErrNotModified):
+				fmt.Printf("[%s] inchange depuis le dernier import\n", a.ID())
+			case importErr != nil:
 				fmt.Fprintf(os.Stderr, "[%s] ERREUR: %v\n", a.ID(), importErr)
-				continue
+			default:
+				fmt.Printf("[%s] OK\n", a.ID())
 			}
-			fmt.Printf("[%s] OK\n", a.ID())
 		}
 		return nil
 	}
@@ -88,15 +163,42 @@ func runImport(sdb *importer.SourceDB, all bool, source, outputDir string) error
 		return err
 	}
 
-	url, err := sdb.GetURL(a.ID())
+	g, err := gates(a.ID())
 	if err != nil {
-		return fmt.Errorf("[%s] ERREUR (URL): %w", a.ID(), err)
+		return fmt.Errorf("[%s] ERREUR: %w", a.ID(), err)
 	}
-
 	fmt.Printf("[%s] Import en cours...\n", a.ID())
-	if err := a.Import(ctx, url, outputDir); err != nil {
+	err = importer.Run(ctx, sdb, a, outputDir, importer.RunOptions{Progress: progressPrinter(), Force: force, Gates: g, Logger: slog.Default()})
+	if errors.Is(err, importer.ErrNotModified) {
+		fmt.Printf("[%s] inchange depuis le dernier import (--force pour reconstruire)\n", a.ID())
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("[%s] ERREUR: %w", a.ID(), err)
 	}
 	fmt.Printf("[%s] OK -> %s/%s/\n", a.ID(), outputDir, a.DictID())
 	return nil
 }
+
+// progressPrinter reports downloads on stdout: their start, then their
+// progress every 10 seconds.
+func progressPrinter() importer.ProgressFunc {
+	var current string
+	var last time.Time
+	return func(url string, done, total int64) {
+		if url != current {
+			current, last = url, time.Now()
+			fmt.Printf("  telechargement %s...\n", url)
+			return
+		}
+		if time.Since(last) < 10*time.Second {
+			return
+		}
+		last = time.Now()
+		if total > 0 {
+			fmt.Printf("  %.1f / %.1f Mo (%d%%)\n", float64(done)/1e6, float64(total)/1e6, done*100/total)
+		} else {
+			fmt.Printf("  %.1f Mo\n", float64(done)/1e6)
+		}
+	}
+}
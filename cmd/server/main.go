@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Entry point dispatching serve/import subcommands, wiring HTTP+MCP server with dictionary registry and graceful shutdown.
+// CLAUDE:SUMMARY Entry point dispatching serve/import/migrate-gob/export subcommands, wiring HTTP+MCP server with dictionary registry and graceful shutdown.
 package main
 
 import (
@@ -25,6 +25,7 @@ import (
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
 	"github.com/hazyhaar/touchstone-registry/pkg/fo"
 	"github.com/hazyhaar/touchstone-registry/pkg/importer"
+	"github.com/hazyhaar/touchstone-registry/pkg/policy"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"gopkg.in/yaml.v3"
 
@@ -46,6 +47,9 @@ type config struct {
 	FOAllowedDicts []string `yaml:"fo_allowed_dicts"`
 	NERPython      string   `yaml:"ner_python"` // path to python with spaCy
 	NERScript      string   `yaml:"ner_script"` // path to scripts/ner.py
+	Policies       policy.Config `yaml:"policies"`
+	AdaptersDir    string        `yaml:"adapters_dir"` // declarative adapter specs loaded at startup
+	ImportConcurrency int        `yaml:"import_concurrency"` // scheduled imports running at once (default 2)
 }
 
 func main() {
@@ -61,6 +65,10 @@ func main() {
 		cmdImport(os.Args[2:])
 	case "migrate-gob":
 		cmdMigrateGob(os.Args[2:])
+	case "export":
+		cmdExport(os.Args[2:])
+	case "rollback":
+		cmdRollback(os.Args[2:])
 	default:
 		usage()
 		os.Exit(1)
@@ -68,7 +76,7 @@ func main() {
 }
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "Usage: touchstone <command>\n\nCommands:\n  serve        Start the server (HTTP/1.1+2, HTTP/3, MCP-over-QUIC)\n  import       Download and build dictionaries from public sources\n  migrate-gob  Convert data.gob files to data.db (SQLite)\n")
+	fmt.Fprintf(os.Stderr, "Usage: touchstone <command>\n\nCommands:\n  serve        Start the server (HTTP/1.1+2, HTTP/3, MCP-over-QUIC)\n  import       Download and build dictionaries from public sources\n  migrate-gob  Convert data.gob files to data.db (SQLite) and add missing range indexes\n  export       Package dictionaries into a signed offline bundle\n  rollback     Restore the previous build of an imported dictionary\n")
 }
 
 func cmdServe(args []string) {
@@ -87,6 +95,15 @@ func cmdServe(args []string) {
 		os.Exit(1)
 	}
 
+	if cfg.AdaptersDir != "" {
+		n, err := importer.LoadSpecs(cfg.AdaptersDir)
+		if err != nil {
+			logger.Error("failed to load adapter specs", "dir", cfg.AdaptersDir, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("declarative adapters loaded", "dir", cfg.AdaptersDir, "count", n)
+	}
+
 	deps := initServer(sdb, cfg, logger)
 
 	// SIGHUP: hot reload dictionaries.
@@ -141,7 +158,11 @@ func cmdServe(args []string) {
 	}()
 
 	go deps.checker.Start(ctx)
+	go deps.reg.StartCriblage(ctx, logger, 24*time.Hour)
 	go deps.foRL.StartGC(ctx)
+	if deps.sched != nil {
+		go deps.sched.Start(ctx, time.Hour)
+	}
 
 	// Start chassis (TCP + QUIC).
 	go func() {
@@ -167,6 +188,7 @@ type serverDeps struct {
 	adminDB *sql.DB
 	auditor *audit.SQLiteLogger
 	foRL    *fo.RateLimiter
+	sched   *admin.Scheduler // nil without admin_token
 }
 
 func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *serverDeps {
@@ -185,11 +207,19 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 	}
 	logger.Info("dictionaries loaded", "count", reg.DictCount(), "entries", reg.TotalEntries())
 
+	// Sensitivity policies, enforced by the API routes and the MCP tools.
+	pol, err := policy.New(cfg.Policies)
+	if err != nil {
+		sdb.Close()
+		logger.Error("invalid policies", "error", err)
+		os.Exit(1)
+	}
+
 	// Combined HTTP mux: public API + admin.
 	topMux := http.NewServeMux()
 
 	// Public API routes.
-	apiRouter := api.NewRouter(reg)
+	apiRouter := api.NewRouter(reg, pol)
 	topMux.Handle("/v1/", apiRouter)
 	topMux.Handle("/v1/health", apiRouter)
 
@@ -239,8 +269,9 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 
 	// Admin routes (only if admin_token is set).
 	var auditor *audit.SQLiteLogger
+	var sched *admin.Scheduler
 	if cfg.AdminToken != "" {
-		if _, err := adminDB.Exec(admin.Schema); err != nil {
+		if err := admin.InitSchema(adminDB); err != nil {
 			logger.Error("admin schema init failed", "error", err)
 			os.Exit(1)
 		}
@@ -252,6 +283,7 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 		}
 
 		adminSvc := admin.NewService(adminDB, auditor)
+		adminSvc.SetRegistry(reg)
 
 		// Sync on-disk dictionaries and legacy sources into admin DB.
 		if err := adminSvc.SyncFromRegistry(reg); err != nil {
@@ -261,6 +293,13 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 			logger.Warn("admin migrate from source db failed", "error", err)
 		}
 
+		// Background imports of due sources, also triggered from the admin API.
+		concurrency := cfg.ImportConcurrency
+		if concurrency <= 0 {
+			concurrency = 2
+		}
+		sched = admin.NewScheduler(adminSvc, sdb, cfg.DictsDir, logger, concurrency)
+
 		adminAPIRouter := admin.NewRouter(adminSvc, cfg.AdminToken)
 		topMux.Handle("/admin/v1/", adminAPIRouter)
 
@@ -276,7 +315,7 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 
 	// MCP server with Touchstone tools.
 	mcpSrv := mcp.NewServer(&mcp.Implementation{Name: "touchstone", Version: "0.1.0"}, nil)
-	api.RegisterMCPTools(mcpSrv, reg)
+	api.RegisterMCPTools(mcpSrv, reg, pol)
 
 	// Global security headers via shield package.
 	globalHandler := shield.SecurityHeaders(shield.DefaultHeaders())(topMux)
@@ -299,7 +338,7 @@ func initServer(sdb *importer.SourceDB, cfg config, logger *slog.Logger) *server
 	// Source availability checker (every 24h).
 	checker := importer.NewChecker(sdb, logger, 24*time.Hour)
 
-	return &serverDeps{reg: reg, srv: srv, checker: checker, adminDB: adminDB, auditor: auditor, foRL: foRL}
+	return &serverDeps{reg: reg, srv: srv, checker: checker, adminDB: adminDB, auditor: auditor, foRL: foRL, sched: sched}
 }
 
 func loadConfig(path string, logger *slog.Logger) config {
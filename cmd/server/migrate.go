@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY CLI subcommand to migrate data.gob dictionaries to data.db (SQLite).
+// CLAUDE:SUMMARY CLI subcommand to migrate data.gob dictionaries to data.db (SQLite), and to add range indexes to data.db files built before them.
 package main
 
 import (
@@ -23,15 +23,28 @@ func cmdMigrateGob(args []string) {
 		os.Exit(1)
 	}
 
-	var converted, skipped, failed int
+	var converted, indexed, skipped, failed int
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		dir := filepath.Join(*dictsDir, entry.Name())
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
 			continue
 		}
-		dir := filepath.Join(*dictsDir, entry.Name())
 		gobPath := filepath.Join(dir, "data.gob")
 		dbPath := filepath.Join(dir, "data.db")
 
+		// Add the range index to databases built before range queries.
+		if _, err := os.Stat(dbPath); err == nil {
+			built, err := dict.EnsureRangeIndex(dbPath)
+			switch {
+			case err != nil:
+				fmt.Printf("  index %s FAILED (%v)\n", entry.Name(), err)
+				failed++
+			case built:
+				fmt.Printf("  indexed %s (range_index added)\n", entry.Name())
+				indexed++
+			}
+		}
+
 		// Skip if no gob file.
 		if _, err := os.Stat(gobPath); err != nil {
 			continue
@@ -73,7 +86,7 @@ func cmdMigrateGob(args []string) {
 		}
 	}
 
-	fmt.Printf("\nMigration complete: %d converted, %d skipped, %d failed\n", converted, skipped, failed)
+	fmt.Printf("\nMigration complete: %d converted, %d indexed, %d skipped, %d failed\n", converted, indexed, skipped, failed)
 	if failed > 0 {
 		os.Exit(1)
 	}
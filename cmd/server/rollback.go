@@ -0,0 +1,28 @@
+// CLAUDE:SUMMARY CLI subcommand putting the previous build of an imported dictionary (<id>.prev) back in place.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/importer"
+)
+
+func cmdRollback(args []string) {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	dictsDir := fs.String("dicts-dir", "dicts", "path to dictionaries directory")
+	dictID := fs.String("dict", "", "dictionary ID to roll back")
+	_ = fs.Parse(args)
+
+	if *dictID == "" {
+		fmt.Fprintln(os.Stderr, "Usage: touchstone rollback --dict <id> [--dicts-dir <dir>]")
+		os.Exit(1)
+	}
+	if err := importer.Rollback(*dictsDir, *dictID); err != nil {
+		fmt.Fprintf(os.Stderr, "rollback: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s rolled back; rolling back again restores the replaced build.\n", *dictID)
+	fmt.Println("A running server serves it after SIGHUP (or use POST /admin/v1/dicts/{id}/rollback instead).")
+}
@@ -0,0 +1,204 @@
+// CLAUDE:SUMMARY Admin service operations on alias pool entries (add, remove, CSV import, reorder), persisted to the pool manifest with a registry reload and an audit entry per change.
+// CLAUDE:DEPENDS pkg/admin/service.go, pkg/dict/aliaspool.go
+// CLAUDE:EXPORTS SetRegistry, ListAliases, AddAliases, RemoveAlias, ImportAliasesCSV, ReorderAliases
+
+package admin
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+var (
+	errNoRegistry        = errors.New("no dictionary registry attached")
+	errAliasPoolNotFound = errors.New("alias pool not found")
+	errAliasNotFound     = errors.New("alias not found")
+	errInvalidAliases    = errors.New("invalid aliases")
+)
+
+// SetRegistry attaches the live registry whose alias pools the service edits.
+func (s *Service) SetRegistry(reg *dict.Registry) {
+	s.reg = reg
+}
+
+// ListAliases returns the entries of the alias pool serving domain.
+func (s *Service) ListAliases(domain string) ([]dict.AliasEntry, error) {
+	if s.reg == nil {
+		return nil, errNoRegistry
+	}
+	if _, ok := s.reg.AliasPoolID(domain); !ok {
+		return nil, errAliasPoolNotFound
+	}
+	return s.reg.GetAliases(domain), nil
+}
+
+// AddAliases appends entries to the pool serving domain. Aliases already in
+// the pool, or repeated in entries, are rejected.
+func (s *Service) AddAliases(domain string, entries []dict.AliasEntry) ([]dict.AliasEntry, error) {
+	return s.editAliases("alias.add", domain, map[string]any{"domain": domain, "entries": entries},
+		func(current []dict.AliasEntry) ([]dict.AliasEntry, error) {
+			return appendAliases(current, entries)
+		})
+}
+
+// RemoveAlias removes alias from the pool serving domain.
+func (s *Service) RemoveAlias(domain, alias string) ([]dict.AliasEntry, error) {
+	return s.editAliases("alias.remove", domain, map[string]any{"domain": domain, "alias": alias},
+		func(current []dict.AliasEntry) ([]dict.AliasEntry, error) {
+			for i, e := range current {
+				if e.Alias == alias {
+					return append(current[:i], current[i+1:]...), nil
+				}
+			}
+			return nil, errAliasNotFound
+		})
+}
+
+// ImportAliasesCSV reads entries from CSV (header alias[,mimics][,suffix],
+// comma- or semicolon-delimited) and appends them to the pool serving domain,
+// or replaces its entries when replace is set.
+func (s *Service) ImportAliasesCSV(domain string, r io.Reader, replace bool) ([]dict.AliasEntry, error) {
+	entries, err := parseAliasCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]any{"domain": domain, "count": len(entries), "replace": replace}
+	return s.editAliases("alias.import", domain, params,
+		func(current []dict.AliasEntry) ([]dict.AliasEntry, error) {
+			if replace {
+				current = nil
+			}
+			return appendAliases(current, entries)
+		})
+}
+
+// ReorderAliases reorders the pool serving domain; order must list every
+// alias of the pool exactly once.
+func (s *Service) ReorderAliases(domain string, order []string) ([]dict.AliasEntry, error) {
+	return s.editAliases("alias.reorder", domain, map[string]any{"domain": domain, "order": order},
+		func(current []dict.AliasEntry) ([]dict.AliasEntry, error) {
+			if len(order) != len(current) {
+				return nil, fmt.Errorf("%w: order lists %d aliases, pool has %d", errInvalidAliases, len(order), len(current))
+			}
+			byAlias := make(map[string]dict.AliasEntry, len(current))
+			for _, e := range current {
+				byAlias[e.Alias] = e
+			}
+			out := make([]dict.AliasEntry, 0, len(order))
+			for _, a := range order {
+				e, ok := byAlias[a]
+				if !ok {
+					return nil, fmt.Errorf("%w: %q is not in the pool or listed twice", errInvalidAliases, a)
+				}
+				delete(byAlias, a)
+				out = append(out, e)
+			}
+			return out, nil
+		})
+}
+
+// editAliases runs edit through the registry, which persists and reloads the
+// pool, then records the change in the audit log.
+func (s *Service) editAliases(action, domain string, params any, edit func([]dict.AliasEntry) ([]dict.AliasEntry, error)) ([]dict.AliasEntry, error) {
+	if s.reg == nil {
+		return nil, errNoRegistry
+	}
+	if _, ok := s.reg.AliasPoolID(domain); !ok {
+		return nil, errAliasPoolNotFound
+	}
+	id, entries, err := s.reg.EditAliasPool(domain, edit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the dict_registry row in step; the audit entry below covers it.
+	_, _ = s.db.Exec(`UPDATE dict_registry SET entry_count = ?, updated_at = ? WHERE id = ?`,
+		len(entries), time.Now().Unix(), id)
+	s.logAudit(action, id, params)
+	return entries, nil
+}
+
+// appendAliases appends add to current, rejecting empty aliases and aliases
+// already present (compared case- and accent-insensitively).
+func appendAliases(current, add []dict.AliasEntry) ([]dict.AliasEntry, error) {
+	seen := make(map[string]bool, len(current)+len(add))
+	for _, e := range current {
+		seen[dict.NormalizeLowercaseASCII(e.Alias)] = true
+	}
+	for _, e := range add {
+		e.Alias = strings.TrimSpace(e.Alias)
+		if e.Alias == "" {
+			return nil, fmt.Errorf("%w: empty alias", errInvalidAliases)
+		}
+		key := dict.NormalizeLowercaseASCII(e.Alias)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %q already in the pool", errInvalidAliases, e.Alias)
+		}
+		seen[key] = true
+		// Criblage flags are recorded by the server, not supplied.
+		e.Collisions, e.Duplicate = nil, false
+		current = append(current, e)
+	}
+	return current, nil
+}
+
+func parseAliasCSV(r io.Reader) ([]dict.AliasEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	cr := csv.NewReader(strings.NewReader(string(data)))
+	first, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: read csv header: %v", errInvalidAliases, err)
+	}
+	col := map[string]int{"alias": -1, "mimics": -1, "suffix": -1}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := col[h]; ok {
+			col[h] = i
+		}
+	}
+	if col["alias"] < 0 {
+		return nil, fmt.Errorf("%w: csv header has no alias column", errInvalidAliases)
+	}
+
+	field := func(rec []string, name string) string {
+		if i := col[name]; i >= 0 && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	var entries []dict.AliasEntry
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: read csv: %v", errInvalidAliases, err)
+		}
+		if field(rec, "alias") == "" {
+			continue
+		}
+		entries = append(entries, dict.AliasEntry{
+			Alias:  field(rec, "alias"),
+			Mimics: field(rec, "mimics"),
+			Suffix: field(rec, "suffix"),
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,224 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hazyhaar/pkg/audit"
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// recordingAudit keeps audit entries in memory.
+type recordingAudit struct {
+	mu      sync.Mutex
+	entries []*audit.Entry
+}
+
+func (a *recordingAudit) Log(_ context.Context, e *audit.Entry) error {
+	a.LogAsync(e)
+	return nil
+}
+
+func (a *recordingAudit) LogAsync(e *audit.Entry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, e)
+}
+
+func (a *recordingAudit) Close() error { return nil }
+
+func (a *recordingAudit) actions() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var out []string
+	for _, e := range a.entries {
+		out = append(out, e.Action)
+	}
+	return out
+}
+
+func setupAliasService(t *testing.T) (*Service, *recordingAudit, string) {
+	t.Helper()
+	dir := t.TempDir()
+	poolDir := filepath.Join(dir, "pharma-aliases")
+	if err := os.MkdirAll(poolDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `id: pharma-aliases
+version: "1.0"
+jurisdiction: fr
+entity_type: alias
+source: manual
+type: alias_pool
+domain: pharma
+next_criblage: "2026-04-01"
+entries:
+  - alias: "ALDORA"
+    mimics: "SANOFI"
+  - alias: "NOVALIS"
+`
+	if err := os.WriteFile(filepath.Join(poolDir, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	rec := &recordingAudit{}
+	svc := NewService(setupTestDB(t), rec)
+	svc.SetRegistry(reg)
+	return svc, rec, poolDir
+}
+
+func aliasNames(entries []dict.AliasEntry) string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Alias
+	}
+	return strings.Join(names, ",")
+}
+
+func TestAliasService_Edits(t *testing.T) {
+	svc, rec, poolDir := setupAliasService(t)
+
+	entries, err := svc.AddAliases("pharma", []dict.AliasEntry{{Alias: "VERANCE", Suffix: "SA"}})
+	if err != nil {
+		t.Fatalf("AddAliases: %v", err)
+	}
+	if got := aliasNames(entries); got != "ALDORA,NOVALIS,VERANCE" {
+		t.Errorf("after add = %s", got)
+	}
+	if _, err := svc.AddAliases("pharma", []dict.AliasEntry{{Alias: "aldora"}}); err == nil {
+		t.Error("expected error for an alias already in the pool")
+	}
+
+	if entries, err = svc.ReorderAliases("pharma", []string{"VERANCE", "ALDORA", "NOVALIS"}); err != nil {
+		t.Fatalf("ReorderAliases: %v", err)
+	}
+	if got := aliasNames(entries); got != "VERANCE,ALDORA,NOVALIS" {
+		t.Errorf("after reorder = %s", got)
+	}
+	if _, err := svc.ReorderAliases("pharma", []string{"VERANCE", "VERANCE", "NOVALIS"}); err == nil {
+		t.Error("expected error for an order that is not a permutation")
+	}
+
+	if entries, err = svc.RemoveAlias("pharma", "ALDORA"); err != nil {
+		t.Fatalf("RemoveAlias: %v", err)
+	}
+	if got := aliasNames(entries); got != "VERANCE,NOVALIS" {
+		t.Errorf("after remove = %s", got)
+	}
+
+	// The live registry serves the new pool.
+	if got := aliasNames(svc.reg.GetAliases("pharma")); got != "VERANCE,NOVALIS" {
+		t.Errorf("registry pool = %s", got)
+	}
+
+	// Persisted, screened on every edit.
+	m, err := dict.LoadManifest(filepath.Join(poolDir, "manifest.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := aliasNames(m.AliasEntries); got != "VERANCE,NOVALIS" || m.AliasEntries[0].Suffix != "SA" {
+		t.Errorf("manifest entries = %+v", m.AliasEntries)
+	}
+	if m.LastCriblage == "" || m.NextCriblage == "" || m.Domain != "pharma" {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	if got := strings.Join(rec.actions(), ","); got != "alias.add,alias.reorder,alias.remove" {
+		t.Errorf("audit actions = %s", got)
+	}
+}
+
+func TestAliasService_ImportCSV(t *testing.T) {
+	svc, rec, _ := setupAliasService(t)
+
+	csvData := "alias;mimics;suffix\nVERANCE;SERVIER;SAS\n\nPHARMEX;;\n"
+	entries, err := svc.ImportAliasesCSV("pharma", strings.NewReader(csvData), false)
+	if err != nil {
+		t.Fatalf("ImportAliasesCSV: %v", err)
+	}
+	if got := aliasNames(entries); got != "ALDORA,NOVALIS,VERANCE,PHARMEX" {
+		t.Errorf("after append = %s", got)
+	}
+	if entries[2].Mimics != "SERVIER" || entries[2].Suffix != "SAS" {
+		t.Errorf("entry = %+v", entries[2])
+	}
+
+	entries, err = svc.ImportAliasesCSV("pharma", strings.NewReader("suffix,alias\nSA,ORVALE\n"), true)
+	if err != nil {
+		t.Fatalf("ImportAliasesCSV replace: %v", err)
+	}
+	if got := aliasNames(entries); got != "ORVALE" || entries[0].Suffix != "SA" {
+		t.Errorf("after replace = %+v", entries)
+	}
+
+	if _, err := svc.ImportAliasesCSV("pharma", strings.NewReader("name\nX\n"), false); err == nil {
+		t.Error("expected error for a CSV without alias column")
+	}
+	if got := strings.Join(rec.actions(), ","); got != "alias.import,alias.import" {
+		t.Errorf("audit actions = %s", got)
+	}
+}
+
+func TestAdminRouter_Aliases(t *testing.T) {
+	svc, _, _ := setupAliasService(t)
+	router := NewRouter(svc, "test-token")
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/admin/v1/aliases/pharma", `{"entries":[{"alias":"VERANCE"}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("add status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("PUT", "/admin/v1/aliases/pharma/order", `{"aliases":["NOVALIS","VERANCE","ALDORA"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("reorder status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("DELETE", "/admin/v1/aliases/pharma/VERANCE", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("remove status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("POST", "/admin/v1/aliases/pharma/import", "alias\nORVALE\n")
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do("GET", "/admin/v1/aliases/pharma", "")
+	var resp aliasesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if got := aliasNames(resp.Aliases); got != "NOVALIS,ALDORA,ORVALE" {
+		t.Errorf("pool = %s", got)
+	}
+
+	if w := do("DELETE", "/admin/v1/aliases/pharma/NOPE", ""); w.Code != http.StatusNotFound {
+		t.Errorf("remove unknown: status = %d, want 404", w.Code)
+	}
+	if w := do("GET", "/admin/v1/aliases/finance", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown pool: status = %d, want 404", w.Code)
+	}
+	if w := do("POST", "/admin/v1/aliases/pharma", `{"entries":[{"alias":""}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("empty alias: status = %d, want 400", w.Code)
+	}
+}
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY HTTP handlers for admin API CRUD operations on dicts, sources, import runs, and audit log.
+// CLAUDE:SUMMARY HTTP handlers for admin API CRUD operations on dicts, alias pools, sources, import runs (trigger, cancel, SSE progress), and audit log.
 // CLAUDE:DEPENDS pkg/admin/service.go, pkg/admin/auth.go
 // CLAUDE:EXPORTS NewRouter
 
@@ -7,7 +7,13 @@ package admin
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/importer"
 )
 
 // NewRouter returns an http.Handler with all admin API routes, protected by bearer auth.
@@ -21,16 +27,27 @@ func NewRouter(svc *Service, token string) http.Handler {
 	mux.HandleFunc("GET /admin/v1/dicts/{id}", h.getDict)
 	mux.HandleFunc("PATCH /admin/v1/dicts/{id}", h.updateDict)
 	mux.HandleFunc("DELETE /admin/v1/dicts/{id}", h.deleteDict)
+	mux.HandleFunc("POST /admin/v1/dicts/{id}/rollback", h.rollbackDict)
+
+	// Alias pools
+	mux.HandleFunc("GET /admin/v1/aliases/{domain}", h.listAliases)
+	mux.HandleFunc("POST /admin/v1/aliases/{domain}", h.addAliases)
+	mux.HandleFunc("POST /admin/v1/aliases/{domain}/import", h.importAliases)
+	mux.HandleFunc("PUT /admin/v1/aliases/{domain}/order", h.reorderAliases)
+	mux.HandleFunc("DELETE /admin/v1/aliases/{domain}/{alias}", h.removeAlias)
 
 	// Sources
 	mux.HandleFunc("POST /admin/v1/sources", h.createSource)
 	mux.HandleFunc("GET /admin/v1/sources", h.listSources)
 	mux.HandleFunc("PATCH /admin/v1/sources/{id}", h.updateSource)
 	mux.HandleFunc("DELETE /admin/v1/sources/{id}", h.deleteSource)
+	mux.HandleFunc("POST /admin/v1/sources/{id}/import", h.triggerImport)
 
 	// Imports
 	mux.HandleFunc("GET /admin/v1/imports", h.listImportRuns)
 	mux.HandleFunc("GET /admin/v1/imports/{id}", h.getImportRun)
+	mux.HandleFunc("GET /admin/v1/imports/{id}/events", h.importEvents)
+	mux.HandleFunc("POST /admin/v1/imports/{id}/cancel", h.cancelImport)
 
 	// Health
 	mux.HandleFunc("GET /admin/v1/health", h.health)
@@ -110,6 +127,107 @@ func (h *adminHandler) deleteDict(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "archived"})
 }
 
+func (h *adminHandler) rollbackDict(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.RollbackDict(r.PathValue("id")); err != nil {
+		writeImportError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "rolled back"})
+}
+
+// --- Alias pools ---
+
+type aliasesResponse struct {
+	Domain  string            `json:"domain"`
+	Aliases []dict.AliasEntry `json:"aliases"`
+}
+
+func (h *adminHandler) listAliases(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	entries, err := h.svc.ListAliases(domain)
+	if err != nil {
+		writeAliasError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, aliasesResponse{Domain: domain, Aliases: entries})
+}
+
+func (h *adminHandler) addAliases(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Entries []dict.AliasEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if len(req.Entries) == 0 {
+		writeError(w, http.StatusBadRequest, "entries is required")
+		return
+	}
+
+	domain := r.PathValue("domain")
+	entries, err := h.svc.AddAliases(domain, req.Entries)
+	if err != nil {
+		writeAliasError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, aliasesResponse{Domain: domain, Aliases: entries})
+}
+
+// importAliases takes a CSV body; ?mode=replace replaces the pool instead of
+// appending to it.
+func (h *adminHandler) importAliases(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	replace := r.URL.Query().Get("mode") == "replace"
+	entries, err := h.svc.ImportAliasesCSV(domain, http.MaxBytesReader(w, r.Body, 8<<20), replace)
+	if err != nil {
+		writeAliasError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, aliasesResponse{Domain: domain, Aliases: entries})
+}
+
+func (h *adminHandler) reorderAliases(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	domain := r.PathValue("domain")
+	entries, err := h.svc.ReorderAliases(domain, req.Aliases)
+	if err != nil {
+		writeAliasError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, aliasesResponse{Domain: domain, Aliases: entries})
+}
+
+func (h *adminHandler) removeAlias(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	entries, err := h.svc.RemoveAlias(domain, r.PathValue("alias"))
+	if err != nil {
+		writeAliasError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, aliasesResponse{Domain: domain, Aliases: entries})
+}
+
+func writeAliasError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errAliasPoolNotFound), errors.Is(err, errAliasNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errInvalidAliases):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errNoRegistry):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 // --- Sources ---
 
 func (h *adminHandler) createSource(w http.ResponseWriter, r *http.Request) {
@@ -125,7 +243,7 @@ func (h *adminHandler) createSource(w http.ResponseWriter, r *http.Request) {
 
 	rec, err := h.svc.CreateSource(req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeSourceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, rec)
@@ -150,7 +268,7 @@ func (h *adminHandler) updateSource(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svc.UpdateSource(id, req); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeSourceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]string{"status": "updated"})
@@ -165,8 +283,113 @@ func (h *adminHandler) deleteSource(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+func writeSourceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errInvalidGates) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
 // --- Imports ---
 
+func (h *adminHandler) triggerImport(w http.ResponseWriter, r *http.Request) {
+	rec, err := h.svc.TriggerImport(r.PathValue("id"))
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, rec)
+}
+
+func (h *adminHandler) cancelImport(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.CancelImport(r.PathValue("id")); err != nil {
+		writeImportError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "cancelling"})
+}
+
+// importEventsInterval is how often importEvents sends the progress of a
+// running import.
+var importEventsInterval = 500 * time.Millisecond
+
+// importEvents streams the progress of an import run as server-sent events:
+// "progress" events while it runs, then one "done" event with the run record.
+func (h *adminHandler) importEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	p, done, live := h.svc.ImportProgress(id)
+	if !live {
+		if _, err := h.svc.GetImportRun(id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				writeError(w, http.StatusNotFound, "import run not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	send := func(event string, v any) bool {
+		data, _ := json.Marshal(v)
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if live {
+		ticker := time.NewTicker(importEventsInterval)
+		defer ticker.Stop()
+		var sent ImportProgress
+		for done != nil {
+			if p != sent {
+				if !send("progress", p) {
+					return
+				}
+				sent = p
+			}
+			select {
+			case <-r.Context().Done():
+				return
+			case <-done:
+				done = nil
+			case <-ticker.C:
+				if np, _, ok := h.svc.ImportProgress(id); ok {
+					p = np
+				}
+			}
+		}
+	}
+	rec, err := h.svc.GetImportRun(id)
+	if err != nil {
+		send("error", map[string]string{"error": err.Error()})
+		return
+	}
+	send("done", rec)
+}
+
+func writeImportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "source not found")
+	case errors.Is(err, errRunNotRunning), errors.Is(err, importer.ErrNoPrevious):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errNoAdapter):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errImportRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, errNoScheduler):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *adminHandler) listImportRuns(w http.ResponseWriter, r *http.Request) {
 	sourceID := r.URL.Query().Get("source_id")
 	dictID := r.URL.Query().Get("dict_id")
@@ -103,7 +103,11 @@ func (p *panelHandler) dictDetail(w http.ResponseWriter, r *http.Request) {
 		runViews = append(runViews, importRunToView(run))
 	}
 
-	renderTempl(w, r, templates.DictDetail(dictToView(*rec), sourceViews, runViews))
+	view := dictToView(*rec)
+	if rec.Provenance != nil {
+		view.Provenance = provenanceToView(rec.Provenance)
+	}
+	renderTempl(w, r, templates.DictDetail(view, sourceViews, runViews))
 }
 
 func (p *panelHandler) sourcesList(w http.ResponseWriter, r *http.Request) {
@@ -169,6 +173,31 @@ func dictToView(rec DictRecord) templates.DictView {
 	}
 }
 
+func provenanceToView(p *dict.Provenance) *templates.ProvenanceView {
+	v := &templates.ProvenanceView{
+		Adapter:        p.Adapter,
+		AdapterVersion: p.AdapterVersion,
+		Mode:           p.Mode,
+		SourceDate:     p.SourceDate.Format("2006-01-02 15:04"),
+		StartedAt:      p.StartedAt.Format("2006-01-02 15:04:05"),
+		FinishedAt:     p.FinishedAt.Format("2006-01-02 15:04:05"),
+		Read:           p.Rows.Read,
+		Filtered:       p.Rows.Filtered,
+		Kept:           p.Rows.Kept,
+		Collided:       p.Rows.Collided,
+	}
+	for _, dl := range p.Downloads {
+		v.Downloads = append(v.Downloads, templates.DownloadView{
+			URL:          dl.URL,
+			ResolvedURL:  dl.ResolvedURL,
+			SHA256:       dl.SHA256,
+			LastModified: dl.LastModified,
+			Bytes:        dl.Bytes,
+		})
+	}
+	return v
+}
+
 func sourceToView(rec SourceRecord) templates.SourceView {
 	lastStatus := ""
 	if rec.LastStatus != nil {
@@ -0,0 +1,406 @@
+// CLAUDE:SUMMARY Background import scheduler: runs the imports of enabled sources whose update_frequency (or their dictionary's) has elapsed, with a concurrency limit, records each run in import_runs and hot-reloads the rebuilt dictionary; also runs admin-triggered imports, tracks their live progress, cancels them and rolls dictionaries back.
+// CLAUDE:DEPENDS pkg/admin/service.go, pkg/importer/run.go, pkg/dict/registry.go
+// CLAUDE:EXPORTS Scheduler, NewScheduler, ImportProgress, TriggerImport, CancelImport, RollbackDict
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/importer"
+)
+
+var (
+	errNoScheduler   = errors.New("import scheduler not running")
+	errImportRunning = errors.New("an import of this dictionary is already running")
+	errNoAdapter     = errors.New("source has no import adapter")
+	errRunNotRunning = errors.New("import run not running")
+	errCancelled     = errors.New("import cancelled")
+	errInvalidGates  = errors.New("invalid quality gates")
+)
+
+// failedRetryDelay is the minimum delay before a failed scheduled import is
+// retried.
+const failedRetryDelay = time.Hour
+
+// Outcomes of an import, stored in sources.last_import_status.
+const (
+	importSuccess   = "success"
+	importUnchanged = "unchanged"
+	importFailed    = "failed"
+	importRejected  = "rejected" // the new build failed its quality gates
+	importCancelled = "cancelled"
+)
+
+// importStatus classifies the outcome of an import.
+func importStatus(err error, unchanged bool) string {
+	switch {
+	case errors.Is(err, errCancelled):
+		return importCancelled
+	case errors.Is(err, importer.ErrGateFailed):
+		return importRejected
+	case err != nil:
+		return importFailed
+	case unchanged:
+		return importUnchanged
+	}
+	return importSuccess
+}
+
+// Scheduler runs source imports in the background. A source is due when it
+// is enabled, has an adapter, and the interval of its update_frequency — or,
+// when empty, of its dictionary manifest's — has elapsed since its last
+// import. A source never imported counts from its dictionary's manifest file.
+type Scheduler struct {
+	svc      *Service
+	sources  *importer.SourceDB
+	dictsDir string
+	logger   *slog.Logger
+	sem      chan struct{} // bounds concurrent imports
+
+	mu      sync.Mutex
+	ctx     context.Context     // imports outlive the request triggering them
+	running map[string]bool     // dictionary IDs being imported
+	live    map[string]*liveRun // running imports by run ID
+	wg      sync.WaitGroup
+}
+
+// ImportProgress is the live progress of a running import.
+type ImportProgress struct {
+	RunID      string `json:"run_id"`
+	URL        string `json:"url,omitempty"` // file being downloaded
+	BytesDone  int64  `json:"bytes_done"`
+	BytesTotal int64  `json:"bytes_total"` // -1 when unknown
+	Rows       int    `json:"rows"`        // entries written by the parser
+	Entries    int    `json:"entries"`     // distinct keys stored
+}
+
+// liveRun is the state of a running import.
+type liveRun struct {
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the run is recorded
+
+	mu       sync.Mutex
+	progress ImportProgress
+}
+
+func (l *liveRun) snapshot() ImportProgress {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.progress
+}
+
+func (l *liveRun) download(url string, done, total int64) {
+	l.mu.Lock()
+	l.progress.URL, l.progress.BytesDone, l.progress.BytesTotal = url, done, total
+	l.mu.Unlock()
+}
+
+func (l *liveRun) entries(rows, entries int) {
+	l.mu.Lock()
+	l.progress.Rows, l.progress.Entries = rows, entries
+	l.mu.Unlock()
+}
+
+// NewScheduler creates a Scheduler importing into dictsDir, running at most
+// concurrency imports at once, and attaches it to svc for the admin API.
+func NewScheduler(svc *Service, sources *importer.SourceDB, dictsDir string, logger *slog.Logger, concurrency int) *Scheduler {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s := &Scheduler{
+		svc:      svc,
+		sources:  sources,
+		dictsDir: dictsDir,
+		logger:   logger,
+		sem:      make(chan struct{}, concurrency),
+		ctx:      context.Background(),
+		running:  make(map[string]bool),
+		live:     make(map[string]*liveRun),
+	}
+	svc.sched = s
+	return s
+}
+
+// Start runs due imports immediately, then every interval until ctx is
+// cancelled, and waits for the imports still running.
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue starts the imports of the sources due at now and returns their runs.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) []*ImportRunRecord {
+	sources, err := s.svc.ListSources("")
+	if err != nil {
+		s.logger.Error("import scheduler: list sources", "error", err)
+		return nil
+	}
+	var runs []*ImportRunRecord
+	for i := range sources {
+		src := &sources[i]
+		if !s.due(src, now) {
+			continue
+		}
+		run, err := s.start(ctx, src)
+		if err != nil {
+			if !errors.Is(err, errImportRunning) {
+				s.logger.Error("import scheduler: start", "source", src.ID, "adapter", src.AdapterID, "error", err)
+			}
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// Wait blocks until the running imports finish.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// TriggerImport starts an import of a source now, whether or not it is due
+// or enabled.
+func (s *Service) TriggerImport(sourceID string) (*ImportRunRecord, error) {
+	if s.sched == nil {
+		return nil, errNoScheduler
+	}
+	src, err := s.GetSource(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	s.sched.mu.Lock()
+	ctx := s.sched.ctx
+	s.sched.mu.Unlock()
+	run, err := s.sched.start(ctx, src)
+	if err == nil {
+		s.logAudit("import.trigger", run.ID, map[string]string{"source_id": sourceID})
+	}
+	return run, err
+}
+
+// RollbackDict puts the previous build of a dictionary back in place and
+// reloads it. The build it replaces becomes the previous one.
+func (s *Service) RollbackDict(dictID string) error {
+	if s.sched == nil {
+		return errNoScheduler
+	}
+	sc := s.sched
+	sc.mu.Lock()
+	if sc.running[dictID] {
+		sc.mu.Unlock()
+		return errImportRunning
+	}
+	sc.running[dictID] = true
+	sc.mu.Unlock()
+	defer sc.done(dictID, "")
+
+	if err := importer.Rollback(sc.dictsDir, dictID); err != nil {
+		return err
+	}
+	if err := sc.reload(dictID); err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+	s.logAudit("dict.rollback", dictID, nil)
+	return nil
+}
+
+// CancelImport cancels a running import. The run is recorded as failed.
+func (s *Service) CancelImport(runID string) error {
+	if s.sched == nil {
+		return errNoScheduler
+	}
+	l := s.sched.liveRun(runID)
+	if l == nil {
+		return errRunNotRunning
+	}
+	l.cancel()
+	s.logAudit("import.cancel", runID, nil)
+	return nil
+}
+
+// ImportProgress returns the progress of a running import, and a channel
+// closed once the run is recorded. ok is false when the run is not running.
+func (s *Service) ImportProgress(runID string) (p ImportProgress, done <-chan struct{}, ok bool) {
+	if s.sched == nil {
+		return p, nil, false
+	}
+	l := s.sched.liveRun(runID)
+	if l == nil {
+		return p, nil, false
+	}
+	return l.snapshot(), l.done, true
+}
+
+func (s *Scheduler) liveRun(runID string) *liveRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.live[runID]
+}
+
+func (s *Scheduler) due(src *SourceRecord, now time.Time) bool {
+	if !src.Enabled || src.AdapterID == "" {
+		return false
+	}
+	interval := dict.UpdateInterval(src.UpdateFrequency)
+	if interval == 0 && s.svc.reg != nil {
+		if d, ok := s.svc.reg.Get(src.DictID); ok {
+			interval = dict.UpdateInterval(d.Manifest.UpdateFrequency)
+		}
+	}
+	if interval == 0 {
+		return false
+	}
+
+	var last time.Time
+	if src.LastImport != nil {
+		last = time.Unix(*src.LastImport, 0)
+	} else if fi, err := os.Stat(filepath.Join(s.dictsDir, src.DictID, "manifest.yaml")); err == nil {
+		last = fi.ModTime()
+	}
+	if now.Before(last.Add(interval)) {
+		return false
+	}
+	failed := s.svc.lastFailedRun(src.ID)
+	return failed == 0 || !now.Before(time.Unix(failed, 0).Add(min(failedRetryDelay, interval)))
+}
+
+// start records a run and imports src in the background, at most one import
+// per dictionary at a time.
+func (s *Scheduler) start(ctx context.Context, src *SourceRecord) (*ImportRunRecord, error) {
+	a, err := importer.Get(src.AdapterID)
+	if src.AdapterID == "" || err != nil {
+		return nil, fmt.Errorf("%w: %q", errNoAdapter, src.AdapterID)
+	}
+
+	s.mu.Lock()
+	if s.running[src.DictID] {
+		s.mu.Unlock()
+		return nil, errImportRunning
+	}
+	s.running[src.DictID] = true
+	s.mu.Unlock()
+
+	run, err := s.svc.CreateImportRun(src.ID, src.DictID)
+	if err != nil {
+		s.done(src.DictID, "")
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	l := &liveRun{cancel: cancel, done: make(chan struct{}), progress: ImportProgress{RunID: run.ID, BytesTotal: -1}}
+	s.mu.Lock()
+	s.live[run.ID] = l
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(l.done)
+		defer s.done(src.DictID, run.ID)
+		defer cancel()
+		select {
+		case s.sem <- struct{}{}:
+			defer func() { <-s.sem }()
+		case <-ctx.Done():
+			_ = s.svc.FinishImportRun(run.ID, 0, errCancelled)
+			_ = s.svc.recordImport(src.ID, time.Now().Unix(), 0, importCancelled, errCancelled)
+			return
+		}
+		s.execute(ctx, src, a, run, l)
+	}()
+	return run, nil
+}
+
+func (s *Scheduler) done(dictID, runID string) {
+	s.mu.Lock()
+	delete(s.running, dictID)
+	delete(s.live, runID)
+	s.mu.Unlock()
+}
+
+// execute imports src and records the outcome of run.
+func (s *Scheduler) execute(ctx context.Context, src *SourceRecord, a importer.Adapter, run *ImportRunRecord, l *liveRun) {
+	logger := s.logger.With("source", src.ID, "adapter", a.ID(), "dict", src.DictID, "run", run.ID)
+	logger.Info("import started")
+
+	err := s.syncURL(a.ID(), src.SourceURL)
+	if err == nil {
+		err = importer.Run(ctx, s.sources, a, s.dictsDir, importer.RunOptions{Progress: l.download, Entries: l.entries, Gates: src.QualityGates, Logger: logger})
+	}
+	unchanged := errors.Is(err, importer.ErrNotModified)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		err = errCancelled
+	case unchanged:
+		err = nil
+	case err == nil:
+		if err = s.reload(src.DictID); err != nil {
+			err = fmt.Errorf("reload: %w", err)
+		}
+	}
+
+	var count int
+	if s.svc.reg != nil {
+		if d, ok := s.svc.reg.Get(src.DictID); ok {
+			count = d.EntryCount()
+		}
+	}
+	if finishErr := s.svc.FinishImportRun(run.ID, count, err); finishErr != nil {
+		logger.Error("import run not recorded", "error", finishErr)
+	}
+	if recErr := s.svc.recordImport(src.ID, time.Now().Unix(), count, importStatus(err, unchanged), err); recErr != nil {
+		logger.Error("import not recorded", "error", recErr)
+	}
+	if err != nil {
+		logger.Error("import failed", "error", err)
+		return
+	}
+	logger.Info("import finished", "unchanged", unchanged, "entries", count)
+}
+
+// syncURL makes the import use the admin source URL when one is set.
+func (s *Scheduler) syncURL(adapterID, url string) error {
+	if url == "" {
+		return nil
+	}
+	cur, err := s.sources.GetURL(adapterID)
+	if err != nil || cur == url {
+		return err
+	}
+	return s.sources.SetURL(adapterID, url)
+}
+
+// reload hot-reloads the rebuilt dictionary, or loads it when new.
+func (s *Scheduler) reload(dictID string) error {
+	reg := s.svc.reg
+	if reg == nil {
+		return nil
+	}
+	if _, ok := reg.Get(dictID); ok {
+		return reg.ReloadDict(dictID)
+	}
+	return reg.Reload()
+}
@@ -0,0 +1,466 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/importer"
+)
+
+// csvAdapter writes a one-column CSV dictionary of its current terms.
+type csvAdapter struct {
+	mu      sync.Mutex
+	terms   []string
+	fail    error
+	urls    []string
+	blocked chan struct{} // when set, Import signals it and waits for cancellation
+}
+
+func (a *csvAdapter) ID() string          { return "test-sched" }
+func (a *csvAdapter) DictID() string      { return "sched-dict" }
+func (a *csvAdapter) Description() string { return "scheduler test" }
+func (a *csvAdapter) DefaultURL() string  { return "https://example.test/default.csv" }
+func (a *csvAdapter) License() string     { return "CC0" }
+
+func (a *csvAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.urls = append(a.urls, sourceURL)
+	if a.blocked != nil {
+		close(a.blocked)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if a.fail != nil {
+		return a.fail
+	}
+	dir := filepath.Join(outputDir, a.DictID())
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	manifest := "id: sched-dict\nentity_type: thing\ndata_file: data.csv\nformat:\n  has_header: true\n  key_column: term\n"
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "data.csv"), []byte("term\n"+strings.Join(a.terms, "\n")+"\n"), 0o644)
+}
+
+func setupScheduler(t *testing.T) (*Scheduler, *csvAdapter, *dict.Registry, *SourceRecord) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(dir, "admin.db")+"?_pragma=journal_mode(wal)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := InitSchema(db); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(db, nil)
+
+	a := &csvAdapter{terms: []string{"alpha"}}
+	importer.Register(a)
+	sdb, err := importer.OpenSourceDB(filepath.Join(dir, "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sdb.Close() })
+	if err := sdb.Seed([]importer.Adapter{a}); err != nil {
+		t.Fatal(err)
+	}
+
+	dictsDir := filepath.Join(dir, "dicts")
+	if err := os.MkdirAll(dictsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(dictsDir)
+	if err := reg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reg.Close() })
+	svc.SetRegistry(reg)
+
+	if _, err := svc.CreateDict(CreateDictRequest{ID: "sched-dict", Type: "registry"}); err != nil {
+		t.Fatal(err)
+	}
+	src, err := svc.CreateSource(CreateSourceRequest{
+		DictID:          "sched-dict",
+		AdapterID:       a.ID(),
+		SourceURL:       "https://example.test/admin.csv",
+		UpdateFrequency: "daily",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewScheduler(svc, sdb, dictsDir, logger, 1), a, reg, src
+}
+
+func TestInitSchema_AddsEnabled(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	old := strings.Replace(Schema, "    enabled INTEGER NOT NULL DEFAULT 1,\n", "", 1)
+	if old == Schema {
+		t.Fatal("enabled column not found in Schema")
+	}
+	if _, err := db.Exec(old); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 { // idempotent
+		if err := InitSchema(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewService(db, nil)
+	if _, err := svc.CreateDict(CreateDictRequest{ID: "d", Type: "registry"}); err != nil {
+		t.Fatal(err)
+	}
+	src, err := svc.CreateSource(CreateSourceRequest{DictID: "d", SourceURL: "https://example.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetSource(src.ID)
+	if err != nil || !got.Enabled {
+		t.Errorf("GetSource = %+v, %v", got, err)
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	s, a, reg, src := setupScheduler(t)
+	now := time.Now()
+
+	// Never imported and no dictionary on disk: due at once.
+	runs := s.RunDue(context.Background(), now)
+	if len(runs) != 1 || runs[0].SourceID != src.ID {
+		t.Fatalf("RunDue = %+v", runs)
+	}
+	s.Wait()
+
+	run, err := s.svc.GetImportRun(runs[0].ID)
+	if err != nil || run.Status != "success" || run.EntryCount != 1 {
+		t.Fatalf("run = %+v, %v", run, err)
+	}
+	if _, ok := reg.Get("sched-dict"); !ok {
+		t.Fatal("new dictionary not loaded")
+	}
+	if len(a.urls) != 1 || a.urls[0] != src.SourceURL {
+		t.Errorf("imported from %v, want the admin source URL", a.urls)
+	}
+	got, _ := s.svc.GetSource(src.ID)
+	if got.LastImport == nil || got.LastImportCount == nil || *got.LastImportCount != 1 ||
+		got.LastImportStatus != importSuccess || got.LastImportError != nil {
+		t.Errorf("source = %+v", got)
+	}
+
+	// Imported just now: not due until a day has passed.
+	if runs := s.RunDue(context.Background(), now); len(runs) != 0 {
+		t.Errorf("RunDue right after an import = %+v", runs)
+	}
+
+	// A day later: due again, and the loaded dictionary is hot-reloaded.
+	a.terms = []string{"alpha", "beta"}
+	if runs := s.RunDue(context.Background(), now.Add(25*time.Hour)); len(runs) != 1 {
+		t.Fatalf("RunDue a day later = %+v", runs)
+	}
+	s.Wait()
+	d, _ := reg.Get("sched-dict")
+	if _, ok := d.Lookup("beta"); !ok {
+		t.Error("dictionary not reloaded")
+	}
+
+	// Disabled: never due.
+	off := false
+	if err := s.svc.UpdateSource(src.ID, UpdateSourceRequest{Enabled: &off}); err != nil {
+		t.Fatal(err)
+	}
+	if runs := s.RunDue(context.Background(), now.Add(30*24*time.Hour)); len(runs) != 0 {
+		t.Errorf("RunDue of a disabled source = %+v", runs)
+	}
+}
+
+func TestScheduler_FailedRetry(t *testing.T) {
+	s, a, _, _ := setupScheduler(t)
+	a.fail = errors.New("source down")
+	now := time.Now()
+
+	runs := s.RunDue(context.Background(), now)
+	if len(runs) != 1 {
+		t.Fatalf("RunDue = %+v", runs)
+	}
+	s.Wait()
+	run, _ := s.svc.GetImportRun(runs[0].ID)
+	if run.Status != "failed" || run.Error == nil || !strings.Contains(*run.Error, "source down") {
+		t.Fatalf("run = %+v", run)
+	}
+	src, _ := s.svc.GetSource(runs[0].SourceID)
+	if src.LastImportStatus != importFailed || src.LastImportError == nil || !strings.Contains(*src.LastImportError, "source down") || src.LastImport != nil {
+		t.Errorf("source = %+v", src)
+	}
+
+	if runs := s.RunDue(context.Background(), now.Add(10*time.Minute)); len(runs) != 0 {
+		t.Errorf("retried too early: %+v", runs)
+	}
+	if runs := s.RunDue(context.Background(), now.Add(failedRetryDelay+time.Minute)); len(runs) != 1 {
+		t.Errorf("not retried after %v: %+v", failedRetryDelay, runs)
+	}
+	s.Wait()
+}
+
+func TestAdminRouter_TriggerImport(t *testing.T) {
+	s, _, reg, src := setupScheduler(t)
+	off := false
+	if err := s.svc.UpdateSource(src.ID, UpdateSourceRequest{Enabled: &off}); err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(s.svc, "tok")
+	post := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/admin/v1/sources/%s/import", id), nil)
+		req.Header.Set("Authorization", "Bearer tok")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A disabled source can still be imported on demand.
+	if rec := post(src.ID); rec.Code != http.StatusAccepted {
+		t.Fatalf("trigger: status %d: %s", rec.Code, rec.Body)
+	}
+	s.Wait()
+	if _, ok := reg.Get("sched-dict"); !ok {
+		t.Error("dictionary not loaded")
+	}
+
+	if rec := post("missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing source: status %d", rec.Code)
+	}
+
+	s.mu.Lock()
+	s.running["sched-dict"] = true
+	s.mu.Unlock()
+	if rec := post(src.ID); rec.Code != http.StatusConflict {
+		t.Errorf("running import: status %d", rec.Code)
+	}
+	s.done("sched-dict", "")
+
+	noAdapter, err := s.svc.CreateSource(CreateSourceRequest{DictID: "sched-dict", SourceURL: "https://example.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := post(noAdapter.ID); rec.Code != http.StatusBadRequest {
+		t.Errorf("source without adapter: status %d", rec.Code)
+	}
+
+	s.svc.sched = nil
+	if rec := post(src.ID); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no scheduler: status %d", rec.Code)
+	}
+}
+
+func TestAdminRouter_ImportEvents(t *testing.T) {
+	s, a, _, src := setupScheduler(t)
+	a.blocked = make(chan struct{})
+	ts := httptest.NewServer(NewRouter(s.svc, "tok"))
+	defer ts.Close()
+	call := func(method, path string) *http.Response {
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer tok")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	run, err := s.svc.TriggerImport(src.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-a.blocked
+
+	resp := call(http.MethodGet, "/admin/v1/imports/"+run.ID+"/events")
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	events := bufio.NewScanner(resp.Body)
+	next := func() (event, data string) {
+		for events.Scan() {
+			line := events.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && event != "":
+				return event, data
+			}
+		}
+		return "", ""
+	}
+	if event, data := next(); event != "progress" || !strings.Contains(data, run.ID) {
+		t.Fatalf("first event = %s %s", event, data)
+	}
+
+	if r := call(http.MethodPost, "/admin/v1/imports/"+run.ID+"/cancel"); r.StatusCode != http.StatusAccepted {
+		t.Errorf("cancel: status %d", r.StatusCode)
+	}
+	event, data := next()
+	for event == "progress" {
+		event, data = next()
+	}
+	if event != "done" || !strings.Contains(data, `"status":"failed"`) || !strings.Contains(data, errCancelled.Error()) {
+		t.Fatalf("last event = %s %s", event, data)
+	}
+	s.Wait()
+	if got, _ := s.svc.GetSource(run.SourceID); got.LastImportStatus != importCancelled {
+		t.Errorf("cancelled import recorded as %q", got.LastImportStatus)
+	}
+
+	if r := call(http.MethodPost, "/admin/v1/imports/"+run.ID+"/cancel"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("cancel a finished run: status %d", r.StatusCode)
+	}
+	// A finished run streams its record at once.
+	done := call(http.MethodGet, "/admin/v1/imports/"+run.ID+"/events")
+	defer done.Body.Close()
+	events = bufio.NewScanner(done.Body)
+	if event, _ := next(); event != "done" {
+		t.Errorf("finished run: event = %q", event)
+	}
+	if r := call(http.MethodGet, "/admin/v1/imports/missing/events"); r.StatusCode != http.StatusNotFound {
+		t.Errorf("missing run: status %d", r.StatusCode)
+	}
+}
+
+func TestScheduler_QualityGates(t *testing.T) {
+	s, _, reg, _ := setupScheduler(t)
+	body := "name\nalpha\nbeta\ngamma\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	spec := "id: test-gated\ndict_id: gated\nurl: " + ts.URL + "\nkeys: [{expr: name}]\nmanifest: {entity_type: thing}\n"
+	if err := importer.RegisterSpec([]byte(spec)); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := importer.Get("test-gated")
+	if err := s.sources.Seed([]importer.Adapter{a}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.svc.CreateDict(CreateDictRequest{ID: "gated", Type: "registry"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.svc.CreateSource(CreateSourceRequest{DictID: "gated", SourceURL: ts.URL, QualityGates: &importer.Gates{MaxDrop: 2}}); !errors.Is(err, errInvalidGates) {
+		t.Errorf("invalid gates: err = %v", err)
+	}
+	src, err := s.svc.CreateSource(CreateSourceRequest{
+		DictID:       "gated",
+		AdapterID:    "test-gated",
+		SourceURL:    ts.URL,
+		QualityGates: &importer.Gates{MaxDrop: 0.5, RequiredKeys: []string{"beta"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.svc.GetSource(src.ID); got.QualityGates == nil || got.QualityGates.MaxDrop != 0.5 {
+		t.Fatalf("stored gates = %+v", got.QualityGates)
+	}
+	if g, err := s.svc.AdapterGates("test-gated"); err != nil || g == nil || g.MaxDrop != 0.5 {
+		t.Errorf("AdapterGates = %+v, %v", g, err)
+	}
+	if g, err := s.svc.AdapterGates("nope"); err != nil || g != nil {
+		t.Errorf("AdapterGates(nope) = %+v, %v", g, err)
+	}
+
+	importNow := func() *ImportRunRecord {
+		t.Helper()
+		run, err := s.svc.TriggerImport(src.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Wait()
+		run, _ = s.svc.GetImportRun(run.ID)
+		return run
+	}
+	if run := importNow(); run.Status != "success" || run.EntryCount != 3 {
+		t.Fatalf("first import = %+v", run)
+	}
+
+	// A truncated file fails the gates and leaves the previous build live.
+	body = "name\nalpha\n"
+	run := importNow()
+	if run.Status != "failed" || run.Error == nil || !strings.Contains(*run.Error, importer.ErrGateFailed.Error()) {
+		t.Fatalf("truncated import = %+v", run)
+	}
+	d, _ := reg.Get("gated")
+	if _, ok := d.Lookup("beta"); !ok || d.EntryCount() != 3 {
+		t.Errorf("previous build replaced: %d entries", d.EntryCount())
+	}
+	if got, _ := s.svc.GetSource(src.ID); got.LastImportStatus != importRejected || got.LastImportError == nil || *got.LastImportCount != 3 {
+		t.Errorf("rejected import recorded as %+v", got)
+	}
+
+	// Removing the gates lets the smaller build through.
+	if err := s.svc.UpdateSource(src.ID, UpdateSourceRequest{QualityGates: &importer.Gates{}}); err != nil {
+		t.Fatal(err)
+	}
+	src, _ = s.svc.GetSource(src.ID)
+	if src.QualityGates != nil {
+		t.Errorf("gates not removed: %+v", src.QualityGates)
+	}
+	if run := importNow(); run.Status != "success" || run.EntryCount != 1 {
+		t.Errorf("ungated import = %+v", run)
+	}
+}
+
+func TestAdminRouter_RollbackDict(t *testing.T) {
+	s, a, reg, src := setupScheduler(t)
+	for _, terms := range [][]string{{"alpha"}, {"alpha", "beta"}} {
+		a.terms = terms
+		if _, err := s.svc.TriggerImport(src.ID); err != nil {
+			t.Fatal(err)
+		}
+		s.Wait()
+	}
+	router := NewRouter(s.svc, "tok")
+	rollback := func(id string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/v1/dicts/"+id+"/rollback", nil)
+		req.Header.Set("Authorization", "Bearer tok")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	hasBeta := func() bool {
+		d, _ := reg.Get("sched-dict")
+		_, ok := d.Lookup("beta")
+		return ok
+	}
+
+	if code := rollback("sched-dict"); code != http.StatusOK || hasBeta() {
+		t.Errorf("rollback: status %d, beta still live: %v", code, hasBeta())
+	}
+	if code := rollback("sched-dict"); code != http.StatusOK || !hasBeta() {
+		t.Errorf("second rollback: status %d, beta live: %v", code, hasBeta())
+	}
+	if code := rollback("unknown"); code != http.StatusNotFound {
+		t.Errorf("unknown dictionary: status %d", code)
+	}
+}
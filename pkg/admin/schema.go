@@ -1,9 +1,14 @@
-// CLAUDE:SUMMARY DDL schema for the admin database (dict_registry, sources, import_runs).
+// CLAUDE:SUMMARY DDL schema for the admin database (dict_registry, sources, import_runs) and its in-place migration.
 // CLAUDE:DEPENDS
-// CLAUDE:EXPORTS Schema
+// CLAUDE:EXPORTS Schema, InitSchema
 
 package admin
 
+import (
+	"database/sql"
+	"fmt"
+)
+
 // Schema is the DDL for the admin database tables.
 const Schema = `
 CREATE TABLE IF NOT EXISTS dict_registry (
@@ -28,11 +33,15 @@ CREATE TABLE IF NOT EXISTS sources (
     license TEXT NOT NULL DEFAULT '',
     format TEXT NOT NULL DEFAULT '',
     update_frequency TEXT NOT NULL DEFAULT '',
+    enabled INTEGER NOT NULL DEFAULT 1,
+    quality_gates TEXT NOT NULL DEFAULT '',
     last_check INTEGER,
     last_status INTEGER,
     last_error TEXT,
     last_import INTEGER,
     last_import_count INTEGER,
+    last_import_status TEXT NOT NULL DEFAULT '',
+    last_import_error TEXT,
     created_at INTEGER NOT NULL,
     updated_at INTEGER NOT NULL
 );
@@ -49,3 +58,29 @@ CREATE TABLE IF NOT EXISTS import_runs (
     duration_ms INTEGER
 );
 `
+
+// InitSchema creates the admin tables and adds the columns introduced since
+// an existing database was created.
+func InitSchema(db *sql.DB) error {
+	if _, err := db.Exec(Schema); err != nil {
+		return err
+	}
+	for _, col := range []struct{ name, def string }{
+		{"enabled", "INTEGER NOT NULL DEFAULT 1"},
+		{"quality_gates", "TEXT NOT NULL DEFAULT ''"},
+		{"last_import_status", "TEXT NOT NULL DEFAULT ''"},
+		{"last_import_error", "TEXT"},
+	} {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('sources') WHERE name = ?`, col.name).Scan(&n); err != nil {
+			return fmt.Errorf("inspect sources: %w", err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE sources ADD COLUMN ` + col.name + ` ` + col.def); err != nil {
+			return fmt.Errorf("add sources.%s: %w", col.name, err)
+		}
+	}
+	return nil
+}
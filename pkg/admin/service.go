@@ -1,6 +1,6 @@
 // CLAUDE:SUMMARY Admin service layer for CRUD operations on dicts, sources, import runs, and alias pools with audit logging.
 // CLAUDE:DEPENDS pkg/admin/schema.go
-// CLAUDE:EXPORTS Service, DictRecord, SourceRecord, ImportRunRecord, CreateDictRequest, CreateSourceRequest
+// CLAUDE:EXPORTS Service, DictRecord, SourceRecord, ImportRunRecord, CreateDictRequest, CreateSourceRequest, GetSource
 
 package admin
 
@@ -12,12 +12,16 @@ import (
 
 	"github.com/hazyhaar/pkg/audit"
 	"github.com/hazyhaar/pkg/idgen"
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/importer"
 )
 
 // Service provides CRUD operations for the admin API.
 type Service struct {
 	db    *sql.DB
 	audit audit.Logger
+	reg   *dict.Registry // alias pool edits; see SetRegistry
+	sched *Scheduler     // admin-triggered imports; see NewScheduler
 }
 
 // NewService creates a new admin service.
@@ -39,6 +43,8 @@ type DictRecord struct {
 	EntryCount   int    `json:"entry_count"`
 	CreatedAt    int64  `json:"created_at"`
 	UpdatedAt    int64  `json:"updated_at"`
+
+	Provenance *dict.Provenance `json:"provenance,omitempty"` // GetDict only: how the loaded build was imported
 }
 
 // CreateDictRequest is the payload for creating a new dictionary.
@@ -103,9 +109,23 @@ func (s *Service) GetDict(id string) (*DictRecord, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get dict %s: %w", id, err)
 	}
+	rec.Provenance = s.dictProvenance(id)
 	return &rec, nil
 }
 
+// dictProvenance returns the import provenance of the loaded dictionary id,
+// nil when it has none or no registry is attached.
+func (s *Service) dictProvenance(id string) *dict.Provenance {
+	if s.reg == nil {
+		return nil
+	}
+	d, ok := s.reg.Get(id)
+	if !ok {
+		return nil
+	}
+	return d.Provenance
+}
+
 // ListDicts returns all dictionary records.
 func (s *Service) ListDicts() ([]DictRecord, error) {
 	rows, err := s.db.Query(`SELECT id, type, jurisdiction, entity_type, domain, status, manifest_json, entry_count, created_at, updated_at
@@ -171,51 +191,62 @@ func (s *Service) DeleteDict(id string) error {
 
 // SourceRecord is a row from sources.
 type SourceRecord struct {
-	ID              string  `json:"id"`
-	DictID          string  `json:"dict_id"`
-	AdapterID       string  `json:"adapter_id"`
-	Description     string  `json:"description"`
-	SourceURL       string  `json:"source_url"`
-	License         string  `json:"license"`
-	Format          string  `json:"format"`
-	UpdateFrequency string  `json:"update_frequency"`
-	LastCheck       *int64  `json:"last_check"`
-	LastStatus      *int    `json:"last_status"`
-	LastError       *string `json:"last_error"`
-	LastImport      *int64  `json:"last_import"`
-	LastImportCount *int    `json:"last_import_count"`
-	CreatedAt       int64   `json:"created_at"`
-	UpdatedAt       int64   `json:"updated_at"`
+	ID               string          `json:"id"`
+	DictID           string          `json:"dict_id"`
+	AdapterID        string          `json:"adapter_id"`
+	Description      string          `json:"description"`
+	SourceURL        string          `json:"source_url"`
+	License          string          `json:"license"`
+	Format           string          `json:"format"`
+	UpdateFrequency  string          `json:"update_frequency"`
+	Enabled          bool            `json:"enabled"`                 // scheduled imports run; see Scheduler
+	QualityGates     *importer.Gates `json:"quality_gates,omitempty"` // checked before a new build goes live
+	LastCheck        *int64          `json:"last_check"`
+	LastStatus       *int            `json:"last_status"`
+	LastError        *string         `json:"last_error"`
+	LastImport       *int64          `json:"last_import"`
+	LastImportCount  *int            `json:"last_import_count"`
+	LastImportStatus string          `json:"last_import_status"` // outcome of the latest import; see importStatus
+	LastImportError  *string         `json:"last_import_error"`
+	CreatedAt        int64           `json:"created_at"`
+	UpdatedAt        int64           `json:"updated_at"`
 }
 
 // CreateSourceRequest is the payload for creating a new source.
 type CreateSourceRequest struct {
-	DictID          string `json:"dict_id"`
-	AdapterID       string `json:"adapter_id"`
-	Description     string `json:"description"`
-	SourceURL       string `json:"source_url"`
-	License         string `json:"license"`
-	Format          string `json:"format"`
-	UpdateFrequency string `json:"update_frequency"`
+	DictID          string          `json:"dict_id"`
+	AdapterID       string          `json:"adapter_id"`
+	Description     string          `json:"description"`
+	SourceURL       string          `json:"source_url"`
+	License         string          `json:"license"`
+	Format          string          `json:"format"`
+	UpdateFrequency string          `json:"update_frequency"`
+	QualityGates    *importer.Gates `json:"quality_gates,omitempty"`
 }
 
 // UpdateSourceRequest is the payload for updating a source.
 type UpdateSourceRequest struct {
-	Description     string `json:"description,omitempty"`
-	SourceURL       string `json:"source_url,omitempty"`
-	License         string `json:"license,omitempty"`
-	Format          string `json:"format,omitempty"`
-	UpdateFrequency string `json:"update_frequency,omitempty"`
+	Description     string          `json:"description,omitempty"`
+	SourceURL       string          `json:"source_url,omitempty"`
+	License         string          `json:"license,omitempty"`
+	Format          string          `json:"format,omitempty"`
+	UpdateFrequency string          `json:"update_frequency,omitempty"`
+	Enabled         *bool           `json:"enabled,omitempty"`
+	QualityGates    *importer.Gates `json:"quality_gates,omitempty"` // {} removes them
 }
 
 // CreateSource inserts a new source record.
 func (s *Service) CreateSource(req CreateSourceRequest) (*SourceRecord, error) {
+	gates, err := marshalGates(req.QualityGates)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now().Unix()
 	id := idgen.New()
 
-	_, err := s.db.Exec(`INSERT INTO sources (id, dict_id, adapter_id, description, source_url, license, format, update_frequency, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		id, req.DictID, req.AdapterID, req.Description, req.SourceURL, req.License, req.Format, req.UpdateFrequency, now, now)
+	_, err = s.db.Exec(`INSERT INTO sources (id, dict_id, adapter_id, description, source_url, license, format, update_frequency, quality_gates, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, req.DictID, req.AdapterID, req.Description, req.SourceURL, req.License, req.Format, req.UpdateFrequency, gates, now, now)
 	if err != nil {
 		return nil, fmt.Errorf("create source: %w", err)
 	}
@@ -231,6 +262,8 @@ func (s *Service) CreateSource(req CreateSourceRequest) (*SourceRecord, error) {
 		License:         req.License,
 		Format:          req.Format,
 		UpdateFrequency: req.UpdateFrequency,
+		Enabled:         true,
+		QualityGates:    req.QualityGates,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}, nil
@@ -238,9 +271,7 @@ func (s *Service) CreateSource(req CreateSourceRequest) (*SourceRecord, error) {
 
 // ListSources returns all sources, optionally filtered by dict_id.
 func (s *Service) ListSources(dictID string) ([]SourceRecord, error) {
-	query := `SELECT id, dict_id, adapter_id, description, source_url, license, format, update_frequency,
-		last_check, last_status, last_error, last_import, last_import_count, created_at, updated_at
-		FROM sources`
+	query := sourceColumns + ` FROM sources`
 	var args []any
 	if dictID != "" {
 		query += ` WHERE dict_id = ?`
@@ -257,10 +288,7 @@ func (s *Service) ListSources(dictID string) ([]SourceRecord, error) {
 	var recs []SourceRecord
 	for rows.Next() {
 		var rec SourceRecord
-		if err := rows.Scan(&rec.ID, &rec.DictID, &rec.AdapterID, &rec.Description, &rec.SourceURL,
-			&rec.License, &rec.Format, &rec.UpdateFrequency,
-			&rec.LastCheck, &rec.LastStatus, &rec.LastError,
-			&rec.LastImport, &rec.LastImportCount, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		if err := scanSource(rows, &rec); err != nil {
 			return nil, fmt.Errorf("scan source: %w", err)
 		}
 		recs = append(recs, rec)
@@ -268,8 +296,81 @@ func (s *Service) ListSources(dictID string) ([]SourceRecord, error) {
 	return recs, rows.Err()
 }
 
+const sourceColumns = `SELECT id, dict_id, adapter_id, description, source_url, license, format, update_frequency, enabled, quality_gates,
+		last_check, last_status, last_error, last_import, last_import_count, last_import_status, last_import_error, created_at, updated_at`
+
+func scanSource(row interface{ Scan(...any) error }, rec *SourceRecord) error {
+	var gates string
+	if err := row.Scan(&rec.ID, &rec.DictID, &rec.AdapterID, &rec.Description, &rec.SourceURL,
+		&rec.License, &rec.Format, &rec.UpdateFrequency, &rec.Enabled, &gates,
+		&rec.LastCheck, &rec.LastStatus, &rec.LastError,
+		&rec.LastImport, &rec.LastImportCount, &rec.LastImportStatus, &rec.LastImportError, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return err
+	}
+	if gates != "" {
+		rec.QualityGates = &importer.Gates{}
+		if err := json.Unmarshal([]byte(gates), rec.QualityGates); err != nil {
+			return fmt.Errorf("decode quality gates of %s: %w", rec.ID, err)
+		}
+	}
+	return nil
+}
+
+// marshalGates validates g and encodes it for the quality_gates column;
+// nil and zero gates encode as "".
+func marshalGates(g *importer.Gates) (string, error) {
+	if g == nil {
+		return "", nil
+	}
+	if err := g.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidGates, err)
+	}
+	b, err := json.Marshal(g)
+	if err != nil || string(b) == "{}" {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// GetSource returns a single source by ID.
+func (s *Service) GetSource(id string) (*SourceRecord, error) {
+	var rec SourceRecord
+	if err := scanSource(s.db.QueryRow(sourceColumns+` FROM sources WHERE id = ?`, id), &rec); err != nil {
+		return nil, fmt.Errorf("get source %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// AdapterGates returns the quality gates of the source imported by adapter
+// adapterID, nil when no source sets any, for imports run outside the
+// scheduler. An enabled source wins over a disabled one.
+func (s *Service) AdapterGates(adapterID string) (*importer.Gates, error) {
+	var gates string
+	err := s.db.QueryRow(`SELECT quality_gates FROM sources WHERE adapter_id = ? AND quality_gates != ''
+		ORDER BY enabled DESC, id LIMIT 1`, adapterID).Scan(&gates)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("quality gates of %s: %w", adapterID, err)
+	}
+	g := &importer.Gates{}
+	if err := json.Unmarshal([]byte(gates), g); err != nil {
+		return nil, fmt.Errorf("decode quality gates of %s: %w", adapterID, err)
+	}
+	return g, nil
+}
+
 // UpdateSource updates a source record.
 func (s *Service) UpdateSource(id string, req UpdateSourceRequest) error {
+	var gates *string
+	if req.QualityGates != nil {
+		g, err := marshalGates(req.QualityGates)
+		if err != nil {
+			return err
+		}
+		gates = &g
+	}
 	now := time.Now().Unix()
 	_, err := s.db.Exec(`UPDATE sources SET
 		description = CASE WHEN ? != '' THEN ? ELSE description END,
@@ -277,6 +378,8 @@ func (s *Service) UpdateSource(id string, req UpdateSourceRequest) error {
 		license = CASE WHEN ? != '' THEN ? ELSE license END,
 		format = CASE WHEN ? != '' THEN ? ELSE format END,
 		update_frequency = CASE WHEN ? != '' THEN ? ELSE update_frequency END,
+		enabled = COALESCE(?, enabled),
+		quality_gates = COALESCE(?, quality_gates),
 		updated_at = ?
 		WHERE id = ?`,
 		req.Description, req.Description,
@@ -284,6 +387,7 @@ func (s *Service) UpdateSource(id string, req UpdateSourceRequest) error {
 		req.License, req.License,
 		req.Format, req.Format,
 		req.UpdateFrequency, req.UpdateFrequency,
+		req.Enabled, gates,
 		now, id)
 	if err != nil {
 		return fmt.Errorf("update source %s: %w", id, err)
@@ -365,6 +469,36 @@ func (s *Service) FinishImportRun(id string, entryCount int, importErr error) er
 	return nil
 }
 
+// recordImport stores the outcome of the latest import of a source. Only
+// successful and unchanged imports move last_import and last_import_count,
+// so a failure does not delay the next scheduled attempt.
+func (s *Service) recordImport(sourceID string, at int64, entryCount int, status string, importErr error) error {
+	var errStr *string
+	if importErr != nil {
+		e := importErr.Error()
+		errStr = &e
+	}
+	var err error
+	if status == importSuccess || status == importUnchanged {
+		_, err = s.db.Exec(`UPDATE sources SET last_import = ?, last_import_count = ?, last_import_status = ?, last_import_error = NULL WHERE id = ?`,
+			at, entryCount, status, sourceID)
+	} else {
+		_, err = s.db.Exec(`UPDATE sources SET last_import_status = ?, last_import_error = ? WHERE id = ?`, status, errStr, sourceID)
+	}
+	if err != nil {
+		return fmt.Errorf("record import of %s: %w", sourceID, err)
+	}
+	return nil
+}
+
+// lastFailedRun returns the start time of the latest failed import run of a
+// source, or 0.
+func (s *Service) lastFailedRun(sourceID string) int64 {
+	var at sql.NullInt64
+	_ = s.db.QueryRow(`SELECT MAX(started_at) FROM import_runs WHERE source_id = ? AND status = 'failed'`, sourceID).Scan(&at)
+	return at.Int64
+}
+
 // ListImportRuns returns import runs, optionally filtered.
 func (s *Service) ListImportRuns(sourceID, dictID string) ([]ImportRunRecord, error) {
 	query := `SELECT id, source_id, dict_id, started_at, finished_at, status, entry_count, error, duration_ms
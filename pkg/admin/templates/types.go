@@ -21,6 +21,31 @@ type DictView struct {
 	EntryCount   int
 	CreatedAt    string
 	UpdatedAt    string
+	Provenance   *ProvenanceView // detail page only; nil when not imported
+}
+
+// ProvenanceView is a view model for the import provenance of a dictionary.
+type ProvenanceView struct {
+	Adapter        string
+	AdapterVersion string
+	Mode           string
+	SourceDate     string
+	StartedAt      string
+	FinishedAt     string
+	Read           int
+	Filtered       int
+	Kept           int
+	Collided       int
+	Downloads      []DownloadView
+}
+
+// DownloadView is a view model for a file downloaded by an import.
+type DownloadView struct {
+	URL          string
+	ResolvedURL  string
+	SHA256       string
+	LastModified string
+	Bytes        int64
 }
 
 // SourceView is a view model for a source in the admin panel.
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Transport-agnostic kit.Endpoint functions for classify-term, classify-batch, and list-dicts operations.
+// CLAUDE:SUMMARY Transport-agnostic kit.Endpoint functions for classify-term, classify-batch, generalize, hash-prefix range, pseudonymize, surrogate, risk, alias allocation, and list-dicts operations.
 package api
 
 import (
@@ -6,13 +6,16 @@ import (
 	"fmt"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/policy"
+	"github.com/hazyhaar/touchstone-registry/pkg/pseudo"
 	"github.com/hazyhaar/pkg/kit"
 )
 
 // Shared request/response types used by both HTTP and MCP transports.
 
 type batchResponse struct {
-	Results []*dict.ClassifyResult `json:"results"`
+	Results        []*dict.ClassifyResult `json:"results"`
+	BirthDateLinks []dict.BirthDateLink   `json:"birth_date_links,omitempty"`
 }
 
 type dictsResponse struct {
@@ -34,6 +37,41 @@ type resolveTermReq struct {
 	Opts *dict.ClassifyOptions
 }
 
+type generalizeTermReq struct {
+	Term  string
+	Level int
+	Opts  *dict.ClassifyOptions
+}
+
+type rangeReq struct {
+	Prefix string
+	Opts   *dict.ClassifyOptions
+}
+
+type pseudonymizeResponse struct {
+	Results []pseudo.Result `json:"results"`
+}
+
+type surrogateResponse struct {
+	Results []pseudo.SurrogateResult `json:"results"`
+}
+
+type riskReq struct {
+	Terms []string
+	Opts  *dict.RiskOptions
+}
+
+type allocateAliasesReq struct {
+	Domain string
+	Key    []byte
+	Terms  []string
+}
+
+type allocateAliasesResponse struct {
+	Domain      string                   `json:"domain"`
+	Allocations []pseudo.AliasAllocation `json:"allocations"`
+}
+
 type getAliasesReq struct {
 	Domain string
 }
@@ -43,17 +81,22 @@ type aliasesResponse struct {
 	Aliases []dict.AliasEntry  `json:"aliases"`
 }
 
-// Endpoints returns the core kit.Endpoints backed by the registry.
+// Endpoints returns the core kit.Endpoints backed by the registry. Endpoints
+// taking a policy enforce it for the caller class carried by the context
+// (see policy.WithClass); a nil policy allows everything.
 
-func classifyTermEndpoint(reg *dict.Registry) kit.Endpoint {
-	return func(_ context.Context, request any) (any, error) {
+func classifyTermEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
 		req := request.(*classifyTermReq)
-		return reg.Classify(req.Term, req.Opts), nil
+		class := policy.ClassFrom(ctx)
+		res := reg.Classify(req.Term, pol.Options(req.Opts, class))
+		pol.FilterMatches(reg, class, res.Matches)
+		return res, nil
 	}
 }
 
-func classifyBatchEndpoint(reg *dict.Registry) kit.Endpoint {
-	return func(_ context.Context, request any) (any, error) {
+func classifyBatchEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
 		req := request.(*classifyBatchReq)
 		if len(req.Terms) == 0 {
 			return nil, fmt.Errorf("terms array is empty")
@@ -61,31 +104,172 @@ func classifyBatchEndpoint(reg *dict.Registry) kit.Endpoint {
 		if len(req.Terms) > 100 {
 			return nil, fmt.Errorf("too many terms (max 100, got %d)", len(req.Terms))
 		}
+		class := policy.ClassFrom(ctx)
+		opts := pol.Options(req.Opts, class)
 		results := make([]*dict.ClassifyResult, len(req.Terms))
 		for i, term := range req.Terms {
-			results[i] = reg.Classify(term, req.Opts)
+			results[i] = reg.Classify(term, opts)
+			pol.FilterMatches(reg, class, results[i].Matches)
+		}
+		return batchResponse{Results: results, BirthDateLinks: dict.LinkBirthDates(results)}, nil
+	}
+}
+
+func pseudonymizeEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*pseudo.Request)
+		if len(req.Terms) == 0 {
+			return nil, fmt.Errorf("terms array is empty")
+		}
+		if len(req.Terms) > 100 {
+			return nil, fmt.Errorf("too many terms (max 100, got %d)", len(req.Terms))
+		}
+		req.Opts = pol.Options(req.Opts, policy.ClassFrom(ctx))
+		results, err := pseudo.Pseudonymize(ctx, reg, req)
+		if err != nil {
+			return nil, err
+		}
+		return pseudonymizeResponse{Results: results}, nil
+	}
+}
+
+func surrogateEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*pseudo.SurrogateRequest)
+		if len(req.Terms) == 0 {
+			return nil, fmt.Errorf("terms array is empty")
+		}
+		if len(req.Terms) > 100 {
+			return nil, fmt.Errorf("too many terms (max 100, got %d)", len(req.Terms))
 		}
-		return batchResponse{Results: results}, nil
+		req.Opts = pol.Options(req.Opts, policy.ClassFrom(ctx))
+		results, err := pseudo.Surrogate(reg, req)
+		if err != nil {
+			return nil, err
+		}
+		return surrogateResponse{Results: results}, nil
+	}
+}
+
+func riskEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*riskReq)
+		if len(req.Terms) == 0 {
+			return nil, fmt.Errorf("terms array is empty")
+		}
+		if len(req.Terms) > 100 {
+			return nil, fmt.Errorf("too many terms (max 100, got %d)", len(req.Terms))
+		}
+		if req.Opts.Population < 0 || req.Opts.K < 0 {
+			return nil, fmt.Errorf("population and k must not be negative")
+		}
+		req.Opts.Classify = pol.Options(req.Opts.Classify, policy.ClassFrom(ctx))
+		return reg.Risk(req.Terms, req.Opts), nil
 	}
 }
 
-func listDictsEndpoint(reg *dict.Registry) kit.Endpoint {
-	return func(_ context.Context, _ any) (any, error) {
-		return dictsResponse{Dictionaries: reg.ListDicts()}, nil
+func listDictsEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, _ any) (any, error) {
+		class := policy.ClassFrom(ctx)
+		infos := reg.ListDicts()
+		visible := infos[:0]
+		for _, info := range infos {
+			if d, ok := reg.Get(info.ID); ok && !pol.CanQuery(d.Manifest, class) {
+				continue
+			}
+			visible = append(visible, info)
+		}
+		return dictsResponse{Dictionaries: visible}, nil
 	}
 }
 
-func resolveTermEndpoint(reg *dict.Registry) kit.Endpoint {
-	return func(_ context.Context, request any) (any, error) {
+func resolveTermEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
 		req := request.(*resolveTermReq)
-		return reg.Resolve(req.Term, req.Opts), nil
+		class := policy.ClassFrom(ctx)
+		res := reg.Resolve(req.Term, pol.Options(req.Opts, class))
+		if d, ok := reg.Get(res.Dict); ok {
+			if !pol.CanResolve(d.Manifest, class) {
+				res.Data = nil
+			} else {
+				res.Data = pol.FilterMetadata(d.Manifest, class, res.Data)
+			}
+		}
+		return res, nil
+	}
+}
+
+func generalizeTermEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*generalizeTermReq)
+		if req.Level < 0 || req.Level > dict.MaxGeneralizeLevel {
+			return nil, fmt.Errorf("level must be between 0 and %d", dict.MaxGeneralizeLevel)
+		}
+		class := policy.ClassFrom(ctx)
+		res := reg.Generalize(req.Term, req.Level, pol.Options(req.Opts, class))
+		for i, step := range res.Path {
+			// The value of a step is the parent_column field of the entry
+			// below: the walk stops short of a field class may not see.
+			if i > 0 {
+				if d, ok := reg.Get(res.Path[i-1].DictID); ok && !pol.CanSeeField(d.Manifest, class, d.Manifest.ParentColumn) {
+					res.Path = res.Path[:i]
+					res.Level, res.Generalized, res.Top = i-1, res.Path[i-1].Value, true
+					break
+				}
+			}
+			if d, ok := reg.Get(step.DictID); ok {
+				res.Path[i].Metadata = pol.FilterMetadata(d.Manifest, class, step.Metadata)
+			}
+		}
+		return res, nil
 	}
 }
 
-func getAliasesEndpoint(reg *dict.Registry) kit.Endpoint {
-	return func(_ context.Context, request any) (any, error) {
+func rangeEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*rangeReq)
+		return reg.Range(req.Prefix, pol.Options(req.Opts, policy.ClassFrom(ctx)))
+	}
+}
+
+func getAliasesEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
 		req := request.(*getAliasesReq)
-		aliases := reg.GetAliases(req.Domain)
+		var aliases []dict.AliasEntry
+		if canQueryAliasPool(reg, pol, policy.ClassFrom(ctx), req.Domain) {
+			aliases = reg.GetAliases(req.Domain)
+		}
 		return aliasesResponse{Domain: req.Domain, Aliases: aliases}, nil
 	}
 }
+
+func allocateAliasesEndpoint(reg *dict.Registry, pol *policy.Policy) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		req := request.(*allocateAliasesReq)
+		if len(req.Terms) == 0 {
+			return nil, fmt.Errorf("terms array is empty")
+		}
+		if len(req.Terms) > 100 {
+			return nil, fmt.Errorf("too many terms (max 100, got %d)", len(req.Terms))
+		}
+		if !canQueryAliasPool(reg, pol, policy.ClassFrom(ctx), req.Domain) {
+			return nil, fmt.Errorf("no alias pool for domain %q", req.Domain)
+		}
+		allocs, err := pseudo.AllocateAliases(reg, req.Domain, req.Key, req.Terms)
+		if err != nil {
+			return nil, err
+		}
+		return allocateAliasesResponse{Domain: req.Domain, Allocations: allocs}, nil
+	}
+}
+
+// canQueryAliasPool reports whether class may query the alias pool serving
+// domain. A pool the class may not query is reported as missing.
+func canQueryAliasPool(reg *dict.Registry, pol *policy.Policy, class, domain string) bool {
+	id, ok := reg.AliasPoolID(domain)
+	if !ok {
+		return true
+	}
+	d, ok := reg.Get(id)
+	return !ok || pol.CanQuery(d.Manifest, class)
+}
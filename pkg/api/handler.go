@@ -1,24 +1,34 @@
-// CLAUDE:SUMMARY HTTP handler and router for the Touchstone REST API (classify, batch, dicts, health) with CORS middleware.
+// CLAUDE:SUMMARY HTTP handler and router for the Touchstone REST API (classify, batch, resolve, generalize, range, pseudonymize, surrogate, risk, dicts, health) with CORS middleware.
 package api
 
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/policy"
+	"github.com/hazyhaar/touchstone-registry/pkg/pseudo"
 	"github.com/hazyhaar/pkg/kit"
 )
 
-// NewRouter returns an http.Handler with all Touchstone API routes.
-func NewRouter(reg *dict.Registry) http.Handler {
+// NewRouter returns an http.Handler with all Touchstone API routes, enforcing
+// pol (nil allows everything) for the caller class of each request.
+func NewRouter(reg *dict.Registry, pol *policy.Policy) http.Handler {
 	mux := http.NewServeMux()
 	h := &handler{
-		classifyTerm:  classifyTermEndpoint(reg),
-		classifyBatch: classifyBatchEndpoint(reg),
-		listDicts:     listDictsEndpoint(reg),
-		resolveTerm:   resolveTermEndpoint(reg),
-		getAliases:    getAliasesEndpoint(reg),
+		classifyTerm:  classifyTermEndpoint(reg, pol),
+		classifyBatch: classifyBatchEndpoint(reg, pol),
+		listDicts:     listDictsEndpoint(reg, pol),
+		resolveTerm:   resolveTermEndpoint(reg, pol),
+		generalize:    generalizeTermEndpoint(reg, pol),
+		hashRange:     rangeEndpoint(reg, pol),
+		getAliases:    getAliasesEndpoint(reg, pol),
+		pseudonymize:  pseudonymizeEndpoint(reg, pol),
+		surrogate:     surrogateEndpoint(reg, pol),
+		risk:          riskEndpoint(reg, pol),
+		allocAliases:  allocateAliasesEndpoint(reg, pol),
 		reg:           reg,
 	}
 
@@ -26,11 +36,24 @@ func NewRouter(reg *dict.Registry) http.Handler {
 	mux.HandleFunc("POST /v1/classify/batch", h.handleClassifyBatch)
 	mux.HandleFunc("GET /v1/classify/{term}", h.handleClassifyTerm)
 	mux.HandleFunc("GET /v1/resolve/{term}", h.handleResolveTerm)
+	mux.HandleFunc("GET /v1/generalize/{term}", h.handleGeneralizeTerm)
+	mux.HandleFunc("GET /v1/range/{prefix}", h.handleRange)
 	mux.HandleFunc("GET /v1/aliases/{domain}", h.handleGetAliases)
+	mux.HandleFunc("POST /v1/aliases/{domain}/allocate", h.handleAllocateAliases)
+	mux.HandleFunc("POST /v1/pseudonymize", h.handlePseudonymize)
+	mux.HandleFunc("POST /v1/surrogate", h.handleSurrogate)
+	mux.HandleFunc("POST /v1/risk", h.handleRisk)
 	mux.HandleFunc("GET /v1/dicts", h.handleListDicts)
 	mux.HandleFunc("GET /v1/health", h.handleHealth)
 
-	return cors(mux)
+	return cors(withCallerClass(pol, mux))
+}
+
+// withCallerClass tags each request with its policy caller class.
+func withCallerClass(pol *policy.Policy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(policy.WithClass(r.Context(), pol.HTTPClass(r))))
+	})
 }
 
 type handler struct {
@@ -38,7 +61,13 @@ type handler struct {
 	classifyBatch kit.Endpoint
 	listDicts     kit.Endpoint
 	resolveTerm   kit.Endpoint
+	generalize    kit.Endpoint
+	hashRange     kit.Endpoint
 	getAliases    kit.Endpoint
+	pseudonymize  kit.Endpoint
+	surrogate     kit.Endpoint
+	risk          kit.Endpoint
+	allocAliases  kit.Endpoint
 	reg           *dict.Registry
 }
 
@@ -94,6 +123,118 @@ func (h *handler) handleClassifyBatch(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// --- pseudonymize ---
+
+// httpPseudonymizeRequest carries the client key. The key only lives for the
+// duration of the request: it is never stored nor logged.
+type httpPseudonymizeRequest struct {
+	Terms         []string `json:"terms"`
+	Key           string   `json:"key"`
+	Strategy      string   `json:"strategy,omitempty"`
+	AliasDomain   string   `json:"alias_domain,omitempty"`
+	Jurisdictions []string `json:"jurisdictions,omitempty"`
+	Types         []string `json:"types,omitempty"`
+	Dicts         []string `json:"dicts,omitempty"`
+}
+
+func (h *handler) handlePseudonymize(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024) // 64 KiB max
+	var req httpPseudonymizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.pseudonymize(r.Context(), &pseudo.Request{
+		Terms:       req.Terms,
+		Key:         []byte(req.Key),
+		Strategy:    req.Strategy,
+		AliasDomain: req.AliasDomain,
+		Opts: &dict.ClassifyOptions{
+			Jurisdictions: req.Jurisdictions,
+			Types:         req.Types,
+			Dicts:         req.Dicts,
+		},
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// --- surrogate ---
+
+type httpSurrogateRequest struct {
+	Terms         []string `json:"terms"`
+	Seed          string   `json:"seed"`
+	Jurisdictions []string `json:"jurisdictions,omitempty"`
+	Types         []string `json:"types,omitempty"`
+	Dicts         []string `json:"dicts,omitempty"`
+}
+
+func (h *handler) handleSurrogate(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024) // 64 KiB max
+	var req httpSurrogateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.surrogate(r.Context(), &pseudo.SurrogateRequest{
+		Terms: req.Terms,
+		Seed:  req.Seed,
+		Opts: &dict.ClassifyOptions{
+			Jurisdictions: req.Jurisdictions,
+			Types:         req.Types,
+			Dicts:         req.Dicts,
+		},
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// --- risk ---
+
+type httpRiskRequest struct {
+	Terms         []string `json:"terms"`
+	Population    float64  `json:"population,omitempty"`
+	K             int      `json:"k,omitempty"`
+	Jurisdictions []string `json:"jurisdictions,omitempty"`
+	Types         []string `json:"types,omitempty"`
+	Dicts         []string `json:"dicts,omitempty"`
+}
+
+func (h *handler) handleRisk(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024) // 64 KiB max
+	var req httpRiskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.risk(r.Context(), &riskReq{
+		Terms: req.Terms,
+		Opts: &dict.RiskOptions{
+			Population: req.Population,
+			K:          req.K,
+			Classify: &dict.ClassifyOptions{
+				Jurisdictions: req.Jurisdictions,
+				Types:         req.Types,
+				Dicts:         req.Dicts,
+			},
+		},
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // --- resolve single term ---
 
 func (h *handler) handleResolveTerm(w http.ResponseWriter, r *http.Request) {
@@ -114,6 +255,50 @@ func (h *handler) handleResolveTerm(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// --- generalize single term ---
+
+func (h *handler) handleGeneralizeTerm(w http.ResponseWriter, r *http.Request) {
+	term := r.PathValue("term")
+	if term == "" {
+		writeError(w, http.StatusBadRequest, "missing term")
+		return
+	}
+	level := 1
+	if v := r.URL.Query().Get("level"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid level")
+			return
+		}
+		level = n
+	}
+
+	resp, err := h.generalize(r.Context(), &generalizeTermReq{
+		Term:  term,
+		Level: level,
+		Opts:  parseOpts(r),
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// --- hash-prefix range ---
+
+func (h *handler) handleRange(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.hashRange(r.Context(), &rangeReq{
+		Prefix: r.PathValue("prefix"),
+		Opts:   parseOpts(r),
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // --- get aliases ---
 
 func (h *handler) handleGetAliases(w http.ResponseWriter, r *http.Request) {
@@ -131,6 +316,32 @@ func (h *handler) handleGetAliases(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// httpAllocateAliasesRequest carries the client key, which is never stored.
+type httpAllocateAliasesRequest struct {
+	Terms []string `json:"terms"`
+	Key   string   `json:"key"`
+}
+
+func (h *handler) handleAllocateAliases(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024) // 64 KiB max
+	var req httpAllocateAliasesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.allocAliases(r.Context(), &allocateAliasesReq{
+		Domain: r.PathValue("domain"),
+		Key:    []byte(req.Key),
+		Terms:  req.Terms,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // --- list dicts ---
 
 func (h *handler) handleListDicts(w http.ResponseWriter, r *http.Request) {
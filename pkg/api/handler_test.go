@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/policy"
 )
 
 func setupTestRegistry(t *testing.T) *dict.Registry {
@@ -27,6 +29,7 @@ entity_type: surname
 source: test
 type: registry
 data_file: data.csv
+surrogates: true
 entity_spec:
   sensitivity: medium
   pseudo_strategy: hash
@@ -78,7 +81,7 @@ entries:
 
 func TestHandler_ClassifyTerm(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/classify/DUPONT", nil)
 	w := httptest.NewRecorder()
@@ -102,7 +105,7 @@ func TestHandler_ClassifyTerm(t *testing.T) {
 
 func TestHandler_ResolveTerm(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/resolve/DUPONT", nil)
 	w := httptest.NewRecorder()
@@ -126,7 +129,7 @@ func TestHandler_ResolveTerm(t *testing.T) {
 
 func TestHandler_GetAliases(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/aliases/pharma", nil)
 	w := httptest.NewRecorder()
@@ -153,7 +156,7 @@ func TestHandler_GetAliases(t *testing.T) {
 
 func TestHandler_GetAliases_Unknown(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/aliases/unknown", nil)
 	w := httptest.NewRecorder()
@@ -174,7 +177,7 @@ func TestHandler_GetAliases_Unknown(t *testing.T) {
 
 func TestHandler_Health(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/health", nil)
 	w := httptest.NewRecorder()
@@ -198,7 +201,7 @@ func TestHandler_Health(t *testing.T) {
 
 func TestHandler_ListDicts(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("GET", "/v1/dicts", nil)
 	w := httptest.NewRecorder()
@@ -219,7 +222,7 @@ func TestHandler_ListDicts(t *testing.T) {
 
 func TestHandler_CORS(t *testing.T) {
 	reg := setupTestRegistry(t)
-	router := NewRouter(reg)
+	router := NewRouter(reg, nil)
 
 	req := httptest.NewRequest("OPTIONS", "/v1/health", nil)
 	w := httptest.NewRecorder()
@@ -229,3 +232,412 @@ func TestHandler_CORS(t *testing.T) {
 		t.Error("CORS header missing")
 	}
 }
+
+func TestHandler_ClassifyBatch_BirthDateLinks(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"dates", "national-ids-eu"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "dicts", id, "manifest.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, id), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, id, "manifest.yaml"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	router := NewRouter(reg, nil)
+
+	body := `{"terms": ["44051401359", "14 mai 1944", "DUPONT"]}`
+	req := httptest.NewRequest("POST", "/v1/classify/batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp batchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.BirthDateLinks) != 1 {
+		t.Fatalf("birth_date_links = %+v, want 1", resp.BirthDateLinks)
+	}
+	link := resp.BirthDateLinks[0]
+	if link.IDTerm != "44051401359" || link.DateTerm != "14 mai 1944" || link.BirthDate != "1944-05-14" {
+		t.Errorf("link = %+v", link)
+	}
+}
+
+func TestHandler_Pseudonymize(t *testing.T) {
+	reg := setupTestRegistry(t)
+	router := NewRouter(reg, nil)
+
+	body := `{"terms": ["DUPONT", "Dupont", "inconnu"], "key": "0123456789abcdef"}`
+	req := httptest.NewRequest("POST", "/v1/pseudonymize", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp pseudonymizeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("results = %d", len(resp.Results))
+	}
+	if !resp.Results[0].Replaced || resp.Results[0].Pseudonym == "DUPONT" {
+		t.Errorf("result = %+v", resp.Results[0])
+	}
+	if resp.Results[0].Pseudonym != resp.Results[1].Pseudonym {
+		t.Errorf("pseudonyms differ: %q, %q", resp.Results[0].Pseudonym, resp.Results[1].Pseudonym)
+	}
+	if resp.Results[2].Replaced {
+		t.Errorf("unknown term replaced: %+v", resp.Results[2])
+	}
+
+	req = httptest.NewRequest("POST", "/v1/pseudonymize", strings.NewReader(`{"terms": ["DUPONT"], "key": "short"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("short key: status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandler_Surrogate(t *testing.T) {
+	reg := setupTestRegistry(t)
+	router := NewRouter(reg, nil)
+
+	body := `{"terms": ["DUPONT", "Dupont", "inconnu"], "seed": "s3cret"}`
+	req := httptest.NewRequest("POST", "/v1/surrogate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp surrogateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("results = %d", len(resp.Results))
+	}
+	if r := resp.Results[0]; r.Surrogate != "MARTIN" || r.Conditions["frequency"] != "1e3" {
+		t.Errorf("result = %+v", r)
+	}
+	if r := resp.Results[1]; r.Surrogate != "Martin" {
+		t.Errorf("result = %+v", r)
+	}
+	if resp.Results[2].Replaced {
+		t.Errorf("unknown term replaced: %+v", resp.Results[2])
+	}
+
+	req = httptest.NewRequest("POST", "/v1/surrogate", strings.NewReader(`{"terms": ["DUPONT"]}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("missing seed: status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandler_AllocateAliases(t *testing.T) {
+	reg := setupTestRegistry(t)
+	router := NewRouter(reg, nil)
+
+	body := `{"terms": ["Doliprane", "DOLIPRANE"], "key": "0123456789abcdef"}`
+	req := httptest.NewRequest("POST", "/v1/aliases/pharma/allocate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp allocateAliasesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Domain != "pharma" || len(resp.Allocations) != 2 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if a := resp.Allocations[0]; a.Alias != "SANOFI" || a.Mimics != "SANOFI-AVENTIS" {
+		t.Errorf("allocation = %+v", a)
+	}
+
+	// Two distinct terms, one alias.
+	body = `{"terms": ["Doliprane", "Efferalgan"], "key": "0123456789abcdef"}`
+	req = httptest.NewRequest("POST", "/v1/aliases/pharma/allocate", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("exhausted pool: status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandler_Risk(t *testing.T) {
+	reg := setupTestRegistry(t)
+	router := NewRouter(reg, nil)
+
+	body := `{"terms": ["DUPONT", "1985", "inconnu"], "population": 1000}`
+	req := httptest.NewRequest("POST", "/v1/risk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp dict.RiskReport
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Level != "high" || resp.K != dict.DefaultRiskK {
+		t.Errorf("report = %+v", resp)
+	}
+	if len(resp.Factors) != 2 || resp.Factors[0].Term != "1985" || resp.Factors[1].Basis != "frequency" {
+		t.Errorf("factors = %+v", resp.Factors)
+	}
+	if len(resp.Ignored) != 1 || resp.Ignored[0] != "inconnu" {
+		t.Errorf("ignored = %v", resp.Ignored)
+	}
+
+	req = httptest.NewRequest("POST", "/v1/risk", strings.NewReader(`{"terms": []}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("empty terms: status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandler_Generalize(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dates")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `id: dates
+version: "1.0"
+jurisdiction: intl
+entity_type: date
+source: test
+method: pattern
+parent_column: year
+patterns:
+  - name: date_iso
+    regex: "^\\d{4}-\\d{2}-\\d{2}$"
+    extractor: date_ymd
+`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(filepath.Dir(dir))
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	router := NewRouter(reg, nil)
+
+	req := httptest.NewRequest("GET", "/v1/generalize/1985-01-12", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp dict.GeneralizeResult
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Generalized != "1985" || resp.Level != 1 || !resp.Top || len(resp.Path) != 2 {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/generalize/1985-01-12?level=x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid level: status = %d, want 400", w.Code)
+	}
+
+	// A class that may not see the year stays at the date.
+	pol, err := policy.New(policy.Config{Levels: map[string]map[string]policy.Rule{
+		policy.DefaultLevel: {policy.Anonymous: {Fields: &[]string{"month"}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = httptest.NewRequest("GET", "/v1/generalize/1985-01-12", nil)
+	w = httptest.NewRecorder()
+	NewRouter(reg, pol).ServeHTTP(w, req)
+	resp = dict.GeneralizeResult{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Generalized != "1985-01-12" || resp.Level != 0 || !resp.Top || len(resp.Path) != 1 {
+		t.Errorf("hidden year: resp = %+v", resp)
+	}
+}
+
+func TestHandler_Policy(t *testing.T) {
+	reg := setupTestRegistry(t)
+	f := false
+	pol, err := policy.New(policy.Config{
+		APITokens: []string{"s3cret-token"},
+		Levels: map[string]map[string]policy.Rule{
+			"medium": {policy.Anonymous: {Resolve: &f, Fields: &[]string{}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(reg, pol)
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", path, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	var cr dict.ClassifyResult
+	if err := json.NewDecoder(get("/v1/classify/DUPONT", "").Body).Decode(&cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Matches) != 1 || cr.Matches[0].Metadata != nil {
+		t.Errorf("anonymous classify = %+v", cr.Matches)
+	}
+	if err := json.NewDecoder(get("/v1/classify/DUPONT", "s3cret-token").Body).Decode(&cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Matches) != 1 || cr.Matches[0].Metadata["frequency"] != "1200" {
+		t.Errorf("authenticated classify = %+v", cr.Matches)
+	}
+
+	var rr dict.ResolveResult
+	if err := json.NewDecoder(get("/v1/resolve/DUPONT", "").Body).Decode(&rr); err != nil {
+		t.Fatal(err)
+	}
+	if !rr.Match || rr.Data != nil {
+		t.Errorf("anonymous resolve = %+v", rr)
+	}
+
+	// Queries are refused outright once the level is closed.
+	no := false
+	pol, _ = policy.New(policy.Config{Levels: map[string]map[string]policy.Rule{
+		"medium": {policy.Anonymous: {Query: &no}},
+	}})
+	router = NewRouter(reg, pol)
+	if err := json.NewDecoder(get("/v1/classify/DUPONT", "").Body).Decode(&cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Matches) != 0 {
+		t.Errorf("closed level classify = %+v", cr.Matches)
+	}
+	var dr dictsResponse
+	if err := json.NewDecoder(get("/v1/dicts", "").Body).Decode(&dr); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range dr.Dictionaries {
+		if d.ID == "noms-fr" {
+			t.Error("closed dictionary listed")
+		}
+	}
+}
+
+func TestHandler_PolicyAliases(t *testing.T) {
+	reg := setupTestRegistry(t)
+	no := false
+	pol, err := policy.New(policy.Config{
+		APITokens: []string{"s3cret-token"},
+		Levels: map[string]map[string]policy.Rule{
+			policy.DefaultLevel: {policy.Anonymous: {Query: &no}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(reg, pol)
+
+	serve := func(method, path, body, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var resp aliasesResponse
+	if err := json.NewDecoder(serve("GET", "/v1/aliases/pharma", "", "").Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Aliases) != 0 {
+		t.Errorf("anonymous aliases = %+v", resp.Aliases)
+	}
+	if err := json.NewDecoder(serve("GET", "/v1/aliases/pharma", "", "s3cret-token").Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Aliases) != 1 {
+		t.Errorf("authenticated aliases = %+v", resp.Aliases)
+	}
+
+	body := `{"terms": ["Doliprane"], "key": "0123456789abcdef"}`
+	if w := serve("POST", "/v1/aliases/pharma/allocate", body, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("anonymous allocate: status = %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve("POST", "/v1/aliases/pharma/allocate", body, "s3cret-token"); w.Code != http.StatusOK {
+		t.Errorf("authenticated allocate: status = %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandler_Range(t *testing.T) {
+	reg := setupTestRegistry(t)
+	router := NewRouter(reg, nil)
+
+	h := dict.TermHash("dupont")
+	req := httptest.NewRequest("GET", "/v1/range/"+h[:5], nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp dict.RangeResult
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, m := range resp.Matches {
+		if resp.Prefix+m.Suffix == h && len(m.Dicts) == 1 && m.Dicts[0].DictID == "noms-fr" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("resp = %+v, want dupont in noms-fr", resp)
+	}
+
+	req = httptest.NewRequest("GET", "/v1/range/dupont", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("non-hex prefix: status = %d, want 400", w.Code)
+	}
+}
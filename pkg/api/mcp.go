@@ -1,26 +1,36 @@
-// CLAUDE:SUMMARY MCP tool registration exposing classify_term, classify_batch, and list_dicts as MCP-over-QUIC tools.
+// CLAUDE:SUMMARY MCP tool registration exposing classify_term, classify_batch, generalize_term, hash_range, pseudonymize, surrogate, assess_risk, allocate_aliases, and list_dicts as MCP-over-QUIC tools.
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"github.com/hazyhaar/touchstone-registry/pkg/policy"
+	"github.com/hazyhaar/touchstone-registry/pkg/pseudo"
 	"github.com/hazyhaar/pkg/kit"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-// RegisterMCPTools registers the Touchstone MCP tools on the server.
-func RegisterMCPTools(srv *mcp.Server, reg *dict.Registry) {
-	registerMCPClassifyTerm(srv, reg)
-	registerMCPClassifyBatch(srv, reg)
-	registerMCPListDicts(srv, reg)
-	registerMCPResolveTerm(srv, reg)
-	registerMCPGetAliases(srv, reg)
+// RegisterMCPTools registers the Touchstone MCP tools on the server, enforcing
+// pol (nil allows everything) for its MCP caller class.
+func RegisterMCPTools(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	registerMCPClassifyTerm(srv, reg, pol)
+	registerMCPClassifyBatch(srv, reg, pol)
+	registerMCPListDicts(srv, reg, pol)
+	registerMCPResolveTerm(srv, reg, pol)
+	registerMCPGeneralizeTerm(srv, reg, pol)
+	registerMCPRange(srv, reg, pol)
+	registerMCPGetAliases(srv, reg, pol)
+	registerMCPPseudonymize(srv, reg, pol)
+	registerMCPSurrogate(srv, reg, pol)
+	registerMCPRisk(srv, reg, pol)
+	registerMCPAllocateAliases(srv, reg, pol)
 }
 
-func registerMCPClassifyTerm(srv *mcp.Server, reg *dict.Registry) {
+func registerMCPClassifyTerm(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
 	tool := mcpTool("classify_term",
 		"Classify a single term against public data registries (surnames, first names, companies, cities, street types).",
 		map[string]any{
@@ -32,7 +42,7 @@ func registerMCPClassifyTerm(srv *mcp.Server, reg *dict.Registry) {
 		[]string{"term"},
 	)
 
-	endpoint := classifyTermEndpoint(reg)
+	endpoint := withMCPClass(pol, classifyTermEndpoint(reg, pol))
 
 	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
 		args := parseArgs(req)
@@ -44,7 +54,7 @@ func registerMCPClassifyTerm(srv *mcp.Server, reg *dict.Registry) {
 	})
 }
 
-func registerMCPClassifyBatch(srv *mcp.Server, reg *dict.Registry) {
+func registerMCPClassifyBatch(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
 	tool := mcpTool("classify_batch",
 		"Classify multiple terms (up to 100) against public data registries.",
 		map[string]any{
@@ -56,7 +66,7 @@ func registerMCPClassifyBatch(srv *mcp.Server, reg *dict.Registry) {
 		[]string{"terms"},
 	)
 
-	endpoint := classifyBatchEndpoint(reg)
+	endpoint := withMCPClass(pol, classifyBatchEndpoint(reg, pol))
 
 	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
 		args := parseArgs(req)
@@ -75,14 +85,14 @@ func registerMCPClassifyBatch(srv *mcp.Server, reg *dict.Registry) {
 	})
 }
 
-func registerMCPListDicts(srv *mcp.Server, reg *dict.Registry) {
+func registerMCPListDicts(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
 	tool := mcpTool("list_dicts",
 		"List all loaded dictionaries with metadata (jurisdiction, entity type, entry count, source).",
 		nil,
 		nil,
 	)
 
-	endpoint := listDictsEndpoint(reg)
+	endpoint := withMCPClass(pol, listDictsEndpoint(reg, pol))
 
 	kit.RegisterMCPTool(srv, tool, endpoint, func(_ *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
 		return &kit.MCPDecodeResult{Request: nil}, nil
@@ -120,7 +130,7 @@ func parseArgs(req *mcp.CallToolRequest) map[string]any {
 	return args
 }
 
-func registerMCPResolveTerm(srv *mcp.Server, reg *dict.Registry) {
+func registerMCPResolveTerm(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
 	tool := mcpTool("resolve_term",
 		"Resolve a term against public data registries and return rich structured data.",
 		map[string]any{
@@ -132,7 +142,7 @@ func registerMCPResolveTerm(srv *mcp.Server, reg *dict.Registry) {
 		[]string{"term"},
 	)
 
-	endpoint := resolveTermEndpoint(reg)
+	endpoint := withMCPClass(pol, resolveTermEndpoint(reg, pol))
 
 	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
 		args := parseArgs(req)
@@ -144,7 +154,61 @@ func registerMCPResolveTerm(srv *mcp.Server, reg *dict.Registry) {
 	})
 }
 
-func registerMCPGetAliases(srv *mcp.Server, reg *dict.Registry) {
+func registerMCPGeneralizeTerm(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("generalize_term",
+		"Generalize a term up its hierarchy instead of redacting it: a commune to its department, a department to its region, a NAF code to its section, an ICD-10 code to its chapter, a date to its year.",
+		map[string]any{
+			"term":          map[string]string{"type": "string", "description": "The term to generalize"},
+			"level":         map[string]string{"type": "integer", "description": "Number of levels to walk up (default 1)"},
+			"jurisdictions": map[string]string{"type": "string", "description": "Comma-separated jurisdiction filter"},
+			"types":         map[string]string{"type": "string", "description": "Comma-separated entity type filter"},
+			"dicts":         map[string]string{"type": "string", "description": "Comma-separated dictionary filter"},
+		},
+		[]string{"term"},
+	)
+
+	endpoint := withMCPClass(pol, generalizeTermEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		term, _ := args["term"].(string)
+		level := 1
+		if v, ok := args["level"].(float64); ok {
+			level = int(v)
+		}
+		return &kit.MCPDecodeResult{Request: &generalizeTermReq{
+			Term:  term,
+			Level: level,
+			Opts:  parseMCPOpts(args),
+		}}, nil
+	})
+}
+
+func registerMCPRange(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("hash_range",
+		"k-anonymous lookup: return the SHA-256 hash suffixes of every dictionary term whose hash starts with the given prefix, with their dictionaries. Hash the normalized term locally, send only the first 5 hex characters, and compare the suffixes locally.",
+		map[string]any{
+			"prefix":        map[string]string{"type": "string", "description": "First 5 to 10 hex characters of the SHA-256 of the normalized term"},
+			"jurisdictions": map[string]string{"type": "string", "description": "Comma-separated jurisdiction filter"},
+			"types":         map[string]string{"type": "string", "description": "Comma-separated entity type filter"},
+			"dicts":         map[string]string{"type": "string", "description": "Comma-separated dictionary filter"},
+		},
+		[]string{"prefix"},
+	)
+
+	endpoint := withMCPClass(pol, rangeEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		prefix, _ := args["prefix"].(string)
+		return &kit.MCPDecodeResult{Request: &rangeReq{
+			Prefix: prefix,
+			Opts:   parseMCPOpts(args),
+		}}, nil
+	})
+}
+
+func registerMCPGetAliases(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
 	tool := mcpTool("get_aliases",
 		"Get alias entries for a specific domain (e.g. pharma, finance).",
 		map[string]any{
@@ -153,7 +217,7 @@ func registerMCPGetAliases(srv *mcp.Server, reg *dict.Registry) {
 		[]string{"domain"},
 	)
 
-	endpoint := getAliasesEndpoint(reg)
+	endpoint := withMCPClass(pol, getAliasesEndpoint(reg, pol))
 
 	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
 		args := parseArgs(req)
@@ -162,6 +226,147 @@ func registerMCPGetAliases(srv *mcp.Server, reg *dict.Registry) {
 	})
 }
 
+func registerMCPPseudonymize(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("pseudonymize",
+		"Replace terms (up to 100) with deterministic pseudonyms according to the pseudonymization strategy of the dictionary they match. The key is used for this call only and never stored.",
+		map[string]any{
+			"terms":         map[string]string{"type": "string", "description": "Comma-separated list of terms to pseudonymize (max 100)"},
+			"key":           map[string]string{"type": "string", "description": "Secret key (at least 16 bytes); the same key yields the same pseudonyms"},
+			"strategy":      map[string]string{"type": "string", "description": "Override the dictionaries' strategy (hash, format_preserving, fpe, alias, surrogate)"},
+			"alias_domain":  map[string]string{"type": "string", "description": "Alias pool domain for the alias strategy (defaults to the entity type)"},
+			"jurisdictions": map[string]string{"type": "string", "description": "Comma-separated jurisdiction filter"},
+			"types":         map[string]string{"type": "string", "description": "Comma-separated entity type filter"},
+			"dicts":         map[string]string{"type": "string", "description": "Comma-separated dictionary filter"},
+		},
+		[]string{"terms", "key"},
+	)
+
+	endpoint := withMCPClass(pol, pseudonymizeEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		termsStr, _ := args["terms"].(string)
+		terms := strings.Split(termsStr, ",")
+		for i := range terms {
+			terms[i] = strings.TrimSpace(terms[i])
+		}
+		key, _ := args["key"].(string)
+		strategy, _ := args["strategy"].(string)
+		domain, _ := args["alias_domain"].(string)
+		return &kit.MCPDecodeResult{Request: &pseudo.Request{
+			Terms:       terms,
+			Key:         []byte(key),
+			Strategy:    strategy,
+			AliasDomain: domain,
+			Opts:        parseMCPOpts(args),
+		}}, nil
+	})
+}
+
+func registerMCPSurrogate(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("surrogate",
+		"Replace terms (up to 100) with realistic surrogates from the dictionary they match: a surname of similar frequency, a first name of the same gender and form, a commune of the same department.",
+		map[string]any{
+			"terms":         map[string]string{"type": "string", "description": "Comma-separated list of terms to replace (max 100)"},
+			"seed":          map[string]string{"type": "string", "description": "Seed; the same seed yields the same surrogates"},
+			"jurisdictions": map[string]string{"type": "string", "description": "Comma-separated jurisdiction filter"},
+			"types":         map[string]string{"type": "string", "description": "Comma-separated entity type filter"},
+			"dicts":         map[string]string{"type": "string", "description": "Comma-separated dictionary filter"},
+		},
+		[]string{"terms", "seed"},
+	)
+
+	endpoint := withMCPClass(pol, surrogateEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		termsStr, _ := args["terms"].(string)
+		terms := strings.Split(termsStr, ",")
+		for i := range terms {
+			terms[i] = strings.TrimSpace(terms[i])
+		}
+		seed, _ := args["seed"].(string)
+		return &kit.MCPDecodeResult{Request: &pseudo.SurrogateRequest{
+			Terms: terms,
+			Seed:  seed,
+			Opts:  parseMCPOpts(args),
+		}}, nil
+	})
+}
+
+func registerMCPRisk(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("assess_risk",
+		"Estimate how identifying a set of terms (up to 100) from one document is, k-anonymity style: how many people are expected to share the whole combination, given name frequencies, commune populations and birth years, with the terms ranked by contribution.",
+		map[string]any{
+			"terms":         map[string]string{"type": "string", "description": "Comma-separated list of terms (max 100)"},
+			"population":    map[string]string{"type": "number", "description": "Population the subject is drawn from (default 68000000)"},
+			"k":             map[string]string{"type": "integer", "description": "k-anonymity threshold (default 5)"},
+			"jurisdictions": map[string]string{"type": "string", "description": "Comma-separated jurisdiction filter"},
+			"types":         map[string]string{"type": "string", "description": "Comma-separated entity type filter"},
+			"dicts":         map[string]string{"type": "string", "description": "Comma-separated dictionary filter"},
+		},
+		[]string{"terms"},
+	)
+
+	endpoint := withMCPClass(pol, riskEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		termsStr, _ := args["terms"].(string)
+		terms := strings.Split(termsStr, ",")
+		for i := range terms {
+			terms[i] = strings.TrimSpace(terms[i])
+		}
+		population, _ := args["population"].(float64)
+		k, _ := args["k"].(float64)
+		return &kit.MCPDecodeResult{Request: &riskReq{
+			Terms: terms,
+			Opts: &dict.RiskOptions{
+				Population: population,
+				K:          int(k),
+				Classify:   parseMCPOpts(args),
+			},
+		}}, nil
+	})
+}
+
+func registerMCPAllocateAliases(srv *mcp.Server, reg *dict.Registry, pol *policy.Policy) {
+	tool := mcpTool("allocate_aliases",
+		"Hand out distinct aliases of a pool to terms (up to 100), deterministically for a given key. Aliases colliding with real entities are skipped. The key is used for this call only and never stored.",
+		map[string]any{
+			"domain": map[string]string{"type": "string", "description": "The alias pool domain"},
+			"terms":  map[string]string{"type": "string", "description": "Comma-separated list of terms (max 100)"},
+			"key":    map[string]string{"type": "string", "description": "Secret key (at least 16 bytes); the same key yields the same allocation"},
+		},
+		[]string{"domain", "terms", "key"},
+	)
+
+	endpoint := withMCPClass(pol, allocateAliasesEndpoint(reg, pol))
+
+	kit.RegisterMCPTool(srv, tool, endpoint, func(req *mcp.CallToolRequest) (*kit.MCPDecodeResult, error) {
+		args := parseArgs(req)
+		domain, _ := args["domain"].(string)
+		termsStr, _ := args["terms"].(string)
+		terms := strings.Split(termsStr, ",")
+		for i := range terms {
+			terms[i] = strings.TrimSpace(terms[i])
+		}
+		key, _ := args["key"].(string)
+		return &kit.MCPDecodeResult{Request: &allocateAliasesReq{
+			Domain: domain,
+			Key:    []byte(key),
+			Terms:  terms,
+		}}, nil
+	})
+}
+
+// withMCPClass tags MCP tool calls with the policy's MCP caller class.
+func withMCPClass(pol *policy.Policy, next kit.Endpoint) kit.Endpoint {
+	return func(ctx context.Context, request any) (any, error) {
+		return next(policy.WithClass(ctx, pol.MCPClass()), request)
+	}
+}
+
 // parseMCPOpts extracts ClassifyOptions from MCP tool arguments.
 func parseMCPOpts(args map[string]interface{}) *dict.ClassifyOptions {
 	opts := &dict.ClassifyOptions{}
@@ -0,0 +1,238 @@
+// CLAUDE:SUMMARY Alias pool maintenance: criblage against the dictionaries of cribled_against (collisions, duplicates, next due date) and atomic edits persisted to the pool manifest.
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/dict/manifest.go
+// CLAUDE:EXPORTS AliasCollision, CriblageReport, CribleAliasPool, CribleDuePools, StartCriblage, AliasPoolID, EditAliasPool
+package dict
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// criblageDateLayout is the layout of last_criblage and next_criblage.
+const criblageDateLayout = "2006-01-02"
+
+// defaultCriblageInterval applies to pools without an update_frequency.
+const defaultCriblageInterval = 30 * 24 * time.Hour
+
+// AliasCollision is an alias found as a real entity in a screened dictionary.
+type AliasCollision struct {
+	Alias  string `json:"alias"`
+	Term   string `json:"term"` // the colliding form: the alias, or alias + suffix
+	DictID string `json:"dict_id"`
+}
+
+// CriblageReport is the outcome of screening one alias pool.
+type CriblageReport struct {
+	PoolID       string           `json:"pool_id"`
+	Domain       string           `json:"domain"`
+	CheckedAt    string           `json:"checked_at"`
+	NextCriblage string           `json:"next_criblage"`
+	Aliases      int              `json:"aliases"`
+	Usable       int              `json:"usable"`
+	Collisions   []AliasCollision `json:"collisions,omitempty"`
+	Duplicates   []string         `json:"duplicates,omitempty"`
+	// Missing lists cribled_against dictionaries that are not loaded: their
+	// entities could not be screened.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// CribleAliasPool screens the alias pool id: every alias, alone and followed
+// by its suffix, is looked up in each dictionary of cribled_against, and
+// aliases repeated in the pool are flagged. The result is written to the
+// pool's manifest (collisions, duplicate, last_criblage, next_criblage) and
+// takes effect immediately, without a reload. The next due date is now plus
+// the pool's update_frequency (monthly by default).
+func (r *Registry) CribleAliasPool(id string, now time.Time) (*CriblageReport, error) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+
+	r.mu.RLock()
+	pool, ok := r.dicts[id]
+	r.mu.RUnlock()
+	if !ok || pool.Manifest.Type != "alias_pool" {
+		return nil, fmt.Errorf("alias pool %q not found", id)
+	}
+	m := *pool.Manifest
+	report := r.screenAliases(pool, &m, now)
+
+	if err := SaveManifest(pool.dir, &m); err != nil {
+		return nil, fmt.Errorf("alias pool %s: %w", id, err)
+	}
+
+	r.mu.Lock()
+	if r.dicts[id] == pool {
+		updated := *pool
+		updated.Manifest = &m
+		r.dicts[id] = &updated
+		if m.Domain != "" {
+			r.aliasPools[m.Domain] = m.AliasEntries
+		}
+	}
+	r.mu.Unlock()
+	return report, nil
+}
+
+// screenAliases screens the entries of m, the manifest of pool, against the
+// dictionaries of its cribled_against, recording the outcome and the
+// criblage dates in m.
+func (r *Registry) screenAliases(pool *Dictionary, m *Manifest, now time.Time) *CriblageReport {
+	report := &CriblageReport{PoolID: m.ID, Domain: m.Domain}
+
+	r.mu.RLock()
+	var screened []*Dictionary
+	for _, did := range m.CribledAgainst {
+		if d, ok := r.dicts[did]; ok {
+			screened = append(screened, d)
+		} else {
+			report.Missing = append(report.Missing, did)
+		}
+	}
+
+	seen := make(map[string]bool, len(m.AliasEntries))
+	entries := make([]AliasEntry, len(m.AliasEntries))
+	for i, e := range m.AliasEntries {
+		e.Collisions, e.Duplicate = nil, false
+		norm := pool.NormalizeTerm(e.Alias)
+		if seen[norm] {
+			e.Duplicate = true
+			report.Duplicates = append(report.Duplicates, e.Alias)
+		}
+		seen[norm] = true
+
+		forms := []string{e.Alias}
+		if e.Suffix != "" {
+			forms = append(forms, e.Alias+" "+e.Suffix)
+		}
+		for _, d := range screened {
+			for _, form := range forms {
+				if _, hit := d.Classify(form); hit {
+					e.Collisions = append(e.Collisions, d.Manifest.ID)
+					report.Collisions = append(report.Collisions, AliasCollision{Alias: e.Alias, Term: form, DictID: d.Manifest.ID})
+					break
+				}
+			}
+		}
+		if e.Usable() {
+			report.Usable++
+		}
+		entries[i] = e
+	}
+	r.mu.RUnlock()
+
+	interval := UpdateInterval(m.UpdateFrequency)
+	if interval == 0 {
+		interval = defaultCriblageInterval
+	}
+	m.AliasEntries = entries
+	m.LastCriblage = now.Format(criblageDateLayout)
+	m.NextCriblage = now.Add(interval).Format(criblageDateLayout)
+	report.Aliases = len(entries)
+	report.CheckedAt = m.LastCriblage
+	report.NextCriblage = m.NextCriblage
+	return report
+}
+
+// CribleDuePools screens every alias pool whose next_criblage is empty or not
+// after now, in pool ID order. It stops at the first failure.
+func (r *Registry) CribleDuePools(now time.Time) ([]*CriblageReport, error) {
+	today := now.Format(criblageDateLayout)
+
+	r.mu.RLock()
+	var due []string
+	for id, d := range r.dicts {
+		if d.Manifest.Type != "alias_pool" {
+			continue
+		}
+		// ISO dates compare as strings.
+		if next := d.Manifest.NextCriblage; next == "" || next <= today {
+			due = append(due, id)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Strings(due)
+
+	reports := make([]*CriblageReport, 0, len(due))
+	for _, id := range due {
+		rep, err := r.CribleAliasPool(id, now)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, rep)
+	}
+	return reports, nil
+}
+
+// StartCriblage screens due alias pools immediately, then every interval until
+// ctx is cancelled.
+func (r *Registry) StartCriblage(ctx context.Context, logger *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		reports, err := r.CribleDuePools(time.Now())
+		for _, rep := range reports {
+			logger.Info("alias pool screened", "pool", rep.PoolID, "aliases", rep.Aliases, "usable", rep.Usable,
+				"collisions", len(rep.Collisions), "duplicates", len(rep.Duplicates), "next", rep.NextCriblage)
+			if len(rep.Missing) > 0 {
+				logger.Warn("alias pool screened against missing dictionaries", "pool", rep.PoolID, "missing", rep.Missing)
+			}
+		}
+		if err != nil {
+			logger.Error("alias pool criblage failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AliasPoolID returns the ID of the alias pool serving domain.
+func (r *Registry) AliasPoolID(domain string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for id, d := range r.dicts {
+		if d.Manifest.Type == "alias_pool" && d.Manifest.Domain == domain {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// EditAliasPool applies edit to a copy of the entries of the pool serving
+// domain, screens the result as CribleAliasPool does, writes the manifest
+// atomically and reloads the pool: an added or changed alias is never handed
+// out before it is screened. It returns the pool ID and the new entries.
+func (r *Registry) EditAliasPool(domain string, edit func([]AliasEntry) ([]AliasEntry, error)) (string, []AliasEntry, error) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+
+	id, ok := r.AliasPoolID(domain)
+	if !ok {
+		return "", nil, fmt.Errorf("no alias pool for domain %q", domain)
+	}
+	r.mu.RLock()
+	pool := r.dicts[id]
+	r.mu.RUnlock()
+
+	m := *pool.Manifest
+	entries, err := edit(append([]AliasEntry(nil), m.AliasEntries...))
+	if err != nil {
+		return id, nil, err
+	}
+	m.AliasEntries = entries
+	r.screenAliases(pool, &m, time.Now())
+
+	if err := SaveManifest(pool.dir, &m); err != nil {
+		return id, nil, fmt.Errorf("alias pool %s: %w", id, err)
+	}
+	if err := r.ReloadDict(id); err != nil {
+		return id, nil, err
+	}
+	return id, m.AliasEntries, nil
+}
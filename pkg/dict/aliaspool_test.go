@@ -0,0 +1,178 @@
+package dict
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setupCriblageRegistry(t *testing.T) (*Registry, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeDict(t, dir, "pharma-aliases", `id: pharma-aliases
+version: "1.0"
+jurisdiction: fr
+entity_type: alias
+source: manual
+type: alias_pool
+domain: pharma
+update_frequency: weekly
+cribled_against:
+  - sirene-fr
+  - companies-uk
+entries:
+  - alias: "ALDORA"
+    mimics: "SANOFI"
+  - alias: "VERLAINE"
+    suffix: "SA"
+  - alias: "PHARMEX"
+  - alias: "Aldora"
+  - alias: "NOVALIS"
+`, "")
+	writeDict(t, dir, "sirene-fr", `id: sirene-fr
+version: "1.0"
+jurisdiction: fr
+entity_type: company
+source: test
+data_file: data.csv
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "term"
+`, "term\nPHARMEX\nVERLAINE SA\n")
+	writeDict(t, dir, "other-aliases", `id: other-aliases
+version: "1.0"
+jurisdiction: fr
+entity_type: alias
+source: manual
+type: alias_pool
+domain: other
+next_criblage: "2099-01-01"
+entries:
+  - alias: "X"
+`, "")
+
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg, dir
+}
+
+func TestCribleAliasPool(t *testing.T) {
+	reg, dir := setupCriblageRegistry(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	rep, err := reg.CribleAliasPool("pharma-aliases", now)
+	if err != nil {
+		t.Fatalf("CribleAliasPool: %v", err)
+	}
+	if rep.Aliases != 5 || rep.Usable != 2 {
+		t.Errorf("aliases = %d, usable = %d, want 5, 2", rep.Aliases, rep.Usable)
+	}
+	if len(rep.Collisions) != 2 {
+		t.Fatalf("collisions = %+v", rep.Collisions)
+	}
+	if c := rep.Collisions[0]; c.Alias != "VERLAINE" || c.Term != "VERLAINE SA" || c.DictID != "sirene-fr" {
+		t.Errorf("collision[0] = %+v", c)
+	}
+	if len(rep.Duplicates) != 1 || rep.Duplicates[0] != "Aldora" {
+		t.Errorf("duplicates = %v", rep.Duplicates)
+	}
+	if len(rep.Missing) != 1 || rep.Missing[0] != "companies-uk" {
+		t.Errorf("missing = %v", rep.Missing)
+	}
+	if rep.CheckedAt != "2026-03-01" || rep.NextCriblage != "2026-03-08" {
+		t.Errorf("checked %s, next %s", rep.CheckedAt, rep.NextCriblage)
+	}
+
+	// Applied in memory.
+	var usable []string
+	for _, e := range reg.GetAliases("pharma") {
+		if e.Usable() {
+			usable = append(usable, e.Alias)
+		}
+	}
+	if len(usable) != 2 || usable[0] != "ALDORA" || usable[1] != "NOVALIS" {
+		t.Errorf("usable aliases = %v", usable)
+	}
+
+	// Persisted to the manifest.
+	m, err := LoadManifest(filepath.Join(dir, "pharma-aliases", "manifest.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.LastCriblage != "2026-03-01" || m.NextCriblage != "2026-03-08" {
+		t.Errorf("manifest dates = %s, %s", m.LastCriblage, m.NextCriblage)
+	}
+	if e := m.AliasEntries[2]; len(e.Collisions) != 1 || e.Collisions[0] != "sirene-fr" {
+		t.Errorf("manifest entry[2] = %+v", e)
+	}
+	if !m.AliasEntries[3].Duplicate || m.AliasEntries[0].Mimics != "SANOFI" {
+		t.Errorf("manifest entries = %+v", m.AliasEntries)
+	}
+
+	// Survives a reload.
+	if err := reg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if e := reg.GetAliases("pharma")[1]; e.Usable() {
+		t.Errorf("after reload, entry[1] = %+v", e)
+	}
+}
+
+func TestCribleAliasPool_NotAPool(t *testing.T) {
+	reg, _ := setupCriblageRegistry(t)
+	if _, err := reg.CribleAliasPool("sirene-fr", time.Now()); err == nil {
+		t.Error("expected error for a non-pool dictionary")
+	}
+}
+
+func TestEditAliasPool_Screens(t *testing.T) {
+	reg, _ := setupCriblageRegistry(t)
+	_, entries, err := reg.EditAliasPool("pharma", func(entries []AliasEntry) ([]AliasEntry, error) {
+		return append(entries, AliasEntry{Alias: "VERLAINE", Suffix: "SA"}, AliasEntry{Alias: "ORVALE"}), nil
+	})
+	if err != nil {
+		t.Fatalf("EditAliasPool: %v", err)
+	}
+	if len(entries) != 7 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	// Added aliases are screened before the pool serves them.
+	for _, e := range reg.GetAliases("pharma")[5:] {
+		if want := e.Alias == "ORVALE"; e.Usable() != want {
+			t.Errorf("%s: usable = %v, want %v (%+v)", e.Alias, e.Usable(), want, e)
+		}
+	}
+}
+
+func TestCribleDuePools(t *testing.T) {
+	reg, _ := setupCriblageRegistry(t)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	reports, err := reg.CribleDuePools(now)
+	if err != nil {
+		t.Fatalf("CribleDuePools: %v", err)
+	}
+	if len(reports) != 1 || reports[0].PoolID != "pharma-aliases" {
+		t.Fatalf("reports = %+v", reports)
+	}
+
+	// Next due in a week: nothing to do tomorrow.
+	if reports, _ := reg.CribleDuePools(now.Add(24 * time.Hour)); len(reports) != 0 {
+		t.Errorf("reports = %+v, want none", reports)
+	}
+	if reports, _ := reg.CribleDuePools(now.Add(7 * 24 * time.Hour)); len(reports) != 1 {
+		t.Errorf("reports = %+v, want one", reports)
+	}
+}
+
+func TestUpdateInterval(t *testing.T) {
+	if got := UpdateInterval("weekly"); got != 7*24*time.Hour {
+		t.Errorf("weekly = %v", got)
+	}
+	if got := UpdateInterval("sometimes"); got != 0 {
+		t.Errorf("unknown = %v", got)
+	}
+}
@@ -0,0 +1,160 @@
+// CLAUDE:SUMMARY Birth-date extractors for national IDs (NIR, CNP, PESEL, HETU) and batch cross-check linking IDs to matching date terms.
+// CLAUDE:DEPENDS pkg/dict/dates.go, pkg/dict/registry.go
+// CLAUDE:EXPORTS BirthDateLink, LinkBirthDates
+package dict
+
+import "strings"
+
+// extractNIRBirth reads the birth year and month of a French NIR
+// (S YY MM ...). The NIR does not encode the century, so the year is expanded
+// like a two-digit date year. Months outside 01-12 (unknown or fictitious
+// months) yield no birth date but do not reject the match: the NIR validator
+// has already accepted it.
+func extractNIRBirth(s string) (map[string]string, bool) {
+	meta := map[string]string{"id_scheme": "nir"}
+	if len(s) < 5 || !isDigits(s[1:5]) {
+		return meta, true
+	}
+	y, m := expandYear(atoi(s[1:3])), atoi(s[3:5])
+	if _, ok := isoDate(y, m, 1); ok {
+		meta["birth_date"] = isoDate7(y, m)
+	}
+	return meta, true
+}
+
+// extractCNPBirth reads the birth date of a Romanian CNP (S YY MM DD ...).
+// The sex digit S encodes the century: 1/2 → 1900s, 3/4 → 1800s,
+// 5/6 → 2000s; 7/8 (foreign residents) leave it open.
+func extractCNPBirth(s string) (map[string]string, bool) {
+	if len(s) < 7 || !isDigits(s[:7]) {
+		return nil, false
+	}
+	yy := atoi(s[1:3])
+	var y int
+	switch s[0] {
+	case '1', '2':
+		y = 1900 + yy
+	case '3', '4':
+		y = 1800 + yy
+	case '5', '6':
+		y = 2000 + yy
+	default:
+		y = expandYear(yy)
+	}
+	return birthMeta("cnp", y, atoi(s[3:5]), atoi(s[5:7]))
+}
+
+// peselCentury maps a PESEL month offset (month / 20) to its century.
+var peselCentury = [5]int{1900, 2000, 2100, 2200, 1800}
+
+// extractPESELBirth reads the birth date of a Polish PESEL (YY MM DD ...),
+// whose month carries the century as an offset: +80 → 1800s, +0 → 1900s,
+// +20 → 2000s, +40 → 2100s, +60 → 2200s.
+func extractPESELBirth(s string) (map[string]string, bool) {
+	if len(s) < 6 || !isDigits(s[:6]) {
+		return nil, false
+	}
+	yy, mm := atoi(s[0:2]), atoi(s[2:4])
+	return birthMeta("pesel", peselCentury[mm/20]+yy, mm%20, atoi(s[4:6]))
+}
+
+// extractHETUBirth reads the birth date of a Finnish HETU (DDMMYY C ...),
+// where C is the century sign: + → 1800s, - or U-Y → 1900s, A-F → 2000s.
+func extractHETUBirth(s string) (map[string]string, bool) {
+	if len(s) < 7 || !isDigits(s[:6]) {
+		return nil, false
+	}
+	var century int
+	switch c := s[6]; {
+	case c == '+':
+		century = 1800
+	case c == '-' || (c >= 'U' && c <= 'Y'):
+		century = 1900
+	case c >= 'A' && c <= 'F':
+		century = 2000
+	default:
+		return nil, false
+	}
+	return birthMeta("hetu", century+atoi(s[4:6]), atoi(s[2:4]), atoi(s[0:2]))
+}
+
+// birthMeta validates a full birth date; an impossible date rejects the match.
+func birthMeta(scheme string, y, m, d int) (map[string]string, bool) {
+	iso, ok := isoDate(y, m, d)
+	if !ok {
+		return nil, false
+	}
+	return map[string]string{"id_scheme": scheme, "birth_date": iso}, true
+}
+
+// isoDate7 formats a year and month as YYYY-MM.
+func isoDate7(y, m int) string {
+	iso, _ := isoDate(y, m, 1)
+	return iso[:7]
+}
+
+// BirthDateLink ties a national ID to a date term of the same batch equal to
+// the birth date the ID encodes. BirthDate is YYYY-MM when the ID only encodes
+// year and month (NIR).
+type BirthDateLink struct {
+	IDTerm    string `json:"id_term"`
+	IDDict    string `json:"id_dict"`
+	IDScheme  string `json:"id_scheme"`
+	BirthDate string `json:"birth_date"`
+	DateTerm  string `json:"date_term"`
+	Date      string `json:"date"`
+}
+
+// LinkBirthDates cross-checks classified terms: every match carrying a
+// "birth_date" is compared with every match carrying a "date" or "date_alt",
+// and each agreement becomes a link. Results are scanned in order, so links
+// are deterministic for a given batch.
+func LinkBirthDates(results []*ClassifyResult) []BirthDateLink {
+	type dated struct{ term, date string }
+	var dates []dated
+	for _, r := range results {
+		for _, m := range r.Matches {
+			for _, key := range []string{"date", "date_alt"} {
+				if v := m.Metadata[key]; v != "" {
+					dates = append(dates, dated{r.Term, v})
+				}
+			}
+		}
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+
+	var links []BirthDateLink
+	for _, r := range results {
+		for _, m := range r.Matches {
+			birth := m.Metadata["birth_date"]
+			if birth == "" {
+				continue
+			}
+			for _, d := range dates {
+				if d.term == r.Term || !sameBirthDate(birth, d.date) {
+					continue
+				}
+				links = append(links, BirthDateLink{
+					IDTerm:    r.Term,
+					IDDict:    m.DictID,
+					IDScheme:  m.Metadata["id_scheme"],
+					BirthDate: birth,
+					DateTerm:  d.term,
+					Date:      d.date,
+				})
+			}
+		}
+	}
+	return links
+}
+
+// sameBirthDate reports whether an ISO date agrees with a birth date that may
+// be truncated to YYYY-MM.
+func sameBirthDate(birth, date string) bool {
+	if len(birth) == len(date) {
+		return birth == date
+	}
+	return strings.HasPrefix(date, birth+"-")
+}
@@ -0,0 +1,78 @@
+package dict
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractBirthDates(t *testing.T) {
+	tests := []struct {
+		fn    ExtractorFunc
+		in    string
+		birth string
+		ok    bool
+	}{
+		{extractNIRBirth, "185017512345609", "1985-01", true},
+		{extractNIRBirth, "185997512345609", "", true}, // unknown month: no date, still a NIR
+		{extractCNPBirth, "1850112400015", "1985-01-12", true},
+		{extractCNPBirth, "5020229400015", "", false}, // 2002-02-29
+		{extractPESELBirth, "44051401359", "1944-05-14", true},
+		{extractPESELBirth, "02270803624", "2002-07-08", true},
+		{extractPESELBirth, "44133001359", "", false},
+		{extractHETUBirth, "131052-308T", "1952-10-13", true},
+		{extractHETUBirth, "131052A308T", "2052-10-13", true},
+		{extractHETUBirth, "131052Y308T", "1952-10-13", true},
+		{extractHETUBirth, "131052Z308T", "", false},
+	}
+	for _, tt := range tests {
+		meta, ok := tt.fn(tt.in)
+		if ok != tt.ok {
+			t.Errorf("%q: ok = %v, want %v", tt.in, ok, tt.ok)
+			continue
+		}
+		if ok && meta["birth_date"] != tt.birth {
+			t.Errorf("%q: birth_date = %q, want %q", tt.in, meta["birth_date"], tt.birth)
+		}
+	}
+}
+
+func TestLinkBirthDates(t *testing.T) {
+	var dicts []*Dictionary
+	for _, id := range []string{"dates", "national-ids-eu"} {
+		d, err := LoadDictionary(filepath.Join("..", "..", "dicts", id))
+		if err != nil {
+			t.Fatalf("LoadDictionary(%s): %v", id, err)
+		}
+		dicts = append(dicts, d)
+	}
+
+	terms := []string{"1850112400015", "DUPONT", "12/01/1985", "44051401359", "14 mai 1944", "185017512345609"}
+	results := make([]*ClassifyResult, len(terms))
+	for i, term := range terms {
+		results[i] = &ClassifyResult{Term: term}
+		for _, d := range dicts {
+			if entry, ok := d.Classify(term); ok {
+				results[i].Matches = append(results[i].Matches, Match{DictID: d.Manifest.ID, Metadata: entry.Metadata})
+			}
+		}
+	}
+
+	links := LinkBirthDates(results)
+	want := []BirthDateLink{
+		{IDTerm: "1850112400015", IDDict: "national-ids-eu", IDScheme: "cnp", BirthDate: "1985-01-12", DateTerm: "12/01/1985", Date: "1985-01-12"},
+		{IDTerm: "44051401359", IDDict: "national-ids-eu", IDScheme: "pesel", BirthDate: "1944-05-14", DateTerm: "14 mai 1944", Date: "1944-05-14"},
+		{IDTerm: "185017512345609", IDDict: "national-ids-eu", IDScheme: "nir", BirthDate: "1985-01", DateTerm: "12/01/1985", Date: "1985-01-12"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("links = %+v, want %+v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("links[%d] = %+v, want %+v", i, links[i], want[i])
+		}
+	}
+
+	if links := LinkBirthDates(results[:2]); links != nil {
+		t.Errorf("no date terms: links = %+v, want nil", links)
+	}
+}
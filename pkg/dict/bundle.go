@@ -0,0 +1,360 @@
+// CLAUDE:SUMMARY Offline export bundles: a single gzip'd tar of selected dictionaries (manifests, data, mappings) with a versioned, Ed25519-signed bundle.json listing SHA-256 digests; OpenBundle verifies and loads one into an embedded Registry.
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/dict/dict.go, pkg/dict/manifest.go
+// CLAUDE:EXPORTS BundleFormat, BundleManifest, BundleDict, BundleFile, BundleOptions, Bundle, WriteBundle, OpenBundle, ErrBundleSignature
+
+package dict
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BundleFormat identifies the bundle layout, recorded in bundle.json.
+const BundleFormat = "touchstone-bundle/1"
+
+// Bundle members besides the dictionary files.
+const (
+	bundleManifestName = "bundle.json"
+	bundleSigName      = "bundle.sig"
+)
+
+// BundleManifest is the bundle.json of a bundle. It lists the digest of every
+// dictionary file, so its signature covers the whole bundle.
+type BundleManifest struct {
+	Format  string       `json:"format"`
+	Version string       `json:"version"`
+	Created time.Time    `json:"created"`
+	Signed  bool         `json:"signed"`
+	Dicts   []BundleDict `json:"dicts"`
+}
+
+// BundleDict is one dictionary of a bundle.
+type BundleDict struct {
+	ID      string       `json:"id"`
+	Version string       `json:"version"`
+	Files   []BundleFile `json:"files"`
+}
+
+// BundleFile is one file of a dictionary, Path relative to its directory.
+type BundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteBundle writes the dictionaries ids of dictsDir (all of them when ids
+// is empty) to w as a bundle of the given version. Each dictionary carries
+// its manifest, the data file it loads from (data.db, data.gob or its CSV),
+// its provenance.json if any and the mapping files of its response_fields.
+// A non-nil key signs bundle.json.
+func WriteBundle(w io.Writer, dictsDir string, ids []string, version string, key ed25519.PrivateKey) (*BundleManifest, error) {
+	if len(ids) == 0 {
+		entries, err := os.ReadDir(dictsDir)
+		if err != nil {
+			return nil, fmt.Errorf("read dicts dir %s: %w", dictsDir, err)
+		}
+		for _, e := range entries {
+			if _, err := os.Stat(filepath.Join(dictsDir, e.Name(), "manifest.yaml")); IsDictDir(e.Name()) && err == nil {
+				ids = append(ids, e.Name())
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	bm := &BundleManifest{Format: BundleFormat, Version: version, Created: time.Now().UTC(), Signed: key != nil}
+	for _, id := range ids {
+		bd, err := bundleDict(filepath.Join(dictsDir, id))
+		if err != nil {
+			return nil, err
+		}
+		bd.ID = id
+		bm.Dicts = append(bm.Dicts, bd)
+	}
+
+	manifestJSON, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal bundle manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, bundleManifestName, bytes.NewReader(manifestJSON), int64(len(manifestJSON))); err != nil {
+		return nil, err
+	}
+	if key != nil {
+		sig := []byte(hex.EncodeToString(ed25519.Sign(key, manifestJSON)))
+		if err := writeTarFile(tw, bundleSigName, bytes.NewReader(sig), int64(len(sig))); err != nil {
+			return nil, err
+		}
+	}
+	for _, bd := range bm.Dicts {
+		for _, f := range bd.Files {
+			if err := copyToTar(tw, path.Join(bd.ID, f.Path), filepath.Join(dictsDir, bd.ID, f.Path), f.Size); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("close tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("close gzip: %w", err)
+	}
+	return bm, nil
+}
+
+// bundleDict lists and digests the files of the dictionary in dir.
+func bundleDict(dir string) (BundleDict, error) {
+	m, err := LoadManifest(filepath.Join(dir, "manifest.yaml"))
+	if err != nil {
+		return BundleDict{}, err
+	}
+	bd := BundleDict{Version: m.Version}
+
+	files := []string{"manifest.yaml"}
+	if m.Method != "pattern" && m.Type != "alias_pool" {
+		data := ""
+		for _, name := range []string{"data.db", "data.gob", m.DataFile} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				data = name
+				break
+			}
+		}
+		if data == "" {
+			return BundleDict{}, fmt.Errorf("dict %s: no data file", m.ID)
+		}
+		files = append(files, data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ProvenanceFile)); err == nil {
+		files = append(files, ProvenanceFile)
+	}
+	seen := map[string]bool{}
+	for _, rf := range m.ResponseFields {
+		if rf.Mapping != "" && !seen[rf.Mapping] {
+			seen[rf.Mapping] = true
+			files = append(files, filepath.ToSlash(rf.Mapping))
+		}
+	}
+
+	for _, name := range files {
+		if !localPath(name) {
+			return BundleDict{}, fmt.Errorf("dict %s: file %q outside the dictionary directory", m.ID, name)
+		}
+		sum, size, err := digestFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return BundleDict{}, fmt.Errorf("dict %s: %w", m.ID, err)
+		}
+		bd.Files = append(bd.Files, BundleFile{Path: name, Size: size, SHA256: sum})
+	}
+	return bd, nil
+}
+
+func digestFile(p string) (string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("read %s: %w", p, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: time.Now().UTC(), Typeflag: tar.TypeReg}); err != nil {
+		return fmt.Errorf("write %s header: %w", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func copyToTar(tw *tar.Writer, name, src string, size int64) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// The file must not grow past its digest while copied.
+	return writeTarFile(tw, name, io.LimitReader(f, size), size)
+}
+
+// localPath reports whether a slash-separated bundle path stays inside its
+// directory.
+func localPath(p string) bool {
+	return p != "" && !strings.HasPrefix(p, "/") && filepath.IsLocal(filepath.FromSlash(p))
+}
+
+// BundleOptions configures OpenBundle.
+type BundleOptions struct {
+	// PublicKey, when set, requires a bundle signed by its private key.
+	PublicKey ed25519.PublicKey
+	// TempDir is where the bundle is unpacked; os.TempDir when empty.
+	TempDir string
+}
+
+// Bundle is an opened bundle: a Registry answering Classify, Resolve and the
+// other lookups exactly as the server does, without any network access.
+type Bundle struct {
+	*Registry
+	Manifest BundleManifest
+	dir      string
+}
+
+// ErrBundleSignature reports a missing or invalid bundle signature.
+var ErrBundleSignature = errors.New("bundle signature verification failed")
+
+// OpenBundle verifies and loads the bundle at path. When opts.PublicKey is
+// set, bundle.json is checked against its signature before any file is
+// unpacked; every file is checked against the digests of bundle.json. Close the bundle to release it.
+func OpenBundle(bundlePath string, opts *BundleOptions) (*Bundle, error) {
+	if opts == nil {
+		opts = &BundleOptions{}
+	}
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	manifestJSON, err := readTarMember(tr, bundleManifestName)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PublicKey != nil {
+		// WriteBundle signs bundle.json as the second member: verify it
+		// before anything is unpacked.
+		sig, err := readTarMember(tr, bundleSigName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBundleSignature, err)
+		}
+		raw, err := hex.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || !ed25519.Verify(opts.PublicKey, manifestJSON, raw) {
+			return nil, ErrBundleSignature
+		}
+	}
+	var bm BundleManifest
+	if err := json.Unmarshal(manifestJSON, &bm); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", bundleManifestName, err)
+	}
+	if bm.Format != BundleFormat {
+		return nil, fmt.Errorf("unsupported bundle format %q", bm.Format)
+	}
+
+	expected := make(map[string]BundleFile)
+	for _, bd := range bm.Dicts {
+		for _, bf := range bd.Files {
+			expected[path.Join(bd.ID, bf.Path)] = bf
+		}
+	}
+
+	dir, err := os.MkdirTemp(opts.TempDir, "touchstone-bundle-")
+	if err != nil {
+		return nil, fmt.Errorf("create bundle dir: %w", err)
+	}
+	fail := func(err error) (*Bundle, error) {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(fmt.Errorf("read bundle: %w", err))
+		}
+		if hdr.Name == bundleSigName && opts.PublicKey == nil {
+			continue // unverified
+		}
+		bf, ok := expected[hdr.Name]
+		if !ok || !localPath(hdr.Name) || hdr.Typeflag != tar.TypeReg {
+			return fail(fmt.Errorf("unexpected bundle member %q", hdr.Name))
+		}
+		delete(expected, hdr.Name)
+		if err := extractFile(tr, filepath.Join(dir, filepath.FromSlash(hdr.Name)), bf); err != nil {
+			return fail(fmt.Errorf("%s: %w", hdr.Name, err))
+		}
+	}
+	for name := range expected {
+		return fail(fmt.Errorf("bundle member %q missing", name))
+	}
+
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		return fail(fmt.Errorf("load bundle: %w", err))
+	}
+	return &Bundle{Registry: reg, Manifest: bm, dir: dir}, nil
+}
+
+// Close releases the bundle's dictionaries and removes its unpacked files.
+func (b *Bundle) Close() error {
+	err := b.Registry.Close()
+	if rmErr := os.RemoveAll(b.dir); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// readTarMember reads the next member of tr, which must be name.
+func readTarMember(tr *tar.Reader, name string) ([]byte, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read bundle: %w", err)
+	}
+	if hdr.Name != name {
+		return nil, fmt.Errorf("bundle starts with %q, want %s", hdr.Name, name)
+	}
+	data, err := io.ReadAll(io.LimitReader(tr, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// extractFile writes the current tar member to dst, checking it against bf.
+func extractFile(r io.Reader, dst string, bf BundleFile) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), io.LimitReader(r, bf.Size+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != bf.Size || hex.EncodeToString(h.Sum(nil)) != bf.SHA256 {
+		return fmt.Errorf("digest mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package dict
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupBundleDicts writes a CSV dictionary with a mapping, a SQLite one and a
+// pattern one.
+func setupBundleDicts(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("naf/manifest.yaml", `id: naf
+version: "2.1"
+jurisdiction: fr
+entity_type: activity
+source: test
+data_file: data.csv
+response_fields:
+  - name: section
+    column: section
+    mapping: sections.json
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "code"
+  normalize: lowercase_ascii
+metadata_columns:
+  - name: section
+    column: "section"
+`)
+	write("naf/data.csv", "code;section\n62.01Z;J\n")
+	write("naf/sections.json", `{"J": "Information et communication"}`)
+
+	write("noms/manifest.yaml", `id: noms
+version: "1.0"
+jurisdiction: fr
+entity_type: surname
+source: test
+format:
+  normalize: lowercase_ascii
+`)
+	if err := SaveSQLite(map[string]*Entry{"dupont": {Metadata: map[string]string{"frequency": "1200"}}}, filepath.Join(dir, "noms", "data.db")); err != nil {
+		t.Fatal(err)
+	}
+
+	write("emails/manifest.yaml", `id: emails
+version: "1.0"
+jurisdiction: intl
+entity_type: email
+source: test
+method: pattern
+patterns:
+  - name: email
+    regex: "^[^@]+@[^@]+$"
+`)
+	return dir
+}
+
+func writeTestBundle(t *testing.T, dictsDir string, ids []string, key ed25519.PrivateKey) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "dicts.tsb")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := WriteBundle(f, dictsDir, ids, "2026.10", key); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+	return p
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	dictsDir := setupBundleDicts(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := writeTestBundle(t, dictsDir, nil, priv)
+
+	b, err := OpenBundle(p, &BundleOptions{PublicKey: pub, TempDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("OpenBundle: %v", err)
+	}
+	defer b.Close()
+
+	if b.Manifest.Version != "2026.10" || !b.Manifest.Signed || len(b.Manifest.Dicts) != 3 {
+		t.Errorf("manifest = %+v", b.Manifest)
+	}
+
+	server := NewRegistry(dictsDir)
+	if err := server.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	for _, term := range []string{"Dupont", "62.01Z", "a@b.fr", "inconnu"} {
+		got, want := b.Classify(term, nil), server.Classify(term, nil)
+		if len(got.Matches) != len(want.Matches) {
+			t.Errorf("Classify(%q) = %+v, want %+v", term, got, want)
+		}
+	}
+	if res := b.Resolve("62.01Z", nil); !res.Match || res.Data["section"] != "Information et communication" {
+		t.Errorf("Resolve = %+v", res)
+	}
+}
+
+func TestBundle_Selection(t *testing.T) {
+	p := writeTestBundle(t, setupBundleDicts(t), []string{"noms"}, nil)
+	b, err := OpenBundle(p, nil)
+	if err != nil {
+		t.Fatalf("OpenBundle: %v", err)
+	}
+	defer b.Close()
+	if b.DictCount() != 1 || b.Manifest.Signed {
+		t.Errorf("dicts = %d, signed = %v", b.DictCount(), b.Manifest.Signed)
+	}
+}
+
+func TestBundle_Verification(t *testing.T) {
+	dictsDir := setupBundleDicts(t)
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	unsigned := writeTestBundle(t, dictsDir, nil, nil)
+	if _, err := OpenBundle(unsigned, &BundleOptions{PublicKey: pub}); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("unsigned bundle: err = %v", err)
+	}
+	signed := writeTestBundle(t, dictsDir, nil, priv)
+	if _, err := OpenBundle(signed, &BundleOptions{PublicKey: otherPub}); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("wrong key: err = %v", err)
+	}
+
+	// Rewrite the bundle with one data file altered.
+	tampered := filepath.Join(t.TempDir(), "tampered.tsb")
+	in, _ := os.Open(signed)
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tr, tw := tar.NewReader(gz), tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if hdr.Name == "naf/data.csv" {
+			data = bytes.Replace(data, []byte("62.01Z"), []byte("62.02Z"), 1)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	zw.Close()
+	if err := os.WriteFile(tampered, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBundle(tampered, &BundleOptions{PublicKey: pub}); err == nil {
+		t.Error("tampered bundle: expected error")
+	}
+	// The signature is checked before the altered file is unpacked.
+	if _, err := OpenBundle(tampered, &BundleOptions{PublicKey: otherPub}); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("tampered bundle, wrong key: err = %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+// CLAUDE:SUMMARY Declarative weighted-sum modulus checksum validators compiled from manifest checksum specs.
+// CLAUDE:DEPENDS pkg/dict/manifest.go
+// CLAUDE:EXPORTS ChecksumSpec
+package dict
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChecksumSpec declares a weighted-sum modulus check, enough to express most
+// national ID and tax number schemes without Go code.
+//
+// The term is first cleaned (Ignore characters removed, upper-cased, Replace
+// applied), then split into a payload and a trailing check of CheckLength
+// characters. Each payload character is mapped to its index in InputAlphabet,
+// multiplied by the weight at the same position (weights repeat when shorter
+// than the payload), and summed. The remainder r = sum mod Modulus (or
+// (Modulus - r) mod Modulus when Complement is set) selects the expected check:
+// CheckAlphabet[r] for a single character, or r zero-padded to CheckLength
+// digits otherwise.
+type ChecksumSpec struct {
+	Weights       []int             `yaml:"weights" json:"weights"`
+	Modulus       int               `yaml:"modulus" json:"modulus"`
+	Complement    bool              `yaml:"complement,omitempty" json:"complement,omitempty"`
+	CheckAlphabet string            `yaml:"check_alphabet,omitempty" json:"check_alphabet,omitempty"` // default "0123456789"
+	CheckLength   int               `yaml:"check_length,omitempty" json:"check_length,omitempty"`     // default 1
+	InputAlphabet string            `yaml:"input_alphabet,omitempty" json:"input_alphabet,omitempty"` // default "0123456789"
+	Replace       map[string]string `yaml:"replace,omitempty" json:"replace,omitempty"`
+	Ignore        string            `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+}
+
+const digitAlphabet = "0123456789"
+
+// compileChecksum turns a ChecksumSpec into a validator function.
+func compileChecksum(spec *ChecksumSpec) (ValidatorFunc, error) {
+	if spec.Modulus < 2 {
+		return nil, fmt.Errorf("checksum: modulus must be >= 2, got %d", spec.Modulus)
+	}
+	if len(spec.Weights) == 0 {
+		return nil, fmt.Errorf("checksum: weights are required")
+	}
+	checkLen := spec.CheckLength
+	if checkLen == 0 {
+		checkLen = 1
+	}
+	if checkLen < 0 {
+		return nil, fmt.Errorf("checksum: check_length must be positive, got %d", checkLen)
+	}
+	if checkLen > 1 && spec.CheckAlphabet != "" {
+		return nil, fmt.Errorf("checksum: check_alphabet only applies to single-character checks")
+	}
+	checkAlphabet := []rune(strings.ToUpper(spec.CheckAlphabet))
+	if len(checkAlphabet) == 0 {
+		checkAlphabet = []rune(digitAlphabet)
+	}
+	inputAlphabet := strings.ToUpper(spec.InputAlphabet)
+	if inputAlphabet == "" {
+		inputAlphabet = digitAlphabet
+	}
+	values := make(map[rune]int, len(inputAlphabet))
+	for i, c := range []rune(inputAlphabet) {
+		values[c] = i
+	}
+
+	// Apply replacements longest-first, then lexically, for deterministic results.
+	replace := make(map[string]string, len(spec.Replace))
+	replaceKeys := make([]string, 0, len(spec.Replace))
+	for k, v := range spec.Replace {
+		replace[strings.ToUpper(k)] = strings.ToUpper(v)
+		replaceKeys = append(replaceKeys, strings.ToUpper(k))
+	}
+	sort.Slice(replaceKeys, func(i, j int) bool {
+		if len(replaceKeys[i]) != len(replaceKeys[j]) {
+			return len(replaceKeys[i]) > len(replaceKeys[j])
+		}
+		return replaceKeys[i] < replaceKeys[j]
+	})
+
+	weights := append([]int(nil), spec.Weights...)
+	modulus := spec.Modulus
+	complement := spec.Complement
+	ignore := spec.Ignore
+
+	return func(s string) bool {
+		if ignore != "" {
+			s = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ignore, r) {
+					return -1
+				}
+				return r
+			}, s)
+		}
+		s = strings.ToUpper(s)
+		for _, k := range replaceKeys {
+			s = strings.ReplaceAll(s, k, replace[k])
+		}
+
+		runes := []rune(s)
+		if len(runes) <= checkLen {
+			return false
+		}
+		payload, check := runes[:len(runes)-checkLen], string(runes[len(runes)-checkLen:])
+
+		var sum int
+		for i, c := range payload {
+			v, ok := values[c]
+			if !ok {
+				return false
+			}
+			sum += v * weights[i%len(weights)]
+		}
+		r := sum % modulus
+		if r < 0 {
+			r += modulus
+		}
+		if complement {
+			r = (modulus - r) % modulus
+		}
+
+		if checkLen == 1 {
+			return r < len(checkAlphabet) && string(checkAlphabet[r]) == check
+		}
+		want := strconv.Itoa(r)
+		if len(want) > checkLen {
+			return false
+		}
+		return strings.Repeat("0", checkLen-len(want))+want == check
+	}, nil
+}
@@ -0,0 +1,168 @@
+package dict
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCompileChecksum(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ChecksumSpec
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "dni_es",
+			spec:    ChecksumSpec{Weights: []int{14, 6, 19, 18, 11, 8, 10, 1}, Modulus: 23, CheckAlphabet: "TRWAGMYFPDXBNJZSQVHLCKE"},
+			valid:   []string{"12345678Z", "12345678z"},
+			invalid: []string{"12345678A", "1234567Z", "ABCDEFGHZ"},
+		},
+		{
+			name: "nie_es",
+			spec: ChecksumSpec{
+				Replace: map[string]string{"X": "0", "Y": "1", "Z": "2"},
+				Weights: []int{14, 6, 19, 18, 11, 8, 10, 1}, Modulus: 23, CheckAlphabet: "TRWAGMYFPDXBNJZSQVHLCKE",
+			},
+			valid:   []string{"X1234567L"},
+			invalid: []string{"X1234567T", "Y1234567L"},
+		},
+		{
+			name:    "bsn_nl",
+			spec:    ChecksumSpec{Weights: []int{9, 8, 7, 6, 5, 4, 3, 2}, Modulus: 11},
+			valid:   []string{"111222333"},
+			invalid: []string{"111222334"},
+		},
+		{
+			name:    "pesel_pl",
+			spec:    ChecksumSpec{Weights: []int{1, 3, 7, 9}, Modulus: 10, Complement: true},
+			valid:   []string{"44051401359"},
+			invalid: []string{"44051401358"},
+		},
+		{
+			name:    "nif_pt",
+			spec:    ChecksumSpec{Weights: []int{9, 8, 7, 6, 5, 4, 3, 2}, Modulus: 11, Complement: true, CheckAlphabet: "01234567890"},
+			valid:   []string{"123456789"},
+			invalid: []string{"123456780"},
+		},
+		{
+			name: "nir_fr",
+			spec: ChecksumSpec{
+				Weights: []int{50, 5, 49, 34, 81, 76, 27, 90, 9, 30, 3, 10, 1}, Modulus: 97, Complement: true, CheckLength: 2,
+				Replace: map[string]string{"2A": "19", "2B": "20"},
+			},
+			valid:   []string{"185057800608491"},
+			invalid: []string{"185057800608400", "18505780060849"},
+		},
+		{
+			name:    "ignore separators",
+			spec:    ChecksumSpec{Weights: []int{9, 8, 7, 6, 5, 4, 3, 2}, Modulus: 11, Ignore: ".-"},
+			valid:   []string{"111.222.333", "1112-22333"},
+			invalid: []string{"111.222.334"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := compileChecksum(&tt.spec)
+			if err != nil {
+				t.Fatalf("compileChecksum: %v", err)
+			}
+			for _, s := range tt.valid {
+				if !fn(s) {
+					t.Errorf("checksum(%q) = false, want true", s)
+				}
+			}
+			for _, s := range tt.invalid {
+				if fn(s) {
+					t.Errorf("checksum(%q) = true, want false", s)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileChecksum_Invalid(t *testing.T) {
+	specs := []ChecksumSpec{
+		{Weights: []int{1}, Modulus: 1},
+		{Modulus: 11},
+		{Weights: []int{1}, Modulus: 97, CheckLength: 2, CheckAlphabet: "0123456789"},
+		{Weights: []int{1}, Modulus: 11, CheckLength: -1},
+	}
+	for i := range specs {
+		if _, err := compileChecksum(&specs[i]); err == nil {
+			t.Errorf("spec %d: expected error", i)
+		}
+	}
+}
+
+func TestPatternMatcher_Checksum(t *testing.T) {
+	specs := []PatternSpec{
+		{Name: "dni_es", Regex: `^\d{8}[A-Z]$`, Checksum: &ChecksumSpec{
+			Weights: []int{14, 6, 19, 18, 11, 8, 10, 1}, Modulus: 23, CheckAlphabet: "TRWAGMYFPDXBNJZSQVHLCKE",
+		}},
+	}
+	pm, err := compilePatterns(specs)
+	if err != nil {
+		t.Fatalf("compilePatterns: %v", err)
+	}
+	if name, ok := matchFirst(pm, "12345678Z"); !ok || name != "dni_es" {
+		t.Errorf("match(12345678Z) = %q, %v; want dni_es, true", name, ok)
+	}
+	if _, ok := matchFirst(pm, "12345678A"); ok {
+		t.Error("expected no match for bad DNI letter")
+	}
+}
+
+func TestPatternMatcher_ValidatorAndChecksum(t *testing.T) {
+	// Both must pass: luhn accepts 79927398713, the mod 10 weights do not.
+	specs := []PatternSpec{
+		{Name: "both", Regex: `^\d+$`, Validator: "luhn", Checksum: &ChecksumSpec{Weights: []int{1}, Modulus: 10}},
+	}
+	pm, err := compilePatterns(specs)
+	if err != nil {
+		t.Fatalf("compilePatterns: %v", err)
+	}
+	if _, ok := matchFirst(pm, "79927398713"); ok {
+		t.Error("expected no match when checksum fails")
+	}
+}
+
+func TestLoadDictionary_NationalIDsChecksums(t *testing.T) {
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", "national-ids-eu"))
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+
+	tests := []struct {
+		term    string
+		pattern string
+	}{
+		{"12345678Z", "dni_es"},
+		{"X1234567L", "nie_es"},
+		{"111222333", "bsn_nl"},
+		{"1800101221144", "cnp_ro"},
+	}
+	for _, tt := range tests {
+		entry, ok := d.Classify(tt.term)
+		if !ok {
+			t.Errorf("Classify(%q): no match", tt.term)
+			continue
+		}
+		if entry.Metadata["pattern"] != tt.pattern {
+			t.Errorf("Classify(%q) pattern = %q, want %q", tt.term, entry.Metadata["pattern"], tt.pattern)
+		}
+	}
+
+	// PESEL shares its shape with Steuer-ID and OIB; only the checksum tells them apart.
+	if got := d.MatchPatterns("44051401359"); !containsString(got, "pesel_pl") {
+		t.Errorf("MatchPatterns(44051401359) = %v, want pesel_pl among them", got)
+	}
+	if got := d.MatchPatterns("44051401358"); containsString(got, "pesel_pl") {
+		t.Errorf("MatchPatterns(44051401358) = %v, want no pesel_pl", got)
+	}
+
+	if _, ok := d.Classify("12345678A"); ok {
+		t.Error("expected no match for DNI with wrong letter")
+	}
+}
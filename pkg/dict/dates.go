@@ -0,0 +1,231 @@
+// CLAUDE:SUMMARY Locale-aware date extractors (ISO, numeric day/month order, written FR/EN/DE/ES month names) returning ISO dates.
+// CLAUDE:DEPENDS pkg/dict/extractor.go, pkg/dict/normalize.go
+package dict
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// monthNames maps month names and common abbreviations in French, English,
+// German and Spanish, normalized with NormalizeLowercaseASCII and stripped of
+// dots, to month numbers.
+var monthNames = map[string]int{
+	// French
+	"janvier": 1, "janv": 1, "fevrier": 2, "fevr": 2, "fev": 2, "mars": 3, "avril": 4, "avr": 4,
+	"mai": 5, "juin": 6, "juillet": 7, "juil": 7, "aout": 8, "septembre": 9, "sept": 9,
+	"octobre": 10, "novembre": 11, "decembre": 12,
+	// English
+	"january": 1, "jan": 1, "february": 2, "feb": 2, "march": 3, "mar": 3, "april": 4, "apr": 4,
+	"may": 5, "june": 6, "jun": 6, "july": 7, "jul": 7, "august": 8, "aug": 8,
+	"september": 9, "sep": 9, "october": 10, "oct": 10, "november": 11, "nov": 11,
+	"december": 12, "dec": 12,
+	// German
+	"januar": 1, "janner": 1, "februar": 2, "marz": 3, "maerz": 3, "juni": 6, "juli": 7,
+	"oktober": 10, "okt": 10, "dezember": 12, "dez": 12,
+	// Spanish
+	"enero": 1, "ene": 1, "febrero": 2, "marzo": 3, "abril": 4, "abr": 4, "mayo": 5,
+	"junio": 6, "julio": 7, "agosto": 8, "ago": 8, "septiembre": 9, "setiembre": 9,
+	"octubre": 10, "noviembre": 11, "diciembre": 12, "dic": 12,
+}
+
+// ordinalSuffixes are day suffixes that may precede the month name once
+// spaces are stripped ("1er janvier" → "1erjanvier", "12th May" → "12thmay").
+var ordinalSuffixes = []string{"er", "st", "nd", "rd", "th"}
+
+// extractDateYMD parses year-first numeric dates (1985-01-12, 1985/1/12).
+func extractDateYMD(s string) (map[string]string, bool) {
+	parts := splitDate(s)
+	if len(parts) != 3 || len(parts[0]) != 4 {
+		return nil, false
+	}
+	y, m, d := atoi(parts[0]), atoi(parts[1]), atoi(parts[2])
+	iso, ok := isoDate(y, m, d)
+	if !ok {
+		return nil, false
+	}
+	return dateMeta(iso, ""), true
+}
+
+// extractDateNumeric parses day/month/year numeric dates (12/01/85,
+// 12.01.1985). Day-first, the order used in French, German, Spanish and
+// British English, wins; when the month-first reading is also a valid and
+// different date it is returned as "date_alt".
+func extractDateNumeric(s string) (map[string]string, bool) {
+	parts := splitDate(s)
+	if len(parts) != 3 {
+		return nil, false
+	}
+	if len(parts[0]) == 4 {
+		return extractDateYMD(s)
+	}
+	a, b := atoi(parts[0]), atoi(parts[1])
+	y, ok := parseYear(parts[2])
+	if !ok {
+		return nil, false
+	}
+
+	dmy, dmyOK := isoDate(y, b, a)
+	mdy, mdyOK := isoDate(y, a, b)
+	switch {
+	case dmyOK && mdyOK && dmy != mdy:
+		return dateMeta(dmy, mdy), true
+	case dmyOK:
+		return dateMeta(dmy, ""), true
+	case mdyOK:
+		return dateMeta(mdy, ""), true
+	}
+	return nil, false
+}
+
+// extractDateWritten parses dates with a written month, day-first
+// ("12 janvier 1985", "1er mai 2001", "12. März 1985", "12 de enero de 1985")
+// or month-first ("January 12, 1985").
+func extractDateWritten(s string) (map[string]string, bool) {
+	s = strings.NewReplacer(".", "", ",", "").Replace(NormalizeLowercaseASCII(s))
+	if len(s) < 8 {
+		return nil, false
+	}
+
+	// Trailing 4-digit year.
+	yearStr := s[len(s)-4:]
+	if !isDigits(yearStr) {
+		return nil, false
+	}
+	y := atoi(yearStr)
+	rest := s[:len(s)-4]
+
+	var dayStr, word string
+	if rest != "" && isDigit(rest[0]) {
+		i := leadingDigits(rest)
+		dayStr, word = rest[:i], rest[i:]
+	} else {
+		i := strings.IndexFunc(rest, func(r rune) bool { return r >= '0' && r <= '9' })
+		if i < 0 {
+			return nil, false
+		}
+		word = rest[:i]
+		j := i + leadingDigits(rest[i:])
+		dayStr = rest[i:j]
+		if suffix := rest[j:]; suffix != "" && !containsString(ordinalSuffixes, suffix) {
+			return nil, false
+		}
+	}
+	if dayStr == "" || len(dayStr) > 2 {
+		return nil, false
+	}
+
+	m, ok := lookupMonth(word)
+	if !ok {
+		return nil, false
+	}
+	iso, ok := isoDate(y, m, atoi(dayStr))
+	if !ok {
+		return nil, false
+	}
+	return dateMeta(iso, ""), true
+}
+
+// dateMeta is the metadata of a date match. Both readings of an ambiguous
+// date share the year, which generalizes the date (see Registry.Generalize).
+func dateMeta(iso, alt string) map[string]string {
+	meta := map[string]string{"date": iso, "year": iso[:4]}
+	if alt != "" {
+		meta["date_alt"] = alt
+	}
+	return meta
+}
+
+// lookupMonth resolves a month word, tolerating a leading ordinal suffix and
+// the Spanish "de ... de" around the month ("deenerode" → enero).
+func lookupMonth(word string) (int, bool) {
+	bases := []string{word}
+	for _, suffix := range ordinalSuffixes {
+		if strings.HasPrefix(word, suffix) {
+			bases = append(bases, strings.TrimPrefix(word, suffix))
+		}
+	}
+	for _, base := range bases {
+		for _, candidate := range []string{
+			base,
+			strings.TrimPrefix(base, "de"),
+			strings.TrimSuffix(base, "de"),
+			strings.TrimSuffix(strings.TrimPrefix(base, "de"), "de"),
+		} {
+			if m, ok := monthNames[candidate]; ok {
+				return m, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// isoDate formats a calendar date as YYYY-MM-DD, rejecting impossible dates.
+func isoDate(y, m, d int) (string, bool) {
+	if y < 1000 || y > 2999 || m < 1 || m > 12 || d < 1 || d > 31 {
+		return "", false
+	}
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if t.Day() != d || int(t.Month()) != m {
+		return "", false
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", y, m, d), true
+}
+
+// parseYear parses a 2- or 4-digit year, expanding two-digit years.
+func parseYear(s string) (int, bool) {
+	switch len(s) {
+	case 2:
+		return expandYear(atoi(s)), true
+	case 4:
+		return atoi(s), true
+	}
+	return 0, false
+}
+
+// expandYear maps a two-digit year to the latest year not after the current
+// one: in 2026, 25 → 2025 and 85 → 1985. Dates in documents being mostly past
+// events and birth dates, this beats a fixed pivot.
+func expandYear(yy int) int {
+	now := time.Now().Year()
+	y := now - now%100 + yy
+	if y > now {
+		y -= 100
+	}
+	return y
+}
+
+func splitDate(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '/' || r == '.' })
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func leadingDigits(s string) int {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,99 @@
+package dict
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractDate(t *testing.T) {
+	tests := []struct {
+		fn       ExtractorFunc
+		in       string
+		date     string
+		dateAlt  string
+		rejected bool
+	}{
+		{extractDateYMD, "1985-01-12", "1985-01-12", "", false},
+		{extractDateYMD, "1985/1/2", "1985-01-02", "", false},
+		{extractDateYMD, "1985-02-30", "", "", true},
+		{extractDateNumeric, "12/01/85", "1985-01-12", "1985-12-01", false},
+		{extractDateNumeric, "12.01.1985", "1985-01-12", "1985-12-01", false},
+		{extractDateNumeric, "25-12-1985", "1985-12-25", "", false},
+		{extractDateNumeric, "12/25/1985", "1985-12-25", "", false}, // month-first only
+		{extractDateNumeric, "01/01/1985", "1985-01-01", "", false},
+		{extractDateNumeric, "31/31/1985", "", "", true},
+		{extractDateNumeric, "29/02/1985", "", "", true},
+		{extractDateWritten, "12janvier1985", "1985-01-12", "", false},
+		{extractDateWritten, "1ermai2001", "2001-05-01", "", false},
+		{extractDateWritten, "12.März1985", "1985-03-12", "", false},
+		{extractDateWritten, "3Dezember1999", "1999-12-03", "", false},
+		{extractDateWritten, "12deenerode1985", "1985-01-12", "", false},
+		{extractDateWritten, "5dediciembrede2020", "2020-12-05", "", false},
+		{extractDateWritten, "12thMay1985", "1985-05-12", "", false},
+		{extractDateWritten, "January12,1985", "1985-01-12", "", false},
+		{extractDateWritten, "Feb.3rd1990", "1990-02-03", "", false},
+		{extractDateWritten, "14févr.2000", "2000-02-14", "", false},
+		{extractDateWritten, "12foobar1985", "", "", true},
+		{extractDateWritten, "30février1985", "", "", true},
+		{extractDateWritten, "January12xx1985", "", "", true},
+	}
+	for _, tt := range tests {
+		meta, ok := tt.fn(tt.in)
+		if ok == tt.rejected {
+			t.Errorf("%q: ok = %v, want %v", tt.in, ok, !tt.rejected)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if meta["date"] != tt.date || meta["date_alt"] != tt.dateAlt {
+			t.Errorf("%q: date = %q, date_alt = %q; want %q, %q", tt.in, meta["date"], meta["date_alt"], tt.date, tt.dateAlt)
+		}
+	}
+}
+
+func TestExpandYear(t *testing.T) {
+	if got := expandYear(85); got != 1985 {
+		t.Errorf("expandYear(85) = %d, want 1985", got)
+	}
+	if got := expandYear(0); got != 2000 {
+		t.Errorf("expandYear(0) = %d, want 2000", got)
+	}
+}
+
+func TestDatesDict(t *testing.T) {
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", "dates"))
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+
+	tests := []struct {
+		term    string
+		pattern string
+		date    string
+	}{
+		{"1985-01-12", "date_iso", "1985-01-12"},
+		{"12/01/85", "date_numeric", "1985-01-12"},
+		{"12 janvier 1985", "date_written", "1985-01-12"},
+		{"12 JANVIER 1985", "date_written", "1985-01-12"},
+		{"12. März 1985", "date_written", "1985-03-12"},
+		{"12 de enero de 1985", "date_written", "1985-01-12"},
+		{"January 12, 1985", "date_written", "1985-01-12"},
+	}
+	for _, tt := range tests {
+		entry, ok := d.Classify(tt.term)
+		if !ok {
+			t.Errorf("Classify(%q): no match", tt.term)
+			continue
+		}
+		if entry.Metadata["pattern"] != tt.pattern || entry.Metadata["date"] != tt.date {
+			t.Errorf("Classify(%q) = %v, want pattern %s, date %s", tt.term, entry.Metadata, tt.pattern, tt.date)
+		}
+	}
+
+	for _, term := range []string{"31/02/1985", "12 foo 1985", "DUPONT", "1985"} {
+		if entry, ok := d.Classify(term); ok {
+			t.Errorf("Classify(%q) = %v, want no match", term, entry.Metadata)
+		}
+	}
+}
@@ -24,12 +24,15 @@ type Entry struct {
 // Dictionary is one loaded dictionary with its manifest and in-memory hashmap or SQLite backend.
 type Dictionary struct {
 	Manifest   *Manifest        `json:"manifest"`
+	Provenance *Provenance      `json:"provenance,omitempty"` // nil for dictionaries not built by the importer
 	Entries    map[string]*Entry `json:"-"`
 	normalize  Normalizer
 	patterns   *patternMatcher
 	dir        string  // directory where this dictionary was loaded from
-	db         *sql.DB // non-nil for SQLite-backed dicts
-	entryCount int     // cached entry count
+	db           *sql.DB // non-nil for SQLite-backed dicts
+	entryCount   int     // cached entry count
+	rangeIndexed bool    // data.db has a range_index table
+	totals       *Totals // metadata sums for risk estimates; nil when unknown
 }
 
 // LoadDictionary reads a manifest.yaml and loads data from gob, csv, or patterns.
@@ -46,6 +49,9 @@ func LoadDictionary(dir string) (*Dictionary, error) {
 		normalize: GetNormalizer(manifest.Format.Normalize),
 		dir:       dir,
 	}
+	if d.Provenance, err = LoadProvenance(dir); err != nil {
+		return nil, fmt.Errorf("dict %s: %w", manifest.ID, err)
+	}
 
 	// Pattern-based dictionaries: compile regexes, no data file.
 	if manifest.Method == "pattern" {
@@ -63,6 +69,9 @@ func LoadDictionary(dir string) (*Dictionary, error) {
 		if err := d.loadSQLite(dbPath); err != nil {
 			return nil, fmt.Errorf("dict %s: %w", manifest.ID, err)
 		}
+		if d.Provenance != nil {
+			d.totals = d.Provenance.Totals
+		}
 		return d, nil
 	}
 
@@ -73,6 +82,7 @@ func LoadDictionary(dir string) (*Dictionary, error) {
 			return nil, fmt.Errorf("dict %s: %w", manifest.ID, err)
 		}
 		d.entryCount = len(d.Entries)
+		d.sumTotals()
 		return d, nil
 	}
 
@@ -82,21 +92,100 @@ func LoadDictionary(dir string) (*Dictionary, error) {
 		return nil, fmt.Errorf("dict %s: %w", manifest.ID, err)
 	}
 	d.entryCount = len(d.Entries)
+	d.sumTotals()
 	return d, nil
 }
 
+// sumTotals sums the totals of an in-memory dictionary.
+func (d *Dictionary) sumTotals() {
+	t := &Totals{}
+	for _, e := range d.Entries {
+		t.add(e)
+	}
+	d.totals = t
+}
+
 // Classify matches a term against patterns or falls back to lookup.
+// For pattern dictionaries, metadata "pattern" holds the first matching
+// pattern name and "patterns" all of them, comma-separated; metadata from
+// pattern extractors is merged in, the first matching pattern winning on
+// conflicting keys.
 func (d *Dictionary) Classify(term string) (*Entry, bool) {
 	if d.patterns != nil {
-		name, ok := d.patterns.match(term)
-		if !ok {
+		hits := d.patterns.match(term)
+		if len(hits) == 0 {
 			return nil, false
 		}
-		return &Entry{Metadata: map[string]string{"pattern": name}}, true
+		names := hitNames(hits)
+		meta := map[string]string{}
+		for _, h := range hits {
+			for k, v := range h.meta {
+				if _, ok := meta[k]; !ok {
+					meta[k] = v
+				}
+			}
+		}
+		meta["pattern"] = names[0]
+		meta["patterns"] = strings.Join(names, ",")
+		return &Entry{Metadata: meta}, true
 	}
 	return d.Lookup(term)
 }
 
+// MatchPatterns returns the names of all patterns matching term, in manifest
+// order. It returns nil for dictionaries that are not pattern-based.
+func (d *Dictionary) MatchPatterns(term string) []string {
+	if d.patterns == nil {
+		return nil
+	}
+	return hitNames(d.patterns.match(term))
+}
+
+// MatchesPattern reports whether term matches, validates and extracts against
+// a pattern named name. It returns false for dictionaries that are not
+// pattern-based.
+func (d *Dictionary) MatchesPattern(name, term string) bool {
+	if d.patterns == nil {
+		return false
+	}
+	return hasHit(d.patterns.match(term), name)
+}
+
+// PatternPrefix returns the literal prefix (e.g. "FR" for a French IBAN) of the
+// first pattern named name whose regex matches term, or "".
+func (d *Dictionary) PatternPrefix(name, term string) string {
+	if p := d.patternFor(name, term); p != nil {
+		return p.filter.prefix
+	}
+	return ""
+}
+
+// PatternCheck returns how the check of the first pattern named name whose
+// regex matches term is computed, or nil when its validator declares none
+// (see RegisterCheckDigits).
+func (d *Dictionary) PatternCheck(name, term string) *CheckDigits {
+	if p := d.patternFor(name, term); p != nil {
+		return p.check
+	}
+	return nil
+}
+
+// patternFor returns the first pattern named name whose regex matches term,
+// or nil.
+func (d *Dictionary) patternFor(name, term string) *compiledPattern {
+	if d.patterns == nil {
+		return nil
+	}
+	cleaned := strings.ReplaceAll(term, " ", "")
+	for i := range d.patterns.patterns {
+		p := &d.patterns.patterns[i]
+		if p.name == name && p.re.MatchString(cleaned) {
+			return p
+		}
+	}
+	return nil
+}
+
 func (d *Dictionary) loadCSV(path string) error {
 	f, err := os.Open(path)
 	if err != nil {
@@ -212,6 +301,20 @@ func (d *Dictionary) Lookup(term string) (*Entry, bool) {
 	return e, ok
 }
 
+// Each calls fn for every entry, in no particular order, until fn returns
+// false. Pattern dictionaries and alias pools have no entries.
+func (d *Dictionary) Each(fn func(key string, e *Entry) bool) error {
+	if d.db != nil {
+		return d.eachSQLite(fn)
+	}
+	for key, e := range d.Entries {
+		if !fn(key, e) {
+			break
+		}
+	}
+	return nil
+}
+
 // EntryCount returns the number of entries in this dictionary.
 func (d *Dictionary) EntryCount() int {
 	return d.entryCount
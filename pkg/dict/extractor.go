@@ -0,0 +1,67 @@
+// CLAUDE:SUMMARY Global registry of named extractors that parse pattern matches into structured metadata (dates, birth dates in national IDs, MRZ fields, network identifiers).
+// CLAUDE:DEPENDS pkg/dict/pattern.go, pkg/dict/dates.go, pkg/dict/birthdate.go, pkg/dict/mrz.go, pkg/dict/netid.go
+// CLAUDE:EXPORTS ExtractorFunc, RegisterExtractor, GetExtractor, ExtractorNames
+package dict
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ExtractorFunc parses a term (spaces already stripped) that matched a
+// pattern's regex into metadata. Returning false rejects the match, so an
+// extractor doubles as a semantic validator (e.g. 31/02/1985 is not a date).
+type ExtractorFunc func(string) (map[string]string, bool)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = make(map[string]ExtractorFunc)
+)
+
+func init() {
+	RegisterExtractor("date_ymd", extractDateYMD)
+	RegisterExtractor("date_numeric", extractDateNumeric)
+	RegisterExtractor("date_written", extractDateWritten)
+	RegisterExtractor("nir_birth", extractNIRBirth)
+	RegisterExtractor("cnp_birth", extractCNPBirth)
+	RegisterExtractor("pesel_birth", extractPESELBirth)
+	RegisterExtractor("hetu_birth", extractHETUBirth)
+	RegisterExtractor("mrz", extractMRZ)
+	RegisterExtractor("ip", extractIP)
+	RegisterExtractor("mac", extractMAC)
+	RegisterExtractor("url", extractURL)
+	RegisterExtractor("email", extractEmail)
+}
+
+// RegisterExtractor adds a named extractor to the global registry, replacing
+// any previous extractor with the same name. Extractors must be registered
+// before the dictionaries that reference them are loaded.
+func RegisterExtractor(name string, fn func(string) (map[string]string, bool)) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors[name] = fn
+}
+
+// GetExtractor returns a registered extractor by name, or an error if not found.
+func GetExtractor(name string) (ExtractorFunc, error) {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	fn, ok := extractors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown extractor %q", name)
+	}
+	return fn, nil
+}
+
+// ExtractorNames returns the names of all registered extractors, sorted.
+func ExtractorNames() []string {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	names := make([]string, 0, len(extractors))
+	for name := range extractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,95 @@
+// CLAUDE:SUMMARY Generalization hierarchies: walks a term up the parent_dict/parent_column links declared in manifests (commune → department → region, NAF code → section, date → year).
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/dict/manifest.go
+// CLAUDE:EXPORTS GeneralizeStep, GeneralizeResult, MaxGeneralizeLevel
+
+package dict
+
+// MaxGeneralizeLevel bounds hierarchy walks, guarding against cyclic
+// parent_dict links.
+const MaxGeneralizeLevel = 16
+
+// GeneralizeStep is one level of a hierarchy walk. Level 0 is the term
+// itself. EntityType is the parent_column name for levels with no
+// parent_dict ("chapter", "year").
+type GeneralizeStep struct {
+	Level      int               `json:"level"`
+	Value      string            `json:"value"`
+	DictID     string            `json:"dict_id,omitempty"`
+	EntityType string            `json:"entity_type,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// GeneralizeResult is a term generalized up to Level: the requested level,
+// or less when the walk reached the top of the hierarchy first. Top reports
+// that Generalized has no parent.
+type GeneralizeResult struct {
+	Term        string           `json:"term"`
+	Requested   int              `json:"requested"`
+	Level       int              `json:"level"`
+	Generalized string           `json:"generalized"`
+	Top         bool             `json:"top"`
+	Path        []GeneralizeStep `json:"path"`
+}
+
+// Generalize walks term up to level steps of its hierarchy. Each step reads
+// the parent_column metadata of the current entry and, when the manifest
+// names a parent_dict, looks the value up there to continue: a commune of
+// communes-fr goes to its department in departements-fr, then to its region
+// code. The walk starts from the first match whose dictionary declares a
+// parent. A term matching no dictionary stays at level 0; a parent_dict
+// rejected by opts.Filter ends the walk at its bare value.
+func (r *Registry) Generalize(term string, level int, opts *ClassifyOptions) *GeneralizeResult {
+	res := &GeneralizeResult{Term: term, Requested: level, Generalized: term}
+	cur := GeneralizeStep{Value: term}
+
+	matches := r.Classify(term, opts).Matches
+	var d *Dictionary
+	for _, m := range matches {
+		md, ok := r.Get(m.DictID)
+		if !ok {
+			continue
+		}
+		if d == nil || parentValue(md, m.Metadata) != "" {
+			cur.DictID, cur.EntityType, cur.Metadata, d = m.DictID, m.EntityType, m.Metadata, md
+		}
+		if parentValue(md, m.Metadata) != "" {
+			break
+		}
+	}
+	res.Path = append(res.Path, cur)
+
+	for cur.Level < level && cur.Level < MaxGeneralizeLevel {
+		value := parentValue(d, cur.Metadata)
+		if value == "" {
+			break
+		}
+		next := GeneralizeStep{Level: cur.Level + 1, Value: value, EntityType: d.Manifest.ParentColumn}
+		var parent *Dictionary
+		if id := d.Manifest.ParentDict; id != "" {
+			next.DictID = id
+			if pd, ok := r.Get(id); ok && (opts == nil || opts.Filter == nil || opts.Filter(pd.Manifest)) {
+				next.EntityType = pd.Manifest.EntityType
+				if e, ok := pd.Classify(value); ok && e != nil {
+					next.Metadata = e.Metadata
+					parent = pd
+				}
+			}
+		}
+		res.Path = append(res.Path, next)
+		cur, d = next, parent
+	}
+
+	res.Level = cur.Level
+	res.Generalized = cur.Value
+	res.Top = parentValue(d, cur.Metadata) == ""
+	return res
+}
+
+// parentValue is the parent of an entry of d with metadata meta, or "" at the
+// top of the hierarchy.
+func parentValue(d *Dictionary, meta map[string]string) string {
+	if d == nil || d.Manifest.ParentColumn == "" {
+		return ""
+	}
+	return meta[d.Manifest.ParentColumn]
+}
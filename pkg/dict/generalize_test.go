@@ -0,0 +1,112 @@
+package dict
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupHierarchyRegistry(t *testing.T) *Registry {
+	t.Helper()
+	dir := t.TempDir()
+	writeDict(t, dir, "communes", `id: communes
+version: "1.0"
+jurisdiction: fr
+entity_type: city
+source: test
+data_file: data.csv
+parent_dict: departements
+parent_column: departement
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "term"
+  normalize: lowercase_ascii
+metadata_columns:
+  - name: departement
+    column: "dep"
+`, "term;dep\nLyon;69\nVillefranche-sur-Saone;69\nBrest;29\n")
+	writeDict(t, dir, "departements", `id: departements
+version: "1.0"
+jurisdiction: fr
+entity_type: department
+source: test
+data_file: data.csv
+parent_column: region
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "code"
+  normalize: lowercase_ascii
+metadata_columns:
+  - name: name
+    column: "name"
+  - name: region
+    column: "region"
+`, "code;name;region\n69;Rhone;84\n29;Finistere;53\n")
+	writeDict(t, dir, "dates", `id: dates
+version: "1.0"
+jurisdiction: intl
+entity_type: date
+source: test
+method: pattern
+parent_column: year
+patterns:
+  - name: date_iso
+    regex: "^\\d{4}-\\d{2}-\\d{2}$"
+    extractor: date_ymd
+`, "")
+
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+func TestGeneralize(t *testing.T) {
+	reg := setupHierarchyRegistry(t)
+
+	tests := []struct {
+		term        string
+		level       int
+		generalized string
+		reached     int
+		top         bool
+	}{
+		{"Lyon", 0, "Lyon", 0, false},
+		{"Lyon", 1, "69", 1, false},
+		{"Lyon", 2, "84", 2, true},
+		{"Lyon", 5, "84", 2, true},
+		{"1985-01-12", 1, "1985", 1, true},
+		{"inconnu", 1, "inconnu", 0, true},
+	}
+	for _, tt := range tests {
+		res := reg.Generalize(tt.term, tt.level, nil)
+		if res.Generalized != tt.generalized || res.Level != tt.reached || res.Top != tt.top {
+			t.Errorf("Generalize(%q, %d) = %q at level %d (top %v), want %q at %d (top %v)",
+				tt.term, tt.level, res.Generalized, res.Level, res.Top, tt.generalized, tt.reached, tt.top)
+		}
+		if len(res.Path) != res.Level+1 {
+			t.Errorf("Generalize(%q, %d): path = %+v", tt.term, tt.level, res.Path)
+		}
+	}
+
+	res := reg.Generalize("Lyon", 2, nil)
+	if s := res.Path[1]; s.DictID != "departements" || s.EntityType != "department" || s.Metadata["name"] != "Rhone" {
+		t.Errorf("step 1 = %+v", s)
+	}
+	if s := res.Path[2]; s.DictID != "" || s.EntityType != "region" {
+		t.Errorf("step 2 = %+v", s)
+	}
+}
+
+func TestLoadManifest_ParentDictWithoutColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte("id: x\nparent_dict: y\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifest(path); err == nil {
+		t.Error("expected error for parent_dict without parent_column")
+	}
+}
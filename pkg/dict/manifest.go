@@ -4,6 +4,8 @@ package dict
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,9 +28,13 @@ type Manifest struct {
 	UpdateFrequency string           `yaml:"update_frequency" json:"update_frequency,omitempty"`    // "weekly", "monthly", etc.
 	EntitySpec      *EntitySpec      `yaml:"entity_spec" json:"entity_spec,omitempty"`
 	ResponseFields  []ResponseField  `yaml:"response_fields" json:"response_fields,omitempty"`
+	ParentDict      string           `yaml:"parent_dict,omitempty" json:"parent_dict,omitempty"`     // dictionary parent_column values are looked up in
+	ParentColumn    string           `yaml:"parent_column,omitempty" json:"parent_column,omitempty"` // metadata key holding an entry's parent (see Registry.Generalize)
 	Domain          string           `yaml:"domain,omitempty" json:"domain,omitempty"`              // alias_pool only
+	Surrogates      bool             `yaml:"surrogates,omitempty" json:"surrogates,omitempty"`      // entries may be drawn as surrogates (see pseudo.Surrogate)
 	CribledAgainst  []string         `yaml:"cribled_against,omitempty" json:"cribled_against,omitempty"`
 	NextCriblage    string           `yaml:"next_criblage,omitempty" json:"next_criblage,omitempty"`
+	LastCriblage    string           `yaml:"last_criblage,omitempty" json:"last_criblage,omitempty"`
 	AliasEntries    []AliasEntry     `yaml:"entries,omitempty" json:"-"` // alias_pool entries
 }
 
@@ -52,18 +58,31 @@ type ResponseField struct {
 	Mapping  string   `yaml:"mapping,omitempty" json:"mapping,omitempty"` // path to JSON mapping file
 }
 
-// AliasEntry is a single alias in an alias pool.
+// AliasEntry is a single alias in an alias pool. Collisions and Duplicate are
+// recorded by the criblage (see Registry.CribleAliasPool).
 type AliasEntry struct {
-	Alias  string `yaml:"alias" json:"alias"`
-	Mimics string `yaml:"mimics,omitempty" json:"mimics,omitempty"`
-	Suffix string `yaml:"suffix,omitempty" json:"suffix,omitempty"`
+	Alias      string   `yaml:"alias" json:"alias"`
+	Mimics     string   `yaml:"mimics,omitempty" json:"mimics,omitempty"`
+	Suffix     string   `yaml:"suffix,omitempty" json:"suffix,omitempty"`
+	Collisions []string `yaml:"collisions,omitempty" json:"collisions,omitempty"` // dict IDs holding the alias as a real entity
+	Duplicate  bool     `yaml:"duplicate,omitempty" json:"duplicate,omitempty"`   // same alias earlier in the pool
+}
+
+// Usable reports whether the alias passed the last criblage.
+func (e AliasEntry) Usable() bool {
+	return len(e.Collisions) == 0 && !e.Duplicate
 }
 
 // PatternSpec defines a regex pattern with an optional checksum validator.
+// Validator names a registered validator (see RegisterValidator); Checksum
+// declares one inline. When both are set, both must pass. Extractor names a
+// registered extractor (see RegisterExtractor) that adds metadata to matches.
 type PatternSpec struct {
-	Name      string `yaml:"name" json:"name"`
-	Regex     string `yaml:"regex" json:"regex"`
-	Validator string `yaml:"validator,omitempty" json:"validator,omitempty"`
+	Name      string        `yaml:"name" json:"name"`
+	Regex     string        `yaml:"regex" json:"regex"`
+	Validator string        `yaml:"validator,omitempty" json:"validator,omitempty"`
+	Checksum  *ChecksumSpec `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	Extractor string        `yaml:"extractor,omitempty" json:"extractor,omitempty"`
 }
 
 // FormatSpec describes the CSV layout.
@@ -94,8 +113,63 @@ func LoadManifest(path string) (*Manifest, error) {
 	if m.ID == "" {
 		return nil, fmt.Errorf("manifest %s: missing id", path)
 	}
+	if m.ParentDict != "" && m.ParentColumn == "" {
+		return nil, fmt.Errorf("manifest %s: parent_dict requires parent_column", path)
+	}
 	if m.DataFile == "" {
 		m.DataFile = "data.csv"
 	}
 	return &m, nil
 }
+
+// SaveManifest writes m to dir/manifest.yaml atomically: readers see either
+// the old or the new manifest, never a partial one.
+func SaveManifest(dir string, m *Manifest) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".manifest-*.yaml")
+	if err != nil {
+		return fmt.Errorf("create temp manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close manifest: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("chmod manifest: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, "manifest.yaml")); err != nil {
+		return fmt.Errorf("replace manifest: %w", err)
+	}
+	return nil
+}
+
+// UpdateInterval converts an update_frequency into a duration. Unknown or
+// empty frequencies yield 0.
+func UpdateInterval(freq string) time.Duration {
+	const day = 24 * time.Hour
+	switch freq {
+	case "daily":
+		return day
+	case "weekly":
+		return 7 * day
+	case "monthly":
+		return 30 * day
+	case "quarterly":
+		return 91 * day
+	case "yearly", "annual":
+		return 365 * day
+	}
+	return 0
+}
@@ -0,0 +1,269 @@
+// CLAUDE:SUMMARY ICAO 9303 machine-readable zone parser (TD1, TD2, TD3, pre-2021 French CNI) with check-digit verification and the mrz extractor.
+// CLAUDE:DEPENDS pkg/dict/dates.go, pkg/dict/extractor.go
+// CLAUDE:EXPORTS MRZ, ParseMRZ, MRZCheckDigit
+package dict
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MRZ is the data parsed from a machine-readable zone. Dates are ISO
+// (YYYY-MM-DD), empty when the MRZ leaves them unknown.
+type MRZ struct {
+	Format         string // "td1", "td2", "td3" or "cni_fr"
+	DocumentCode   string // e.g. "P", "I", "ID"
+	IssuingState   string
+	DocumentNumber string
+	Nationality    string
+	BirthDate      string
+	Sex            string // "F", "M" or "" when unspecified
+	ExpiryDate     string
+	Surname        string
+	GivenNames     string
+}
+
+// ParseMRZ parses a machine-readable zone, with or without line breaks and
+// spaces, and verifies every check digit. The layout is chosen by length:
+// 3×30 (TD1, ID cards), 2×36 (TD2, or the French CNI issued before 2021 when
+// it starts with IDFRA) and 2×44 (TD3, passports).
+func ParseMRZ(s string) (*MRZ, error) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\n', '\r':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(s))
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !isDigit(c) && (c < 'A' || c > 'Z') && c != '<' {
+			return nil, fmt.Errorf("mrz: invalid character %q", c)
+		}
+	}
+
+	switch len(s) {
+	case 90:
+		return parseTD1(s[:30], s[30:60], s[60:])
+	case 72:
+		if strings.HasPrefix(s, "IDFRA") {
+			return parseCNIFR(s[:36], s[36:])
+		}
+		return parseTD2(s[:36], s[36:])
+	case 88:
+		return parseTD3(s[:44], s[44:])
+	}
+	return nil, fmt.Errorf("mrz: unsupported length %d", len(s))
+}
+
+// MRZCheckDigit computes the ICAO 9303 check digit of s: characters are
+// valued 0-9, A=10 … Z=35 and < = 0, weighted 7, 3, 1 repeatedly, summed
+// modulo 10.
+func MRZCheckDigit(s string) byte {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		var v int
+		switch c := s[i]; {
+		case isDigit(c):
+			v = int(c - '0')
+		case c >= 'A' && c <= 'Z':
+			v = int(c-'A') + 10
+		}
+		sum += v * weights[i%3]
+	}
+	return byte('0' + sum%10)
+}
+
+// checkMRZ verifies that check equals the check digit of field. A field made
+// only of fillers may carry a filler check digit.
+func checkMRZ(name, field string, check byte) error {
+	if check == '<' && strings.Trim(field, "<") == "" {
+		return nil
+	}
+	if MRZCheckDigit(field) != check {
+		return fmt.Errorf("mrz: %s check digit mismatch", name)
+	}
+	return nil
+}
+
+func parseTD3(l1, l2 string) (*MRZ, error) {
+	if err := checkAll(
+		checkMRZ("document number", l2[0:9], l2[9]),
+		checkMRZ("birth date", l2[13:19], l2[19]),
+		checkMRZ("expiry date", l2[21:27], l2[27]),
+		checkMRZ("personal number", l2[28:42], l2[42]),
+		checkMRZ("composite", l2[0:10]+l2[13:20]+l2[21:43], l2[43]),
+	); err != nil {
+		return nil, err
+	}
+	m := &MRZ{
+		Format:         "td3",
+		DocumentCode:   mrzField(l1[0:2]),
+		IssuingState:   mrzField(l1[2:5]),
+		DocumentNumber: mrzField(l2[0:9]),
+		Nationality:    mrzField(l2[10:13]),
+		BirthDate:      mrzDate(l2[13:19], false),
+		Sex:            mrzSex(l2[20]),
+		ExpiryDate:     mrzDate(l2[21:27], true),
+	}
+	m.Surname, m.GivenNames = mrzName(l1[5:])
+	return m, nil
+}
+
+func parseTD2(l1, l2 string) (*MRZ, error) {
+	if err := checkAll(
+		checkMRZ("document number", l2[0:9], l2[9]),
+		checkMRZ("birth date", l2[13:19], l2[19]),
+		checkMRZ("expiry date", l2[21:27], l2[27]),
+		checkMRZ("composite", l2[0:10]+l2[13:20]+l2[21:35], l2[35]),
+	); err != nil {
+		return nil, err
+	}
+	m := &MRZ{
+		Format:         "td2",
+		DocumentCode:   mrzField(l1[0:2]),
+		IssuingState:   mrzField(l1[2:5]),
+		DocumentNumber: mrzField(l2[0:9]),
+		Nationality:    mrzField(l2[10:13]),
+		BirthDate:      mrzDate(l2[13:19], false),
+		Sex:            mrzSex(l2[20]),
+		ExpiryDate:     mrzDate(l2[21:27], true),
+	}
+	m.Surname, m.GivenNames = mrzName(l1[5:])
+	return m, nil
+}
+
+func parseTD1(l1, l2, l3 string) (*MRZ, error) {
+	// Document numbers longer than 9 characters put a filler in the check
+	// digit position and continue in the optional field, followed by their
+	// check digit.
+	number, check := l1[5:14], l1[14]
+	if check == '<' {
+		rest := strings.TrimRight(l1[15:30], "<")
+		if rest == "" {
+			return nil, fmt.Errorf("mrz: document number check digit missing")
+		}
+		number, check = number+rest[:len(rest)-1], rest[len(rest)-1]
+	}
+	if err := checkAll(
+		checkMRZ("document number", number, check),
+		checkMRZ("birth date", l2[0:6], l2[6]),
+		checkMRZ("expiry date", l2[8:14], l2[14]),
+		checkMRZ("composite", l1[5:30]+l2[0:7]+l2[8:15]+l2[18:29], l2[29]),
+	); err != nil {
+		return nil, err
+	}
+	m := &MRZ{
+		Format:         "td1",
+		DocumentCode:   mrzField(l1[0:2]),
+		IssuingState:   mrzField(l1[2:5]),
+		DocumentNumber: mrzField(number),
+		Nationality:    mrzField(l2[15:18]),
+		BirthDate:      mrzDate(l2[0:6], false),
+		Sex:            mrzSex(l2[7]),
+		ExpiryDate:     mrzDate(l2[8:14], true),
+	}
+	m.Surname, m.GivenNames = mrzName(l3)
+	return m, nil
+}
+
+// parseCNIFR parses the 2×36 MRZ of the French identity card issued from
+// 1988 to 2021, which predates ICAO TD1: line 1 holds the surname and the
+// issuing office, line 2 the 12-character card number, given names, birth
+// date and sex. The card carries no expiry date in its MRZ.
+func parseCNIFR(l1, l2 string) (*MRZ, error) {
+	if err := checkAll(
+		checkMRZ("document number", l2[0:12], l2[12]),
+		checkMRZ("birth date", l2[27:33], l2[33]),
+		checkMRZ("composite", l1+l2[:35], l2[35]),
+	); err != nil {
+		return nil, err
+	}
+	return &MRZ{
+		Format:         "cni_fr",
+		DocumentCode:   "ID",
+		IssuingState:   "FRA",
+		DocumentNumber: l2[0:12],
+		Nationality:    "FRA",
+		BirthDate:      mrzDate(l2[27:33], false),
+		Sex:            mrzSex(l2[34]),
+		Surname:        mrzField(l1[5:30]),
+		GivenNames:     mrzField(strings.ReplaceAll(l2[13:27], "<<", "<")),
+	}, nil
+}
+
+func checkAll(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mrzField strips trailing fillers and turns inner fillers into spaces.
+func mrzField(s string) string {
+	return strings.ReplaceAll(strings.TrimRight(s, "<"), "<", " ")
+}
+
+// mrzName splits a name field "SURNAME<<GIVEN<NAMES" into its two parts.
+func mrzName(s string) (string, string) {
+	surname, given, _ := strings.Cut(s, "<<")
+	return mrzField(surname), mrzField(strings.TrimLeft(given, "<"))
+}
+
+func mrzSex(c byte) string {
+	if c == 'F' || c == 'M' {
+		return string(c)
+	}
+	return ""
+}
+
+// mrzDate converts YYMMDD to an ISO date. Birth dates are placed in the past
+// like two-digit years elsewhere; expiry dates within 50 years of today.
+// Unknown or impossible dates yield "".
+func mrzDate(s string, expiry bool) string {
+	if !isDigits(s) {
+		return ""
+	}
+	yy := atoi(s[0:2])
+	y := expandYear(yy)
+	if expiry && y+100 <= time.Now().Year()+50 {
+		y += 100
+	}
+	iso, ok := isoDate(y, atoi(s[2:4]), atoi(s[4:6]))
+	if !ok {
+		return ""
+	}
+	return iso
+}
+
+// extractMRZ is the "mrz" extractor: it rejects MRZs whose check digits fail
+// and exposes the parsed fields as metadata. The birth date is published as
+// "birth_date" so MRZs take part in batch birth-date cross-checks.
+func extractMRZ(s string) (map[string]string, bool) {
+	m, err := ParseMRZ(s)
+	if err != nil {
+		return nil, false
+	}
+	meta := map[string]string{
+		"id_scheme":       "mrz",
+		"mrz_format":      m.Format,
+		"document_code":   m.DocumentCode,
+		"issuing_state":   m.IssuingState,
+		"document_number": m.DocumentNumber,
+		"nationality":     m.Nationality,
+		"birth_date":      m.BirthDate,
+		"sex":             m.Sex,
+		"expiry_date":     m.ExpiryDate,
+		"surname":         m.Surname,
+		"given_names":     m.GivenNames,
+	}
+	for k, v := range meta {
+		if v == "" {
+			delete(meta, k)
+		}
+	}
+	return meta, true
+}
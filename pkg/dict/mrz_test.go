@@ -0,0 +1,162 @@
+package dict
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Specimens from ICAO Doc 9303 and the French CNI.
+const (
+	mrzTD3 = "P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<\n" +
+		"L898902C36UTO7408122F1204159ZE184226B<<<<<10"
+	mrzTD2 = "I<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<\n" +
+		"D231458907UTO7408122F1204159<<<<<<<6"
+	mrzTD1 = "I<UTOD231458907<<<<<<<<<<<<<<<\n" +
+		"7408122F1204159UTO<<<<<<<<<<<6\n" +
+		"ERIKSSON<<ANNA<MARIA<<<<<<<<<<"
+	mrzCNIFR = "IDFRADOUEL<<<<<<<<<<<<<<<<<<<<932013\n" +
+		"0506932020438CHRISTIANE<<NI2906209F3"
+)
+
+func TestMRZCheckDigit(t *testing.T) {
+	for in, want := range map[string]byte{
+		"L898902C3": '6',
+		"740812":    '2',
+		"120415":    '9',
+		"D23145890": '7',
+		"<<<<<<":    '0',
+	} {
+		if got := MRZCheckDigit(in); got != want {
+			t.Errorf("MRZCheckDigit(%q) = %c, want %c", in, got, want)
+		}
+	}
+}
+
+func TestParseMRZ(t *testing.T) {
+	tests := []struct {
+		in   string
+		want MRZ
+	}{
+		{mrzTD3, MRZ{Format: "td3", DocumentCode: "P", IssuingState: "UTO", DocumentNumber: "L898902C3", Nationality: "UTO",
+			BirthDate: "1974-08-12", Sex: "F", ExpiryDate: "2012-04-15", Surname: "ERIKSSON", GivenNames: "ANNA MARIA"}},
+		{mrzTD2, MRZ{Format: "td2", DocumentCode: "I", IssuingState: "UTO", DocumentNumber: "D23145890", Nationality: "UTO",
+			BirthDate: "1974-08-12", Sex: "F", ExpiryDate: "2012-04-15", Surname: "ERIKSSON", GivenNames: "ANNA MARIA"}},
+		{mrzTD1, MRZ{Format: "td1", DocumentCode: "I", IssuingState: "UTO", DocumentNumber: "D23145890", Nationality: "UTO",
+			BirthDate: "1974-08-12", Sex: "F", ExpiryDate: "2012-04-15", Surname: "ERIKSSON", GivenNames: "ANNA MARIA"}},
+		{mrzCNIFR, MRZ{Format: "cni_fr", DocumentCode: "ID", IssuingState: "FRA", DocumentNumber: "050693202043", Nationality: "FRA",
+			BirthDate: "1929-06-20", Sex: "F", Surname: "DOUEL", GivenNames: "CHRISTIANE NI"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMRZ(tt.in)
+		if err != nil {
+			t.Errorf("ParseMRZ(%s): %v", tt.want.Format, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseMRZ(%s) = %+v, want %+v", tt.want.Format, *got, tt.want)
+		}
+	}
+}
+
+func TestParseMRZ_Invalid(t *testing.T) {
+	for name, in := range map[string]string{
+		"bad document check": strings.Replace(mrzTD3, "L898902C36", "L898902C35", 1),
+		"bad birth check":    strings.Replace(mrzTD3, "7408122", "7408123", 1),
+		"bad composite":      mrzTD3[:len(mrzTD3)-1] + "1",
+		"bad CNI composite":  mrzCNIFR[:len(mrzCNIFR)-1] + "4",
+		"wrong length":       mrzTD3[:80],
+		"invalid character":  strings.Replace(mrzTD1, "ERIKSSON", "ERIK$SON", 1),
+	} {
+		if _, err := ParseMRZ(in); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseMRZ_TD1LongDocumentNumber(t *testing.T) {
+	// A 12-character number: 9 in place, a filler check digit, then the
+	// remaining 3 characters and the check digit in the optional field.
+	number := "D23145890123"
+	l1 := "I<UTO" + number[:9] + "<" + number[9:] + string(MRZCheckDigit(number))
+	l1 += strings.Repeat("<", 30-len(l1))
+	l2 := "7408122F1204159UTO<<<<<<<<<<<"
+	l2 += string(MRZCheckDigit(l1[5:30] + l2[0:7] + l2[8:15] + l2[18:29]))
+	l3 := "ERIKSSON<<ANNA<MARIA<<<<<<<<<<"
+
+	m, err := ParseMRZ(l1 + l2 + l3)
+	if err != nil {
+		t.Fatalf("ParseMRZ: %v", err)
+	}
+	if m.DocumentNumber != number {
+		t.Errorf("DocumentNumber = %q, want %q", m.DocumentNumber, number)
+	}
+}
+
+func TestMRZDict(t *testing.T) {
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", "mrz"))
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+	entry, ok := d.Classify(mrzTD3)
+	if !ok {
+		t.Fatal("TD3 MRZ not classified")
+	}
+	for k, want := range map[string]string{
+		"pattern":         "mrz_td3",
+		"document_number": "L898902C3",
+		"nationality":     "UTO",
+		"birth_date":      "1974-08-12",
+		"expiry_date":     "2012-04-15",
+	} {
+		if entry.Metadata[k] != want {
+			t.Errorf("metadata[%s] = %q, want %q", k, entry.Metadata[k], want)
+		}
+	}
+	if entry, ok := d.Classify(mrzCNIFR); !ok || entry.Metadata["mrz_format"] != "cni_fr" {
+		t.Errorf("CNI MRZ: ok = %v, metadata = %v", ok, entry)
+	}
+	if _, ok := d.Classify(strings.Replace(mrzTD3, "L898902C36", "L898902C35", 1)); ok {
+		t.Error("MRZ with a bad check digit classified")
+	}
+}
+
+func TestPlatesAndIDDocumentsDicts(t *testing.T) {
+	plates, err := LoadDictionary(filepath.Join("..", "..", "dicts", "plates-fr"))
+	if err != nil {
+		t.Fatalf("LoadDictionary(plates-fr): %v", err)
+	}
+	docs, err := LoadDictionary(filepath.Join("..", "..", "dicts", "id-documents-fr"))
+	if err != nil {
+		t.Fatalf("LoadDictionary(id-documents-fr): %v", err)
+	}
+
+	tests := []struct {
+		d       *Dictionary
+		term    string
+		pattern string
+	}{
+		{plates, "AB-123-CD", "siv"},
+		{plates, "AB 123 CD", "siv"},
+		{plates, "ab123cd", "siv"},
+		{plates, "1234 AB 75", "fni"},
+		{plates, "56 ZX 2A", "fni"},
+		{plates, "SS-123-AB", ""},
+		{plates, "AB-000-CD", ""},
+		{plates, "AI-123-CD", ""},
+		{plates, "1234 AB 99", ""},
+		{docs, "09AA12345", "passport_fr"},
+		{docs, "880692310285", "cni_fr"},
+		{docs, "881392310285", ""},
+	}
+	for _, tt := range tests {
+		entry, ok := tt.d.Classify(tt.term)
+		got := ""
+		if ok {
+			got = entry.Metadata["pattern"]
+		}
+		if got != tt.pattern {
+			t.Errorf("Classify(%q) pattern = %q, want %q", tt.term, got, tt.pattern)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// CLAUDE:SUMMARY Network identifier validators and extractors: IPv4/IPv6 via net/netip, MAC addresses, URLs and email domains (host, tld).
+// CLAUDE:DEPENDS pkg/dict/validator.go, pkg/dict/extractor.go
+package dict
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+func validateIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+func validateIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6()
+}
+
+// validateMAC accepts 48-bit MAC addresses in colon, hyphen or Cisco dot
+// notation.
+func validateMAC(s string) bool {
+	hw, err := net.ParseMAC(s)
+	return err == nil && len(hw) == 6
+}
+
+// extractIP reports the IP version and scope, so clients can tell a public
+// address (personal data) from a private or loopback one.
+func extractIP(s string) (map[string]string, bool) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil, false
+	}
+	version := "6"
+	if addr.Is4() || addr.Is4In6() {
+		version = "4"
+	}
+	return map[string]string{
+		"ip_version": version,
+		"ip_scope":   ipScope(addr.Unmap()),
+		"ip":         addr.Unmap().String(),
+	}, true
+}
+
+func ipScope(addr netip.Addr) string {
+	switch {
+	case addr.IsUnspecified():
+		return "unspecified"
+	case addr.IsLoopback():
+		return "loopback"
+	case addr.IsPrivate():
+		return "private"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return "link_local"
+	case addr.IsMulticast():
+		return "multicast"
+	}
+	return "global"
+}
+
+// extractMAC returns the canonical lowercase colon form, the OUI (vendor
+// prefix) and whether the address is locally administered (randomized MACs of
+// phones and laptops are).
+func extractMAC(s string) (map[string]string, bool) {
+	hw, err := net.ParseMAC(s)
+	if err != nil || len(hw) != 6 {
+		return nil, false
+	}
+	mac := hw.String()
+	meta := map[string]string{
+		"mac":                  mac,
+		"oui":                  strings.ToUpper(mac[:8]),
+		"locally_administered": "false",
+	}
+	if hw[0]&0x02 != 0 {
+		meta["locally_administered"] = "true"
+	}
+	if hw[0]&0x01 != 0 {
+		meta["multicast"] = "true"
+	}
+	return meta, true
+}
+
+// extractURL parses a URL (scheme optional when it starts with www.) and
+// returns its host, domain and top-level domain; "has_path" flags URLs whose
+// path, query or fragment may carry personal data (/users/jdupont, ?id=…).
+func extractURL(s string) (map[string]string, bool) {
+	raw := s
+	if !strings.Contains(s, "://") {
+		raw = "http://" + s
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return nil, false
+	}
+	meta, ok := hostMeta(u.Hostname())
+	if !ok {
+		return nil, false
+	}
+	meta["scheme"] = strings.ToLower(u.Scheme)
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+		meta["has_path"] = "true"
+	}
+	if u.User != nil {
+		meta["has_userinfo"] = "true"
+	}
+	return meta, true
+}
+
+// extractEmail returns the domain and top-level domain of an email address.
+func extractEmail(s string) (map[string]string, bool) {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 {
+		return nil, false
+	}
+	return hostMeta(s[at+1:])
+}
+
+// hostMeta describes a URL or email host. IP literals have no tld; domain
+// names need at least two labels and a non-numeric tld.
+func hostMeta(host string) (map[string]string, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return map[string]string{"host": addr.String(), "host_is_ip": "true"}, true
+	}
+	dot := strings.LastIndexByte(host, '.')
+	if dot <= 0 || dot == len(host)-1 {
+		return nil, false
+	}
+	tld := host[dot+1:]
+	if isDigits(tld) {
+		return nil, false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return nil, false
+		}
+	}
+	return map[string]string{
+		"host":   host,
+		"domain": registrableDomain(host),
+		"tld":    tld,
+	}, true
+}
+
+// registrableDomain returns the last two labels of host ("www.example.com" →
+// "example.com"). Without a public suffix list it cannot tell "example.co.uk"
+// from a subdomain, so hosts under a known second-level label of a country
+// code TLD keep three.
+func registrableDomain(host string) string {
+	labels := strings.Split(host, ".")
+	n := 2
+	if len(labels) > 2 && len(labels[len(labels)-1]) == 2 && containsString(secondLevelLabels, labels[len(labels)-2]) {
+		n = 3
+	}
+	if len(labels) <= n {
+		return host
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// secondLevelLabels are common second-level labels under country code TLDs
+// (co.uk, com.au, gouv.fr …).
+var secondLevelLabels = []string{"co", "com", "org", "net", "gov", "gouv", "ac", "edu", "ltd", "plc", "asso", "nom"}
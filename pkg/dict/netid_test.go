@@ -0,0 +1,131 @@
+package dict
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetworkIdentifierDicts(t *testing.T) {
+	tests := []struct {
+		dict    string
+		term    string
+		pattern string
+		meta    map[string]string
+	}{
+		{"ip-addresses", "192.168.1.10", "ipv4", map[string]string{"ip_version": "4", "ip_scope": "private"}},
+		{"ip-addresses", "8.8.8.8", "ipv4", map[string]string{"ip_scope": "global"}},
+		{"ip-addresses", "256.1.1.1", "", nil},
+		{"ip-addresses", "2001:db8::1", "ipv6", map[string]string{"ip_version": "6", "ip": "2001:db8::1"}},
+		{"ip-addresses", "::1", "ipv6", map[string]string{"ip_scope": "loopback"}},
+		{"ip-addresses", "fe80::1%eth0", "ipv6", map[string]string{"ip_scope": "link_local"}},
+		{"ip-addresses", "::ffff:192.0.2.1", "ipv6", map[string]string{"ip_version": "4", "ip": "192.0.2.1"}},
+		{"ip-addresses", "2001:db8:::1", "", nil},
+		{"mac-addresses", "00:1A:2B:3C:4D:5E", "mac", map[string]string{"mac": "00:1a:2b:3c:4d:5e", "oui": "00:1A:2B", "locally_administered": "false"}},
+		{"mac-addresses", "da-a1-19-00-00-01", "mac", map[string]string{"locally_administered": "true"}},
+		{"mac-addresses", "001a.2b3c.4d5e", "mac", map[string]string{"mac": "00:1a:2b:3c:4d:5e"}},
+		{"mac-addresses", "00:1A:2B:3C:4D", "", nil},
+		{"urls", "https://www.example.co.uk/users/jdupont?tab=1", "url", map[string]string{"host": "www.example.co.uk", "domain": "example.co.uk", "tld": "uk", "has_path": "true", "scheme": "https"}},
+		{"urls", "http://example.fr/", "url", map[string]string{"domain": "example.fr", "tld": "fr", "has_path": ""}},
+		{"urls", "www.service-public.gouv.fr/particuliers", "url_www", map[string]string{"domain": "service-public.gouv.fr", "tld": "fr"}},
+		{"urls", "http://10.0.0.1/admin", "url", map[string]string{"host": "10.0.0.1", "host_is_ip": "true", "tld": ""}},
+		{"urls", "https://-bad-.com", "", nil},
+		{"email", "jean.dupont@mail.example.com", "email", map[string]string{"domain": "example.com", "tld": "com"}},
+	}
+	dicts := map[string]*Dictionary{}
+	for _, tt := range tests {
+		d := dicts[tt.dict]
+		if d == nil {
+			var err error
+			d, err = LoadDictionary(filepath.Join("..", "..", "dicts", tt.dict))
+			if err != nil {
+				t.Fatalf("LoadDictionary(%s): %v", tt.dict, err)
+			}
+			dicts[tt.dict] = d
+		}
+		entry, ok := d.Classify(tt.term)
+		if tt.pattern == "" {
+			if ok {
+				t.Errorf("Classify(%q) = %v, want no match", tt.term, entry.Metadata)
+			}
+			continue
+		}
+		if !ok || entry.Metadata["pattern"] != tt.pattern {
+			t.Errorf("Classify(%q) = %v, want pattern %s", tt.term, entry, tt.pattern)
+			continue
+		}
+		for k, want := range tt.meta {
+			if got := entry.Metadata[k]; got != want {
+				t.Errorf("Classify(%q) metadata[%s] = %q, want %q", tt.term, k, got, want)
+			}
+		}
+	}
+}
+
+func TestClassify_TLDFollowUp(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"urls", "email"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "dicts", id, "manifest.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, id), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, id, "manifest.yaml"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// Without a tld dictionary, no follow-up.
+	if m := reg.Classify("https://example.com", nil).Matches; len(m) != 1 || m[0].Metadata["tld_known"] != "" {
+		t.Fatalf("matches = %+v, want one without tld_known", m)
+	}
+
+	tldDir := filepath.Join(dir, "tld")
+	if err := os.MkdirAll(tldDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tldDir, "manifest.yaml"), []byte(`id: tld
+version: "1.0"
+entity_type: tld
+source: test
+data_file: data.csv
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "term"
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tldDir, "data.csv"), []byte("term\nCOM\nFR\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	for term, want := range map[string]string{
+		"https://example.com/a":   "true",
+		"jean@example.fr":         "true",
+		"https://example.invalid": "false",
+	} {
+		res := reg.Classify(term, nil)
+		found := false
+		for _, m := range res.Matches {
+			if m.Metadata["tld"] != "" {
+				found = true
+				if m.Metadata["tld_known"] != want {
+					t.Errorf("%s: tld_known = %q, want %q", term, m.Metadata["tld_known"], want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: no match with a tld", term)
+		}
+	}
+}
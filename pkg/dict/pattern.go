@@ -1,23 +1,38 @@
-// CLAUDE:SUMMARY Regex pattern matcher with checksum validators (IBAN mod97, Luhn, French NIR) for pattern-based dictionaries.
+// CLAUDE:SUMMARY Regex pattern matcher with checksum validators (IBAN mod97, Luhn, French NIR, SIV plates, registered or declarative) for pattern-based dictionaries.
 package dict
 
 import (
 	"fmt"
-	"math/big"
 	"regexp"
 	"strings"
 )
 
-// compiledPattern is a single named regex with an optional checksum validator.
+// compiledPattern is a single named regex with an optional checksum validator
+// and an optional extractor turning the match into metadata.
 type compiledPattern struct {
 	name      string
 	re        *regexp.Regexp
 	validator func(string) bool
+	extractor ExtractorFunc
+	filter    patternFilter
+	check     *CheckDigits // how the validator's check is computed, if known
+	density   float64      // see PatternDensity
+}
+
+// patternHit is a pattern that matched a term, with its extracted metadata.
+type patternHit struct {
+	name string
+	meta map[string]string
 }
 
 // patternMatcher holds compiled patterns for a pattern-based dictionary.
+// It behaves as a regexp set: byFirst dispatches a term on its first byte to
+// the patterns that can start with it, and each candidate's literal prefix
+// (e.g. the "FR" of an IBAN) and length bounds are checked before its regex
+// runs, so a surname never reaches the 35 IBAN regexes.
 type patternMatcher struct {
 	patterns []compiledPattern
+	byFirst  [256][]int // first byte → candidate pattern indices, in manifest order
 }
 
 // compilePatterns builds a patternMatcher from manifest pattern specs.
@@ -27,42 +42,152 @@ func compilePatterns(specs []PatternSpec) (*patternMatcher, error) {
 	}
 
 	pm := &patternMatcher{patterns: make([]compiledPattern, 0, len(specs))}
-	for _, spec := range specs {
+	for i, spec := range specs {
 		re, err := regexp.Compile(spec.Regex)
 		if err != nil {
 			return nil, fmt.Errorf("pattern %q: %w", spec.Name, err)
 		}
-		cp := compiledPattern{name: spec.Name, re: re}
-		switch spec.Validator {
-		case "":
+		cp := compiledPattern{name: spec.Name, re: re, filter: analyzePattern(spec.Regex)}
+		var checks []ValidatorFunc
+		if spec.Validator != "" {
+			fn, err := GetValidator(spec.Validator)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", spec.Name, err)
+			}
+			checks = append(checks, fn)
+			if c, ok := getCheckDigits(spec.Validator); ok && spec.Checksum == nil {
+				cp.check = &c
+			}
+		}
+		if spec.Checksum != nil {
+			fn, err := compileChecksum(spec.Checksum)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", spec.Name, err)
+			}
+			checks = append(checks, fn)
+		}
+		switch len(checks) {
+		case 0:
 			// No validator.
-		case "mod97":
-			cp.validator = validateMod97
-		case "luhn":
-			cp.validator = validateLuhn
-		case "nir":
-			cp.validator = validateNIR
+		case 1:
+			cp.validator = checks[0]
 		default:
-			return nil, fmt.Errorf("pattern %q: unknown validator %q", spec.Name, spec.Validator)
+			cp.validator = func(s string) bool {
+				for _, fn := range checks {
+					if !fn(s) {
+						return false
+					}
+				}
+				return true
+			}
+		}
+		if spec.Extractor != "" {
+			fn, err := GetExtractor(spec.Extractor)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", spec.Name, err)
+			}
+			cp.extractor = fn
 		}
+		cp.density = cp.estimateDensity(spec.Regex)
 		pm.patterns = append(pm.patterns, cp)
+
+		for b := range cp.filter.first {
+			if cp.filter.first[b] {
+				pm.byFirst[b] = append(pm.byFirst[b], i)
+			}
+		}
 	}
 	return pm, nil
 }
 
-// match tests a term against all patterns. Returns the first matching pattern name.
-func (pm *patternMatcher) match(term string) (string, bool) {
+// match tests a term against the patterns that can match it and returns a hit
+// for each that matches, validates and extracts, in manifest order. A pattern
+// name listed several times in the manifest yields a single hit.
+func (pm *patternMatcher) match(term string) []patternHit {
 	cleaned := strings.ReplaceAll(term, " ", "")
-	for _, p := range pm.patterns {
-		if !p.re.MatchString(cleaned) {
+	if cleaned == "" {
+		return pm.matchAll(cleaned)
+	}
+
+	var hits []patternHit
+	for _, idx := range pm.byFirst[cleaned[0]] {
+		p := &pm.patterns[idx]
+		if !p.filter.accepts(cleaned) || hasHit(hits, p.name) {
 			continue
 		}
-		if p.validator != nil && !p.validator(cleaned) {
+		if meta, ok := p.matches(cleaned); ok {
+			hits = append(hits, patternHit{name: p.name, meta: meta})
+		}
+	}
+	return hits
+}
+
+// matchAll runs every pattern, bypassing dispatch (used for the empty term,
+// which has no first byte).
+func (pm *patternMatcher) matchAll(cleaned string) []patternHit {
+	var hits []patternHit
+	for i := range pm.patterns {
+		p := &pm.patterns[i]
+		if hasHit(hits, p.name) {
 			continue
 		}
-		return p.name, true
+		if meta, ok := p.matches(cleaned); ok {
+			hits = append(hits, patternHit{name: p.name, meta: meta})
+		}
 	}
-	return "", false
+	return hits
+}
+
+// matches runs the regex, the validator and the extractor in that order.
+func (p *compiledPattern) matches(s string) (map[string]string, bool) {
+	if !p.re.MatchString(s) {
+		return nil, false
+	}
+	if p.validator != nil && !p.validator(s) {
+		return nil, false
+	}
+	if p.extractor == nil {
+		return nil, true
+	}
+	return p.extractor(s)
+}
+
+// hitNames returns the pattern names of hits, or nil when there are none.
+func hitNames(hits []patternHit) []string {
+	if len(hits) == 0 {
+		return nil
+	}
+	names := make([]string, len(hits))
+	for i, h := range hits {
+		names[i] = h.name
+	}
+	return names
+}
+
+func hasHit(hits []patternHit, name string) bool {
+	for _, h := range hits {
+		if h.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// accepts reports whether s satisfies the filter's length and prefix bounds.
+func (f *patternFilter) accepts(s string) bool {
+	if len(s) < f.minLen || (f.maxLen >= 0 && len(s) > f.maxLen) {
+		return false
+	}
+	return strings.HasPrefix(s, f.prefix)
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
 
 // validateMod97 implements ISO 7064 MOD 97-10 (used by IBAN).
@@ -74,25 +199,20 @@ func validateMod97(s string) bool {
 	// Move first 4 characters to the end.
 	rearranged := s[4:] + s[:4]
 
-	// Convert letters to digits: A=10, B=11, ..., Z=35.
-	var digits strings.Builder
+	// Convert letters to digits (A=10, B=11, ..., Z=35) and reduce mod 97
+	// incrementally, so arbitrarily long inputs never need big integers.
+	var mod int
 	for _, c := range strings.ToUpper(rearranged) {
 		switch {
 		case c >= '0' && c <= '9':
-			digits.WriteRune(c)
+			mod = (mod*10 + int(c-'0')) % 97
 		case c >= 'A' && c <= 'Z':
-			fmt.Fprintf(&digits, "%d", c-'A'+10)
+			mod = (mod*100 + int(c-'A'+10)) % 97
 		default:
 			return false
 		}
 	}
-
-	n := new(big.Int)
-	if _, ok := n.SetString(digits.String(), 10); !ok {
-		return false
-	}
-	mod := new(big.Int).Mod(n, big.NewInt(97))
-	return mod.Int64() == 1
+	return mod == 1
 }
 
 // validateLuhn implements the Luhn algorithm (used by credit card numbers).
@@ -127,30 +247,36 @@ func validateNIR(s string) bool {
 	if len(s) != 15 {
 		return false
 	}
+	key, ok := nirKey(s[:13])
+	return ok && s[13:] == key
+}
 
-	// Extract the check key (last 2 digits).
-	keyStr := s[13:15]
-	var key int64
-	for _, c := range keyStr {
-		if c < '0' || c > '9' {
-			return false
-		}
-		key = key*10 + int64(c-'0')
+// nirKey returns the check key of the 13-character body of a NIR, 97 minus
+// the body mod 97, as two digits.
+func nirKey(body string) (string, bool) {
+	if len(body) != 13 {
+		return "", false
 	}
-
-	// The 13-digit body, with Corsican department substitution.
-	body := s[:13]
 	body = strings.Replace(body, "2A", "19", 1)
 	body = strings.Replace(body, "2B", "20", 1)
 
-	var bodyNum int64
+	var n int64
 	for _, c := range body {
 		if c < '0' || c > '9' {
-			return false
+			return "", false
 		}
-		bodyNum = bodyNum*10 + int64(c-'0')
+		n = n*10 + int64(c-'0')
 	}
+	return fmt.Sprintf("%02d", 97-n%97), true
+}
 
-	// Check: key = 97 - (body mod 97)
-	return key == 97-bodyNum%97
+// validateSIV checks the series rules of a French SIV plate (AA-123-AA, dashes
+// optional): the SS letter pair and the 000 number block are never issued.
+// Letters I, O and U are excluded by the pattern itself.
+func validateSIV(s string) bool {
+	s = strings.ReplaceAll(strings.ToUpper(s), "-", "")
+	if len(s) != 7 {
+		return false
+	}
+	return s[:2] != "SS" && s[5:] != "SS" && s[2:5] != "000"
 }
@@ -0,0 +1,81 @@
+package dict
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// matchSequential is the original matcher: every regex, in order, for every term.
+// Kept as the baseline the prefix-dispatched matcher is benchmarked against.
+func matchSequential(pm *patternMatcher, term string) []string {
+	cleaned := strings.ReplaceAll(term, " ", "")
+	var names []string
+	for i := range pm.patterns {
+		p := &pm.patterns[i]
+		if _, ok := p.matches(cleaned); ok && !containsString(names, p.name) {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// benchTerms mixes pattern hits with the surnames and words that make up most
+// Classify traffic.
+var benchTerms = []string{
+	"FR7630006000011234567890189",
+	"DE89370400440532013000",
+	"GB29NWBK60161331926819",
+	"DUPONT",
+	"jean-pierre",
+	"SCI LES LILAS",
+	"185057800608491",
+	"12345678Z",
+	"44051401359",
+	"Rue des Acacias",
+}
+
+func loadBenchMatcher(b *testing.B, id string) *patternMatcher {
+	b.Helper()
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", id))
+	if err != nil {
+		b.Fatalf("LoadDictionary(%s): %v", id, err)
+	}
+	return d.patterns
+}
+
+func TestPatternMatcher_MatchesSequential(t *testing.T) {
+	for _, id := range []string{"iban", "national-ids-eu", "credit-card", "phone-fr"} {
+		d, err := LoadDictionary(filepath.Join("..", "..", "dicts", id))
+		if err != nil {
+			t.Fatalf("LoadDictionary(%s): %v", id, err)
+		}
+		for _, term := range benchTerms {
+			got := strings.Join(hitNames(d.patterns.match(term)), ",")
+			want := strings.Join(matchSequential(d.patterns, term), ",")
+			if got != want {
+				t.Errorf("%s: match(%q) = %q, sequential = %q", id, term, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkPatternMatcher(b *testing.B) {
+	for _, id := range []string{"iban", "national-ids-eu"} {
+		pm := loadBenchMatcher(b, id)
+		b.Run(id+"/sequential", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, term := range benchTerms {
+					_ = matchSequential(pm, term)
+				}
+			}
+		})
+		b.Run(id+"/dispatch", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, term := range benchTerms {
+					_ = pm.match(term)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package dict
 import (
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -99,7 +101,7 @@ func TestPatternMatcher_IBAN(t *testing.T) {
 		t.Fatalf("compilePatterns: %v", err)
 	}
 
-	name, ok := pm.match("FR7630006000011234567890189")
+	name, ok := matchFirst(pm, "FR7630006000011234567890189")
 	if !ok {
 		t.Fatal("expected match for valid IBAN FR")
 	}
@@ -108,13 +110,13 @@ func TestPatternMatcher_IBAN(t *testing.T) {
 	}
 
 	// Invalid checksum
-	_, ok = pm.match("FR7630006000011234567890180")
+	_, ok = matchFirst(pm, "FR7630006000011234567890180")
 	if ok {
 		t.Error("expected no match for invalid IBAN checksum")
 	}
 
 	// Wrong country
-	_, ok = pm.match("DE89370400440532013000")
+	_, ok = matchFirst(pm, "DE89370400440532013000")
 	if ok {
 		t.Error("expected no match for DE IBAN against FR pattern")
 	}
@@ -143,7 +145,7 @@ func TestPatternMatcher_CreditCard(t *testing.T) {
 		{"9999999999999999", "", false}, // no pattern match
 	}
 	for _, tt := range tests {
-		name, ok := pm.match(tt.input)
+		name, ok := matchFirst(pm, tt.input)
 		if ok != tt.found {
 			t.Errorf("match(%q) found=%v, want %v", tt.input, ok, tt.found)
 		}
@@ -168,7 +170,7 @@ func TestPatternMatcher_Email(t *testing.T) {
 		"a@b.co",
 	}
 	for _, email := range valid {
-		_, ok := pm.match(email)
+		_, ok := matchFirst(pm, email)
 		if !ok {
 			t.Errorf("expected match for %q", email)
 		}
@@ -181,7 +183,7 @@ func TestPatternMatcher_Email(t *testing.T) {
 		"user@.com",
 	}
 	for _, email := range invalid {
-		_, ok := pm.match(email)
+		_, ok := matchFirst(pm, email)
 		if ok {
 			t.Errorf("expected no match for %q", email)
 		}
@@ -198,7 +200,7 @@ func TestPatternMatcher_SpaceStripping(t *testing.T) {
 	}
 
 	// IBAN with spaces — should still match after stripping.
-	name, ok := pm.match("FR76 3000 6000 0112 3456 7890 189")
+	name, ok := matchFirst(pm, "FR76 3000 6000 0112 3456 7890 189")
 	if !ok {
 		t.Fatal("expected match for IBAN with spaces")
 	}
@@ -359,3 +361,192 @@ format:
 		t.Errorf("entity_type = %q, want surname", result.Matches[0].EntityType)
 	}
 }
+
+// matchFirst returns the first matching pattern name, like the original single-result matcher.
+func matchFirst(pm *patternMatcher, term string) (string, bool) {
+	hits := pm.match(term)
+	if len(hits) == 0 {
+		return "", false
+	}
+	return hits[0].name, true
+}
+
+func TestPatternMatcher_AllMatches(t *testing.T) {
+	specs := []PatternSpec{
+		{Name: "steuerid_de", Regex: `^\d{11}$`},
+		{Name: "pesel_pl", Regex: `^\d{11}$`, Checksum: &ChecksumSpec{Weights: []int{1, 3, 7, 9}, Modulus: 10, Complement: true}},
+		{Name: "oib_hr", Regex: `^\d{11}$`},
+		{Name: "iban_fr", Regex: `^FR\d{12}$`},
+	}
+	pm, err := compilePatterns(specs)
+	if err != nil {
+		t.Fatalf("compilePatterns: %v", err)
+	}
+
+	got := hitNames(pm.match("44051401359"))
+	want := []string{"steuerid_de", "pesel_pl", "oib_hr"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("match = %v, want %v", got, want)
+	}
+
+	got = hitNames(pm.match("44051401358")) // bad PESEL checksum
+	want = []string{"steuerid_de", "oib_hr"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("match = %v, want %v", got, want)
+	}
+}
+
+func TestPatternMatcher_PrefixDispatch(t *testing.T) {
+	specs := []PatternSpec{
+		{Name: "iban_fr", Regex: `^FR\d{2}\d{10}[A-Z0-9]{11}\d{2}$`, Validator: "mod97"},
+		{Name: "iban_de", Regex: `^DE\d{2}\d{18}$`, Validator: "mod97"},
+		{Name: "mobile_fr", Regex: `^(\+33|0033|0)[67]\d{8}$`},
+	}
+	pm, err := compilePatterns(specs)
+	if err != nil {
+		t.Fatalf("compilePatterns: %v", err)
+	}
+
+	if got := pm.byFirst['D']; len(got) != 1 || got[0] != 1 {
+		t.Errorf("byFirst['D'] = %v, want [1]", got)
+	}
+	if got := pm.byFirst['0']; len(got) != 1 || got[0] != 2 {
+		t.Errorf("byFirst['0'] = %v, want [2]", got)
+	}
+	if got := pm.byFirst['X']; len(got) != 0 {
+		t.Errorf("byFirst['X'] = %v, want none", got)
+	}
+	if f := pm.patterns[0].filter; f.prefix != "FR" || f.minLen != 27 || f.maxLen != 27 {
+		t.Errorf("iban_fr filter = prefix %q len %d..%d, want FR 27..27", f.prefix, f.minLen, f.maxLen)
+	}
+	if f := pm.patterns[2].filter; f.minLen != 10 || f.maxLen != 13 {
+		t.Errorf("mobile_fr filter len = %d..%d, want 10..13", f.minLen, f.maxLen)
+	}
+
+	if name, ok := matchFirst(pm, "DE89370400440532013000"); !ok || name != "iban_de" {
+		t.Errorf("match DE = %q, %v", name, ok)
+	}
+	if name, ok := matchFirst(pm, "0612345678"); !ok || name != "mobile_fr" {
+		t.Errorf("match mobile = %q, %v", name, ok)
+	}
+	if _, ok := matchFirst(pm, "F"); ok {
+		t.Error("expected no match for term shorter than prefix")
+	}
+}
+
+func TestLiteralPrefix(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`^FR\d{2}$`, "FR"},
+		{`^4\d{12}(\d{3})?$`, "4"},
+		{`^[A-Z]{2}\d+$`, ""},
+		{`FR\d{2}`, ""},           // unanchored
+		{`^(?i)fr\d{2}$`, ""},     // case-insensitive
+		{`^(\+33|0033|0)\d$`, ""}, // alternation
+		{`[invalid`, ""},
+	}
+	for _, tt := range tests {
+		if got := analyzePattern(tt.expr).prefix; got != tt.want {
+			t.Errorf("analyzePattern(%q).prefix = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestDictionary_Classify_AllPatterns(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(`id: ids
+version: "1.0"
+jurisdiction: eu
+entity_type: national_id
+source: test
+method: pattern
+patterns:
+  - name: a
+    regex: "^\\d{9}$"
+  - name: b
+    regex: "^\\d{9}$"
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadDictionary(dir)
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+
+	entry, ok := d.Classify("123456789")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if entry.Metadata["pattern"] != "a" || entry.Metadata["patterns"] != "a,b" {
+		t.Errorf("metadata = %v, want pattern=a patterns=a,b", entry.Metadata)
+	}
+	if got := d.MatchPatterns("123456789"); len(got) != 2 {
+		t.Errorf("MatchPatterns = %v, want 2 names", got)
+	}
+}
+
+func TestAnalyzePattern_Conservative(t *testing.T) {
+	// Patterns the filter cannot narrow must still be tried for every term.
+	for _, expr := range []string{`FR\d{2}`, `^\d*`, `^(?i)fr\d+$`, `^.+@.+$`} {
+		f := analyzePattern(expr)
+		re := regexp.MustCompile(expr)
+		for _, term := range []string{"FR12", "fr12", "xFR12", "a@b", "9"} {
+			if re.MatchString(term) && (!f.first[term[0]] || !f.accepts(term)) {
+				t.Errorf("analyzePattern(%q) rejects %q, which the regex matches", expr, term)
+			}
+		}
+	}
+}
+
+func TestDictionary_MatchesPatternAndPrefix(t *testing.T) {
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", "iban"))
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+	if !d.MatchesPattern("iban_fr", "FR76 3000 6000 0112 3456 7890 189") {
+		t.Error("valid French IBAN rejected")
+	}
+	if d.MatchesPattern("iban_de", "FR7630006000011234567890189") {
+		t.Error("French IBAN accepted as iban_de")
+	}
+	if d.MatchesPattern("iban_fr", "FR7630006000011234567890188") {
+		t.Error("bad checksum accepted")
+	}
+	if got := d.PatternPrefix("iban_fr", "FR7630006000011234567890189"); got != "FR" {
+		t.Errorf("PatternPrefix = %q, want FR", got)
+	}
+}
+
+func TestDictionary_PatternDensity(t *testing.T) {
+	pm, err := compilePatterns([]PatternSpec{
+		{Name: "code", Regex: `^[A-Z]{2}\d{6}$`},
+		{Name: "card", Regex: `^4\d{15}$`, Validator: "luhn"},
+		{Name: "nir", Regex: `^[12]\d{2}(0[1-9]|1[0-2]|[2-9]\d)(\d{2}|2[AB])\d{8}$`, Validator: "nir"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Dictionary{patterns: pm}
+	tests := []struct {
+		name, term string
+		lo, hi     float64
+	}{
+		{"code", "AB123456", 1, 1},
+		{"card", "4111111111111111", 0.08, 0.12},
+		{"nir", "185017512345609", 0.05, 0.2}, // the key is computed
+		{"card", "AB123456", 0, 0}, // not a card number
+	}
+	for _, tt := range tests {
+		if got := d.PatternDensity(tt.name, tt.term); got < tt.lo || got > tt.hi {
+			t.Errorf("PatternDensity(%s, %s) = %.4f, want %.4f..%.4f", tt.name, tt.term, got, tt.lo, tt.hi)
+		}
+	}
+	if c := d.PatternCheck("nir", "185017512345609"); c == nil || c.Len != 2 {
+		t.Errorf("PatternCheck(nir) = %+v, want a 2-digit key", c)
+	}
+	if c := d.PatternCheck("card", "4111111111111111"); c != nil {
+		t.Errorf("PatternCheck(card) = %+v, want none", c)
+	}
+}
@@ -0,0 +1,249 @@
+// CLAUDE:SUMMARY Static analysis of pattern regexes (first-byte set, literal prefix, length bounds) used to dispatch terms to candidate patterns.
+// CLAUDE:DEPENDS pkg/dict/pattern.go
+package dict
+
+import (
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+)
+
+// patternFilter holds necessary conditions derived from a regex's syntax tree.
+// A term failing any of them cannot match, so the regex need not run.
+type patternFilter struct {
+	first  [256]bool // possible first bytes of a match
+	prefix string    // literal every match starts with ("" if none)
+	minLen int       // minimum match length in bytes
+	maxLen int       // maximum match length in bytes, -1 if unbounded
+}
+
+// analyzePattern derives a patternFilter from a regex. Anything it cannot
+// reason about degrades to "may match" so the filter never rejects a term the
+// regex would accept.
+func analyzePattern(expr string) patternFilter {
+	f := patternFilter{maxLen: -1}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		markAll(&f.first)
+		return f
+	}
+	re = re.Simplify()
+
+	startAnchored := anchoredAt(re, syntax.OpBeginText, true)
+	endAnchored := anchoredAt(re, syntax.OpEndText, false)
+
+	minLen, maxLen := lengthBounds(re)
+	f.minLen = minLen
+	if startAnchored && endAnchored {
+		f.maxLen = maxLen
+	}
+
+	if !startAnchored || firstBytes(re, &f.first) {
+		// Unanchored, or can match the empty string: any first byte will do.
+		markAll(&f.first)
+	}
+	if startAnchored {
+		f.prefix = literalPrefixOf(re)
+	}
+	return f
+}
+
+// literalPrefixOf returns the literal string every match of re, anchored at
+// its start, must start with, or "" when re is case-insensitive at its start
+// or begins with a class or alternation.
+func literalPrefixOf(re *syntax.Regexp) string {
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 || re.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+	lit := re.Sub[1]
+	if lit.Op != syntax.OpLiteral || lit.Flags&syntax.FoldCase != 0 {
+		return ""
+	}
+	return string(lit.Rune)
+}
+
+// anchoredAt reports whether every match of re starts (or ends) with the given anchor.
+func anchoredAt(re *syntax.Regexp, anchor syntax.Op, start bool) bool {
+	switch re.Op {
+	case anchor:
+		return true
+	case syntax.OpCapture:
+		return anchoredAt(re.Sub[0], anchor, start)
+	case syntax.OpConcat:
+		if len(re.Sub) == 0 {
+			return false
+		}
+		if start {
+			return anchoredAt(re.Sub[0], anchor, start)
+		}
+		return anchoredAt(re.Sub[len(re.Sub)-1], anchor, start)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !anchoredAt(sub, anchor, start) {
+				return false
+			}
+		}
+		return len(re.Sub) > 0
+	}
+	return false
+}
+
+// lengthBounds returns the minimum and maximum byte length of a match of re.
+// max is -1 when unbounded.
+func lengthBounds(re *syntax.Regexp) (int, int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		n := 0
+		for _, r := range re.Rune {
+			n += utf8.RuneLen(r)
+		}
+		if re.Flags&syntax.FoldCase != 0 {
+			// Case folding can change byte length (e.g. "k" and the Kelvin sign).
+			return len(re.Rune), len(re.Rune) * utf8.UTFMax
+		}
+		return n, n
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return 0, 0
+		}
+		lo, hi := utf8.RuneLen(re.Rune[0]), utf8.RuneLen(re.Rune[len(re.Rune)-1])
+		if lo < 0 {
+			lo = 1
+		}
+		if hi < 0 {
+			hi = utf8.UTFMax
+		}
+		return lo, hi
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1, utf8.UTFMax
+	case syntax.OpCapture:
+		return lengthBounds(re.Sub[0])
+	case syntax.OpStar:
+		return 0, -1
+	case syntax.OpPlus:
+		lo, _ := lengthBounds(re.Sub[0])
+		return lo, -1
+	case syntax.OpQuest:
+		_, hi := lengthBounds(re.Sub[0])
+		return 0, hi
+	case syntax.OpRepeat:
+		lo, hi := lengthBounds(re.Sub[0])
+		if re.Max < 0 || hi < 0 {
+			return lo * re.Min, -1
+		}
+		return lo * re.Min, hi * re.Max
+	case syntax.OpConcat:
+		var lo, hi int
+		for _, sub := range re.Sub {
+			l, h := lengthBounds(sub)
+			lo += l
+			if hi >= 0 {
+				if h < 0 {
+					hi = -1
+				} else {
+					hi += h
+				}
+			}
+		}
+		return lo, hi
+	case syntax.OpAlternate:
+		lo, hi := -1, 0
+		for _, sub := range re.Sub {
+			l, h := lengthBounds(sub)
+			if lo < 0 || l < lo {
+				lo = l
+			}
+			if hi >= 0 && (h < 0 || h > hi) {
+				hi = h
+			}
+		}
+		if lo < 0 {
+			lo = 0
+		}
+		return lo, hi
+	}
+	// Empty-width assertions and empty matches.
+	return 0, 0
+}
+
+// firstBytes adds the possible first bytes of a match of re to set and
+// reports whether re can match the empty string.
+func firstBytes(re *syntax.Regexp, set *[256]bool) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if len(re.Rune) == 0 {
+			return true
+		}
+		r := re.Rune[0]
+		markRune(set, r)
+		if re.Flags&syntax.FoldCase != 0 {
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				markRune(set, f)
+			}
+		}
+		return false
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			for r := lo; r <= hi && r < utf8.RuneSelf; r++ {
+				set[r] = true
+			}
+			if hi >= utf8.RuneSelf {
+				markNonASCII(set)
+			}
+		}
+		return false
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		markAll(set)
+		return false
+	case syntax.OpCapture, syntax.OpPlus:
+		return firstBytes(re.Sub[0], set)
+	case syntax.OpStar, syntax.OpQuest:
+		firstBytes(re.Sub[0], set)
+		return true
+	case syntax.OpRepeat:
+		nullable := firstBytes(re.Sub[0], set)
+		return nullable || re.Min == 0
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !firstBytes(sub, set) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		nullable := false
+		for _, sub := range re.Sub {
+			if firstBytes(sub, set) {
+				nullable = true
+			}
+		}
+		return nullable
+	case syntax.OpNoMatch:
+		return false
+	}
+	// Empty-width assertions and empty matches.
+	return true
+}
+
+func markRune(set *[256]bool, r rune) {
+	if r < utf8.RuneSelf {
+		set[r] = true
+		return
+	}
+	var buf [utf8.UTFMax]byte
+	utf8.EncodeRune(buf[:], r)
+	set[buf[0]] = true
+}
+
+func markNonASCII(set *[256]bool) {
+	for b := utf8.RuneSelf; b < 256; b++ {
+		set[b] = true
+	}
+}
+
+func markAll(set *[256]bool) {
+	for b := range set {
+		set[b] = true
+	}
+}
@@ -0,0 +1,75 @@
+// CLAUDE:SUMMARY Import lineage of a dictionary: provenance.json, written by the importer next to the data, records the downloads (resolved URL, SHA-256, Last-Modified), adapter version, row counts, metadata totals and timestamps of the build.
+// CLAUDE:DEPENDS pkg/dict/dict.go
+// CLAUDE:EXPORTS Provenance, SourceDownload, RowCounts, ProvenanceFile, LoadProvenance, WriteProvenance
+
+package dict
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProvenanceFile is the name of the provenance record in a dictionary
+// directory.
+const ProvenanceFile = "provenance.json"
+
+// Provenance records how an imported dictionary was built.
+type Provenance struct {
+	Adapter        string           `json:"adapter"`
+	AdapterVersion string           `json:"adapter_version"`
+	Mode           string           `json:"mode"`                  // "full" or "delta"
+	DeltaSince     *time.Time       `json:"delta_since,omitempty"` // delta imports: changes applied from this time
+	SourceDate     time.Time        `json:"source_date"`           // latest Last-Modified of the downloads, else StartedAt
+	Downloads      []SourceDownload `json:"downloads"`
+	Rows           RowCounts        `json:"rows"`
+	Totals         *Totals          `json:"totals,omitempty"` // metadata sums of the data.db, for risk estimates
+	StartedAt      time.Time        `json:"started_at"`
+	FinishedAt     time.Time        `json:"finished_at"`
+}
+
+// SourceDownload is one file an import downloaded.
+type SourceDownload struct {
+	URL          string `json:"url"`
+	ResolvedURL  string `json:"resolved_url"` // after redirects
+	SHA256       string `json:"sha256"`
+	LastModified string `json:"last_modified,omitempty"`
+	Bytes        int64  `json:"bytes"`
+}
+
+// RowCounts are the row statistics of a build. Read and Filtered stay zero
+// for adapters that do not count their source rows.
+type RowCounts struct {
+	Read     int `json:"read"`     // source rows parsed
+	Filtered int `json:"filtered"` // rows read that wrote no entry
+	Kept     int `json:"kept"`     // distinct keys in the dictionary
+	Collided int `json:"collided"` // writes landing on a key already stored
+}
+
+// LoadProvenance reads the provenance record of the dictionary in dir, nil
+// when it has none.
+func LoadProvenance(dir string) (*Provenance, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ProvenanceFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read provenance: %w", err)
+	}
+	var p Provenance
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse provenance: %w", err)
+	}
+	return &p, nil
+}
+
+// WriteProvenance writes p as the provenance record of the dictionary in dir.
+func WriteProvenance(dir string, p *Provenance) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal provenance: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, ProvenanceFile), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,261 @@
+// CLAUDE:SUMMARY k-anonymous hash-prefix lookup: SHA-256 range indexes of dictionary keys (precomputed in data.db, built lazily for in-memory dicts) answering "which hashes start with this prefix" without the server seeing the term.
+// CLAUDE:DEPENDS pkg/dict/sqlite.go, pkg/dict/registry.go
+// CLAUDE:EXPORTS TermHash, RangePrefixMin, RangePrefixMax, RangeDict, RangeMatch, RangeResult, EnsureRangeIndex
+
+package dict
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Hash prefix bounds, in hex characters. Five characters split the hash
+// space in about a million ranges, as in "Have I Been Pwned" range queries.
+const (
+	RangePrefixMin = 5
+	RangePrefixMax = 10
+)
+
+// TermHash is the range-index hash of a normalized term: lowercase hex
+// SHA-256. Clients normalize with the dictionary's mode (lowercase_ascii for
+// most) before hashing.
+func TermHash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// RangeDict is a dictionary holding a hash of a range response.
+type RangeDict struct {
+	DictID     string `json:"dict_id"`
+	EntityType string `json:"entity_type"`
+	Normalize  string `json:"normalize"`
+}
+
+// RangeMatch is one hash of a range, by its suffix after the prefix.
+type RangeMatch struct {
+	Suffix string      `json:"suffix"`
+	Dicts  []RangeDict `json:"dicts"`
+}
+
+// RangeResult lists the hashes starting with Prefix, sorted by suffix.
+type RangeResult struct {
+	Prefix  string       `json:"prefix"`
+	Hash    string       `json:"hash"`
+	Matches []RangeMatch `json:"matches"`
+}
+
+// Range returns every indexed hash starting with prefix across the lookup
+// dictionaries opts selects. The client hashes the normalized term, sends
+// only the prefix and finishes the match locally against the suffixes, so
+// the server never learns which of the range's terms it holds.
+func (r *Registry) Range(prefix string, opts *ClassifyOptions) (*RangeResult, error) {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) < RangePrefixMin || len(prefix) > RangePrefixMax {
+		return nil, fmt.Errorf("prefix must be %d to %d hex characters", RangePrefixMin, RangePrefixMax)
+	}
+	if _, err := hex.DecodeString(prefix + strings.Repeat("0", len(prefix)%2)); err != nil {
+		return nil, fmt.Errorf("prefix must be hexadecimal")
+	}
+
+	r.mu.RLock()
+	ids := make([]string, 0, len(r.dicts))
+	for id, d := range r.dicts {
+		if d.patterns != nil || d.Manifest.Type == "alias_pool" || !opts.selects(d.Manifest) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	dicts := make(map[string]*Dictionary, len(ids))
+	for _, id := range ids {
+		dicts[id] = r.dicts[id]
+	}
+	r.mu.RUnlock()
+	sort.Strings(ids)
+
+	bySuffix := make(map[string][]RangeDict)
+	for _, id := range ids {
+		d := dicts[id]
+		hashes, err := d.rangeHashes(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("dict %s: %w", id, err)
+		}
+		rd := RangeDict{DictID: id, EntityType: d.Manifest.EntityType, Normalize: d.Manifest.Format.Normalize}
+		if rd.Normalize == "" {
+			rd.Normalize = "lowercase_ascii"
+		}
+		for _, h := range hashes {
+			bySuffix[h[len(prefix):]] = append(bySuffix[h[len(prefix):]], rd)
+		}
+	}
+
+	res := &RangeResult{Prefix: prefix, Hash: "sha256", Matches: make([]RangeMatch, 0, len(bySuffix))}
+	for suffix, ds := range bySuffix {
+		res.Matches = append(res.Matches, RangeMatch{Suffix: suffix, Dicts: ds})
+	}
+	sort.Slice(res.Matches, func(i, j int) bool { return res.Matches[i].Suffix < res.Matches[j].Suffix })
+	return res, nil
+}
+
+// selects reports whether the options keep dictionary m.
+func (o *ClassifyOptions) selects(m *Manifest) bool {
+	if o == nil {
+		return true
+	}
+	return (len(o.Jurisdictions) == 0 || contains(o.Jurisdictions, m.Jurisdiction)) &&
+		(len(o.Types) == 0 || contains(o.Types, m.EntityType)) &&
+		(len(o.Dicts) == 0 || contains(o.Dicts, m.ID)) &&
+		(o.Filter == nil || o.Filter(m))
+}
+
+// rangeHashes returns the hashes of d's keys starting with prefix. SQLite
+// dictionaries read their precomputed range_index table; in-memory ones
+// hash their keys on first use.
+func (d *Dictionary) rangeHashes(prefix string) ([]string, error) {
+	if d.db != nil {
+		if !d.rangeIndexed {
+			return nil, nil
+		}
+		return rangeSQLite(d.db, prefix)
+	}
+
+	hashes := memRangeIndex(d)
+	i := sort.SearchStrings(hashes, prefix)
+	var out []string
+	for ; i < len(hashes) && strings.HasPrefix(hashes[i], prefix); i++ {
+		out = append(out, hashes[i])
+	}
+	return out, nil
+}
+
+func rangeSQLite(db *sql.DB, prefix string) ([]string, error) {
+	// Hashes are lowercase hex: every hash with the prefix sorts before
+	// prefix + "g".
+	rows, err := db.Query(`SELECT hash FROM range_index WHERE hash >= ? AND hash < ? ORDER BY hash`, prefix, prefix+"g")
+	if err != nil {
+		return nil, fmt.Errorf("query range: %w", err)
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return nil, fmt.Errorf("scan range: %w", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// memRangeIndex is the sorted hash list of an in-memory dictionary.
+type memRangeEntry struct {
+	d      *Dictionary
+	hashes []string
+}
+
+var (
+	memRangeMu    sync.Mutex
+	memRangeCache = make(map[string]*memRangeEntry) // dict ID → index
+)
+
+// memRangeIndex returns the sorted key hashes of d, computing them on first
+// use and again after a reload replaced the dictionary.
+func memRangeIndex(d *Dictionary) []string {
+	memRangeMu.Lock()
+	defer memRangeMu.Unlock()
+	if e := memRangeCache[d.Manifest.ID]; e != nil && e.d == d {
+		return e.hashes
+	}
+	hashes := make([]string, 0, len(d.Entries))
+	for key := range d.Entries {
+		hashes = append(hashes, TermHash(key))
+	}
+	sort.Strings(hashes)
+	memRangeCache[d.Manifest.ID] = &memRangeEntry{d: d, hashes: hashes}
+	return hashes
+}
+
+const rangeIndexSchema = `CREATE TABLE IF NOT EXISTS range_index (hash TEXT PRIMARY KEY) WITHOUT ROWID`
+
+// EnsureRangeIndex adds the range_index table to a data.db built before range
+// queries existed. It reports whether the index was built.
+func EnsureRangeIndex(path string) (bool, error) {
+	db, err := sql.Open("sqlite", path+"?_txlock=immediate&_pragma=journal_mode(wal)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return false, fmt.Errorf("open sqlite: %w", err)
+	}
+	defer db.Close()
+
+	if ok, err := hasRangeIndex(db); err != nil || ok {
+		return false, err
+	}
+	// The index is built under another name, one batch of keys per
+	// transaction, and renamed once complete: an interrupted build leaves no
+	// range_index behind and is started over.
+	if _, err := db.Exec(`DROP TABLE IF EXISTS range_index_build`); err != nil {
+		return false, fmt.Errorf("create range index: %w", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE range_index_build (hash TEXT PRIMARY KEY) WITHOUT ROWID`); err != nil {
+		return false, fmt.Errorf("create range index: %w", err)
+	}
+	last, more := "", true
+	for more {
+		if last, more, err = indexKeysAfter(db, last); err != nil {
+			return false, err
+		}
+	}
+	if _, err := db.Exec(`ALTER TABLE range_index_build RENAME TO range_index`); err != nil {
+		return false, fmt.Errorf("create range index: %w", err)
+	}
+	return true, nil
+}
+
+// indexKeysAfter inserts the hashes of the sqliteBatchSize keys that follow
+// after into range_index_build, and returns the last one, and whether more
+// may follow.
+func indexKeysAfter(db *sql.DB, after string) (last string, more bool, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", false, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO range_index_build (hash) VALUES (?)`)
+	if err != nil {
+		return "", false, fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := tx.Query(`SELECT key FROM terms WHERE key > ? ORDER BY key LIMIT ?`, after, sqliteBatchSize)
+	if err != nil {
+		return "", false, fmt.Errorf("query terms: %w", err)
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		if err := rows.Scan(&last); err != nil {
+			return "", false, fmt.Errorf("scan term: %w", err)
+		}
+		if _, err := stmt.Exec(TermHash(last)); err != nil {
+			return "", false, fmt.Errorf("insert hash: %w", err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return "", false, err
+	}
+	rows.Close()
+	return last, n == sqliteBatchSize, tx.Commit()
+}
+
+func hasRangeIndex(db *sql.DB) (bool, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'range_index'`).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check range index: %w", err)
+	}
+	return n > 0, nil
+}
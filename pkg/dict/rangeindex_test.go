@@ -0,0 +1,122 @@
+package dict
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// rangeHas reports whether res holds hash in dictionary id.
+func rangeHas(res *RangeResult, hash, id string) bool {
+	for _, m := range res.Matches {
+		if res.Prefix+m.Suffix != hash {
+			continue
+		}
+		for _, d := range m.Dicts {
+			if d.DictID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestRange(t *testing.T) {
+	dir := writeTestDict(t, "mem", "lowercase_ascii", "term;frequency\nDUPONT;1200\nMartin;3500\n")
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	h := TermHash("dupont")
+	res, err := reg.Range(strings.ToUpper(h[:5]), nil)
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if res.Prefix != h[:5] || !rangeHas(res, h, "mem") {
+		t.Errorf("Range(%s) = %+v, want suffix of dupont in mem", h[:5], res)
+	}
+	if d := res.Matches[0].Dicts[0]; d.EntityType != "test_type" || d.Normalize != "lowercase_ascii" {
+		t.Errorf("dict = %+v", d)
+	}
+
+	res, _ = reg.Range(h[:5], &ClassifyOptions{Dicts: []string{"other"}})
+	if len(res.Matches) != 0 {
+		t.Errorf("filtered range = %+v, want none", res.Matches)
+	}
+
+	for _, bad := range []string{"abcd", "abcdef01234", "xyz12"} {
+		if _, err := reg.Range(bad, nil); err == nil {
+			t.Errorf("Range(%q): expected error", bad)
+		}
+	}
+}
+
+func TestRange_SQLite(t *testing.T) {
+	dir := writeTestDict(t, "disk", "lowercase_ascii", "term;frequency\nIGNORED;1\n")
+	if err := SaveSQLite(map[string]*Entry{"durand": {}, "bernard": {}}, filepath.Join(dir, "disk", "data.db")); err != nil {
+		t.Fatalf("SaveSQLite: %v", err)
+	}
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	defer reg.Close()
+
+	h := TermHash("durand")
+	res, err := reg.Range(h[:6], nil)
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if !rangeHas(res, h, "disk") {
+		t.Errorf("Range(%s) = %+v, want durand", h[:6], res)
+	}
+}
+
+func TestEnsureRangeIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE terms (key TEXT PRIMARY KEY, metadata TEXT) WITHOUT ROWID;
+		INSERT INTO terms (key, metadata) VALUES ('martin', '');
+		WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		INSERT INTO terms (key, metadata) SELECT 'key' || i, '' FROM n`, sqliteBatchSize+10); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	d := &Dictionary{Manifest: &Manifest{ID: "old"}}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	if hashes, err := d.rangeHashes(TermHash("martin")[:5]); err != nil || len(hashes) != 0 {
+		t.Errorf("unindexed db: hashes = %v, err = %v", hashes, err)
+	}
+	d.Close()
+
+	if built, err := EnsureRangeIndex(path); err != nil || !built {
+		t.Fatalf("EnsureRangeIndex = %v, %v", built, err)
+	}
+	if built, err := EnsureRangeIndex(path); err != nil || built {
+		t.Errorf("second EnsureRangeIndex = %v, %v, want no-op", built, err)
+	}
+
+	d = &Dictionary{Manifest: &Manifest{ID: "old"}}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	defer d.Close()
+	h := TermHash("martin")
+	if hashes, err := d.rangeHashes(h[:5]); err != nil || len(hashes) != 1 || hashes[0] != h {
+		t.Errorf("indexed db: hashes = %v, err = %v", hashes, err)
+	}
+	var n int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM range_index`).Scan(&n); err != nil || n != sqliteBatchSize+11 {
+		t.Errorf("range_index holds %d hashes, err = %v, want %d", n, err, sqliteBatchSize+11)
+	}
+}
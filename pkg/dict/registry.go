@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -15,6 +17,7 @@ type Registry struct {
 	dicts      map[string]*Dictionary
 	aliasPools map[string][]AliasEntry // domain → entries
 	dictsDir   string
+	poolMu     sync.Mutex // serializes alias pool manifest writes
 }
 
 // NewRegistry creates a new empty registry for the given directory.
@@ -36,7 +39,7 @@ func (r *Registry) Load() error {
 	newDicts := make(map[string]*Dictionary)
 	newAliases := make(map[string][]AliasEntry)
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !IsDictDir(entry.Name()) { // imported dictionaries are links to their build
 			continue
 		}
 		dir := filepath.Join(r.dictsDir, entry.Name())
@@ -54,14 +57,7 @@ func (r *Registry) Load() error {
 			if m.Domain != "" {
 				newAliases[m.Domain] = m.AliasEntries
 			}
-			// Also load as a dictionary for DictInfo listing.
-			d := &Dictionary{
-				Manifest:  m,
-				Entries:   make(map[string]*Entry),
-				normalize: GetNormalizer(m.Format.Normalize),
-				dir:       dir,
-			}
-			newDicts[m.ID] = d
+			newDicts[m.ID] = aliasPoolDict(dir, m)
 			continue
 		}
 
@@ -83,11 +79,74 @@ func (r *Registry) Load() error {
 	return nil
 }
 
+// PrevSuffix names the link to the previous build of a dictionary, kept by
+// the importer for rollback.
+const PrevSuffix = ".prev"
+
+// IsDictDir reports whether a directory of the dicts directory may hold a
+// live dictionary: importer work directories (_*), hidden directories and
+// previous builds (*.prev) do not.
+func IsDictDir(name string) bool {
+	return !strings.HasPrefix(name, "_") && !strings.HasPrefix(name, ".") && !strings.HasSuffix(name, PrevSuffix)
+}
+
 // Reload reloads all dictionaries from disk (hot reload).
 func (r *Registry) Reload() error {
 	return r.Load()
 }
 
+// ReloadDict reloads a single dictionary from its directory, leaving the
+// others untouched.
+func (r *Registry) ReloadDict(id string) error {
+	r.mu.RLock()
+	old, ok := r.dicts[id]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("dictionary %q not loaded", id)
+	}
+
+	m, err := LoadManifest(filepath.Join(old.dir, "manifest.yaml"))
+	if err != nil {
+		return err
+	}
+	if m.ID != id {
+		return fmt.Errorf("manifest in %s now declares id %q, want %q", old.dir, m.ID, id)
+	}
+	var d *Dictionary
+	if m.Type == "alias_pool" {
+		d = aliasPoolDict(old.dir, m)
+	} else if d, err = LoadDictionary(old.dir); err != nil {
+		return fmt.Errorf("load dictionary %s: %w", id, err)
+	}
+
+	r.mu.Lock()
+	if prev := r.dicts[id]; prev != nil && prev.Manifest.Type == "alias_pool" && prev.Manifest.Domain != "" {
+		delete(r.aliasPools, prev.Manifest.Domain)
+	}
+	if m.Type == "alias_pool" && m.Domain != "" {
+		r.aliasPools[m.Domain] = m.AliasEntries
+	}
+	prev := r.dicts[id]
+	r.dicts[id] = d
+	r.mu.Unlock()
+
+	if prev != nil {
+		_ = prev.Close()
+	}
+	return nil
+}
+
+// aliasPoolDict wraps an alias pool manifest as an entry-less dictionary, so
+// pools show up in DictInfo listings.
+func aliasPoolDict(dir string, m *Manifest) *Dictionary {
+	return &Dictionary{
+		Manifest:  m,
+		Entries:   make(map[string]*Entry),
+		normalize: GetNormalizer(m.Format.Normalize),
+		dir:       dir,
+	}
+}
+
 // Match is a single dictionary hit for a classified term.
 type Match struct {
 	DictID       string            `json:"dict_id"`
@@ -109,6 +168,7 @@ type ClassifyOptions struct {
 	Jurisdictions []string
 	Types         []string
 	Dicts         []string
+	Filter        func(*Manifest) bool // when set, dictionaries it rejects are skipped (see pkg/policy)
 }
 
 // Classify looks up a term across all (or filtered) dictionaries.
@@ -141,6 +201,9 @@ func (r *Registry) Classify(term string, opts *ClassifyOptions) *ClassifyResult
 			if len(opts.Dicts) > 0 && !contains(opts.Dicts, d.Manifest.ID) {
 				continue
 			}
+			if opts.Filter != nil && !opts.Filter(d.Manifest) {
+				continue
+			}
 		}
 
 		entry, ok := d.Classify(term)
@@ -159,7 +222,7 @@ func (r *Registry) Classify(term string, opts *ClassifyOptions) *ClassifyResult
 			EntitySpec:   d.Manifest.EntitySpec,
 		}
 		if entry.Metadata != nil {
-			m.Metadata = entry.Metadata
+			m.Metadata = r.withTLDCheck(d, entry.Metadata)
 		}
 		result.Matches = append(result.Matches, m)
 	}
@@ -170,6 +233,28 @@ func (r *Registry) Classify(term string, opts *ClassifyOptions) *ClassifyResult
 	return result
 }
 
+// tldDictID is the dictionary the "tld" of URL and email matches is checked against.
+const tldDictID = "tld"
+
+// withTLDCheck follows up pattern matches that extracted a top-level domain
+// (URLs, emails) with a lookup in the tld dictionary, recording the outcome as
+// "tld_known". Metadata is copied, never modified in place: lookup entries are
+// shared. Without a loaded tld dictionary it is returned unchanged.
+func (r *Registry) withTLDCheck(d *Dictionary, meta map[string]string) map[string]string {
+	tld := meta["tld"]
+	td, ok := r.dicts[tldDictID]
+	if tld == "" || !ok || td == d {
+		return meta
+	}
+	out := make(map[string]string, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
+	}
+	_, known := td.Lookup(tld)
+	out["tld_known"] = strconv.FormatBool(known)
+	return out
+}
+
 // DictInfo is the public metadata for a loaded dictionary.
 type DictInfo struct {
 	ID              string      `json:"id"`
@@ -184,6 +269,7 @@ type DictInfo struct {
 	UpdateFrequency string      `json:"update_frequency,omitempty"`
 	EntitySpec      *EntitySpec `json:"entity_spec,omitempty"`
 	Domain          string      `json:"domain,omitempty"`
+	Provenance      *Provenance `json:"provenance,omitempty"`
 }
 
 // ListDicts returns metadata for all loaded dictionaries, sorted by ID.
@@ -210,6 +296,7 @@ func (r *Registry) ListDicts() []DictInfo {
 			UpdateFrequency: d.Manifest.UpdateFrequency,
 			EntitySpec:      d.Manifest.EntitySpec,
 			Domain:          d.Manifest.Domain,
+			Provenance:      d.Provenance,
 		})
 	}
 	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
@@ -239,6 +326,9 @@ func (r *Registry) Resolve(term string, opts *ClassifyOptions) *ResolveResult {
 			if len(opts.Dicts) > 0 && !contains(opts.Dicts, d.Manifest.ID) {
 				continue
 			}
+			if opts.Filter != nil && !opts.Filter(d.Manifest) {
+				continue
+			}
 		}
 
 		result, ok := d.Resolve(term)
@@ -250,6 +340,14 @@ func (r *Registry) Resolve(term string, opts *ClassifyOptions) *ResolveResult {
 	return &ResolveResult{Match: false}
 }
 
+// Get returns a loaded dictionary by ID.
+func (r *Registry) Get(id string) (*Dictionary, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.dicts[id]
+	return d, ok
+}
+
 // GetAliases returns alias entries for a given domain.
 func (r *Registry) GetAliases(domain string) []AliasEntry {
 	r.mu.RLock()
@@ -66,6 +66,24 @@ format:
 	return reg, dir
 }
 
+// writeDict writes the dictionary id into dir: its manifest and, unless
+// data is empty, its data.csv.
+func writeDict(t *testing.T, dir, id, manifest, data string) {
+	t.Helper()
+	d := filepath.Join(dir, id)
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data != "" {
+		if err := os.WriteFile(filepath.Join(d, "data.csv"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func TestRegistryLoad(t *testing.T) {
 	reg, _ := setupRegistry(t)
 
@@ -77,6 +95,29 @@ func TestRegistryLoad(t *testing.T) {
 	}
 }
 
+func TestRegistryLoad_SkipsWorkDirs(t *testing.T) {
+	reg, dir := setupRegistry(t)
+	src := os.DirFS(filepath.Join(dir, "noms-fr"))
+	for _, name := range []string{"noms-fr" + PrevSuffix, "_staging", ".hidden"} {
+		if err := os.CopyFS(filepath.Join(dir, name), src); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "data.csv"), []byte("term;frequency\nStale;1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reg.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	d, _ := reg.Get("noms-fr")
+	if _, ok := d.Lookup("stale"); ok {
+		t.Error("noms-fr loaded from a work directory")
+	}
+	if _, ok := d.Lookup("martin"); !ok || reg.DictCount() != 2 {
+		t.Errorf("DictCount = %d, want 2 with the live noms-fr", reg.DictCount())
+	}
+}
+
 func TestClassify_Match(t *testing.T) {
 	reg, _ := setupRegistry(t)
 
@@ -196,6 +237,36 @@ func TestListDicts(t *testing.T) {
 	if infos[1].ID != "noms-fr" {
 		t.Errorf("second = %q, want noms-fr", infos[1].ID)
 	}
+	if infos[0].Provenance != nil {
+		t.Errorf("provenance = %+v, want none for a hand-made dictionary", infos[0].Provenance)
+	}
+}
+
+func TestListDicts_Provenance(t *testing.T) {
+	reg, dir := setupRegistry(t)
+	want := &Provenance{
+		Adapter:        "insee-noms",
+		AdapterVersion: "1",
+		Mode:           "full",
+		Downloads:      []SourceDownload{{URL: "https://example.org/noms.zip", SHA256: "abc", Bytes: 42}},
+		Rows:           RowCounts{Read: 4, Filtered: 1, Kept: 3},
+	}
+	if err := WriteProvenance(filepath.Join(dir, "noms-fr"), want); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range reg.ListDicts() {
+		if info.ID != "noms-fr" {
+			continue
+		}
+		if p := info.Provenance; p == nil || p.Adapter != want.Adapter || p.Rows != want.Rows || len(p.Downloads) != 1 {
+			t.Errorf("provenance = %+v, want %+v", p, want)
+		}
+		return
+	}
+	t.Fatal("noms-fr not listed")
 }
 
 func TestReload(t *testing.T) {
@@ -254,3 +325,28 @@ func TestEmptyRegistry(t *testing.T) {
 		t.Errorf("matches = %d, want 0", len(result.Matches))
 	}
 }
+
+func TestReloadDict(t *testing.T) {
+	reg, dir := setupRegistry(t)
+	other, _ := reg.Get("firstnames-uk")
+
+	if err := os.WriteFile(filepath.Join(dir, "noms-fr", "data.csv"), []byte("term;frequency\nLEROY;900\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.ReloadDict("noms-fr"); err != nil {
+		t.Fatalf("ReloadDict: %v", err)
+	}
+
+	if r := reg.Classify("Leroy", nil); len(r.Matches) != 1 || r.Matches[0].DictID != "noms-fr" {
+		t.Errorf("Leroy matches = %+v", r.Matches)
+	}
+	if r := reg.Classify("DUPONT", nil); len(r.Matches) != 0 {
+		t.Errorf("DUPONT still matches: %+v", r.Matches)
+	}
+	if d, _ := reg.Get("firstnames-uk"); d != other {
+		t.Error("ReloadDict replaced another dictionary")
+	}
+	if err := reg.ReloadDict("nope"); err == nil {
+		t.Error("expected error for an unknown dictionary")
+	}
+}
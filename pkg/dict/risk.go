@@ -0,0 +1,284 @@
+// CLAUDE:SUMMARY Re-identification risk of a batch of terms: estimates how many people share the whole combination from name frequencies, geography populations and birth dates, k-anonymity style, and ranks the terms by identifying power.
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/dict/dict.go
+// CLAUDE:EXPORTS RiskOptions, RiskFactor, RiskReport, Totals, SumTotals, DefaultRiskPopulation, DefaultRiskK
+package dict
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRiskPopulation is the population the terms are drawn from when the
+// request does not set one (France).
+const DefaultRiskPopulation = 68_000_000
+
+// DefaultRiskK is the default k-anonymity threshold.
+const DefaultRiskK = 5
+
+// birthYearSpan is the number of birth years living people spread over.
+const birthYearSpan = 100
+
+// RiskOptions tunes a risk estimate.
+type RiskOptions struct {
+	Population float64 // people the document's subject is drawn from; DefaultRiskPopulation when zero
+	K          int     // k-anonymity threshold; DefaultRiskK when zero
+	Classify   *ClassifyOptions
+}
+
+// RiskFactor is one term's contribution. Share is the estimated fraction of
+// the population sharing the term, Bits the information it carries
+// (-log2 share) and Contribution its part of the report's total bits.
+type RiskFactor struct {
+	Term         string  `json:"term"`
+	DictID       string  `json:"dict_id,omitempty"`
+	EntityType   string  `json:"entity_type"`
+	Basis        string  `json:"basis"` // direct, frequency, rank, population, uniform, date, birth_year
+	Share        float64 `json:"share"`
+	Bits         float64 `json:"bits"`
+	Contribution float64 `json:"contribution"`
+}
+
+// RiskReport estimates how identifying a combination of terms is. EstimatedK
+// is the expected number of people sharing every term, assuming the terms
+// describe one person and are independent.
+type RiskReport struct {
+	Population       float64      `json:"population"`
+	K                int          `json:"k"`
+	EstimatedK       float64      `json:"estimated_k"`
+	Reidentification float64      `json:"reidentification"` // 1 / max(1, estimated_k)
+	Bits             float64      `json:"bits"`
+	Level            string       `json:"level"` // high (estimated_k < k), medium (< 10k), low
+	Factors          []RiskFactor `json:"factors"`
+	Ignored          []string     `json:"ignored,omitempty"` // terms carrying no quasi-identifier
+}
+
+// directIdentifierTypes single out one person on their own.
+var directIdentifierTypes = map[string]bool{
+	"nir": true, "national_id": true, "id_document": true, "mrz": true,
+	"email": true, "phone": true, "iban": true, "credit_card": true,
+	"vehicle_plate": true, "ip_address": true, "mac_address": true, "crypto_wallet": true,
+}
+
+// quasiIdentifierTypes narrow the population down without metadata: without
+// a frequency, rank or population, entries are taken as equally likely.
+var quasiIdentifierTypes = map[string]bool{
+	"surname": true, "first_name": true, "firstname": true,
+	"city": true, "municipality": true, "arrondissement": true, "postcode": true,
+	"department": true, "region": true, "location": true, "address": true,
+}
+
+// basisQuality ranks the estimates of a term matching several dictionaries:
+// the best-informed wins, then the most identifying.
+var basisQuality = map[string]int{
+	"direct": 3, "frequency": 2, "rank": 2, "population": 2, "date": 2, "birth_year": 2, "uniform": 1,
+}
+
+// Risk estimates the re-identification risk of terms taken together: "a rare
+// surname + a small commune + a birth year is likely unique". Each term
+// matches the population share its best dictionary match gives it (name
+// frequency over the dictionary total, Zipf from rank, commune population
+// over the dictionary total, 1/entries otherwise); a four-digit year counts
+// as a birth year and a direct identifier as unique. Repeated terms count
+// once.
+func (r *Registry) Risk(terms []string, opts *RiskOptions) *RiskReport {
+	rep := &RiskReport{Population: DefaultRiskPopulation, K: DefaultRiskK, Factors: []RiskFactor{}}
+	var classify *ClassifyOptions
+	if opts != nil {
+		if opts.Population > 0 {
+			rep.Population = opts.Population
+		}
+		if opts.K > 0 {
+			rep.K = opts.K
+		}
+		classify = opts.Classify
+	}
+
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		canon := strings.Join(strings.Fields(NormalizeLowercaseASCII(term)), " ")
+		if canon == "" || seen[canon] {
+			continue
+		}
+		seen[canon] = true
+
+		f, ok := r.riskFactor(term, rep.Population, classify)
+		if !ok {
+			rep.Ignored = append(rep.Ignored, term)
+			continue
+		}
+		rep.Factors = append(rep.Factors, f)
+	}
+
+	for _, f := range rep.Factors {
+		rep.Bits += f.Bits
+	}
+	for i := range rep.Factors {
+		if rep.Bits > 0 {
+			rep.Factors[i].Contribution = rep.Factors[i].Bits / rep.Bits
+		}
+	}
+	sort.SliceStable(rep.Factors, func(i, j int) bool {
+		return rep.Factors[i].Bits > rep.Factors[j].Bits
+	})
+
+	rep.EstimatedK = rep.Population * math.Exp2(-rep.Bits)
+	rep.Reidentification = 1 / math.Max(1, rep.EstimatedK)
+	switch k := float64(rep.K); {
+	case rep.EstimatedK < k:
+		rep.Level = "high"
+	case rep.EstimatedK < 10*k:
+		rep.Level = "medium"
+	default:
+		rep.Level = "low"
+	}
+	return rep
+}
+
+// riskFactor estimates the share of the population sharing term.
+func (r *Registry) riskFactor(term string, population float64, opts *ClassifyOptions) (RiskFactor, bool) {
+	best := RiskFactor{Term: term}
+	found := false
+	if isBirthYear(term) {
+		best = RiskFactor{Term: term, EntityType: "birth_year", Basis: "birth_year", Share: 1.0 / birthYearSpan}
+		found = true
+	}
+
+	for _, m := range r.Classify(term, opts).Matches {
+		share, basis, ok := r.matchShare(m, population)
+		if !ok {
+			continue
+		}
+		cand := RiskFactor{Term: term, DictID: m.DictID, EntityType: m.EntityType, Basis: basis, Share: share}
+		if !found || basisQuality[basis] > basisQuality[best.Basis] ||
+			basisQuality[basis] == basisQuality[best.Basis] && share < best.Share {
+			best, found = cand, true
+		}
+	}
+	if !found {
+		return best, false
+	}
+	best.Share = math.Min(1, math.Max(best.Share, 1/population))
+	best.Bits = -math.Log2(best.Share)
+	return best, true
+}
+
+// matchShare is the population share of one dictionary match.
+func (r *Registry) matchShare(m Match, population float64) (float64, string, bool) {
+	d, ok := r.Get(m.DictID)
+	if !ok {
+		return 0, "", false
+	}
+	if directIdentifierTypes[m.EntityType] {
+		return 1 / population, "direct", true
+	}
+	if m.EntityType == "date" && m.Metadata["date"] != "" {
+		return 1 / (365.25 * birthYearSpan), "date", true
+	}
+	if d.Manifest.Method == "pattern" || d.Manifest.Type == "alias_pool" {
+		return 0, "", false
+	}
+
+	t := totalsFor(d)
+	if f := metaFloat(m.Metadata, "frequency", "freq", "count"); f > 0 && t.Frequency > 0 {
+		return f / t.Frequency, "frequency", true
+	}
+	if rank := metaFloat(m.Metadata, "rank"); rank > 0 && t.entries > 0 {
+		// Zipf: share of rank r among n entries is 1 / (r · H(n)).
+		h := math.Log(float64(t.entries)) + 0.5772156649
+		return 1 / (rank * h), "rank", true
+	}
+	if p := metaFloat(m.Metadata, "population"); p > 0 && t.Population > 0 {
+		return p / t.Population, "population", true
+	}
+	if quasiIdentifierTypes[m.EntityType] && t.entries > 0 {
+		return 1 / float64(t.entries), "uniform", true
+	}
+	return 0, "", false
+}
+
+// isBirthYear reports whether term is a year a living person may be born in.
+func isBirthYear(term string) bool {
+	term = strings.TrimSpace(term)
+	if len(term) != 4 || !isDigits(term) {
+		return false
+	}
+	y, now := atoi(term), time.Now().Year()
+	return y <= now && y > now-birthYearSpan-20
+}
+
+func metaFloat(meta map[string]string, keys ...string) float64 {
+	for _, k := range keys {
+		if v, err := strconv.ParseFloat(strings.TrimSpace(meta[k]), 64); err == nil && v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// Totals sum the entry metadata the shares of a dictionary are taken over.
+// The importer records them in provenance.json; dictionaries held in memory
+// sum them when loaded.
+type Totals struct {
+	Frequency  float64 `json:"frequency"`
+	Population float64 `json:"population"`
+}
+
+// add counts the metadata of e.
+func (t *Totals) add(e *Entry) {
+	if e != nil {
+		t.Frequency += metaFloat(e.Metadata, "frequency", "freq", "count")
+		t.Population += metaFloat(e.Metadata, "population")
+	}
+}
+
+// SumTotals sums the totals of the terms of the data.db open as db, for the
+// importer to record while building it.
+func SumTotals(db *sql.DB) (Totals, error) {
+	var t Totals
+	rows, err := db.Query(`SELECT metadata FROM terms`)
+	if err != nil {
+		return t, fmt.Errorf("sum totals: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var raw sql.NullString
+		if err := rows.Scan(&raw); err != nil {
+			return t, fmt.Errorf("sum totals: %w", err)
+		}
+		if !raw.Valid || raw.String == "" {
+			continue
+		}
+		e := &Entry{}
+		if err := json.Unmarshal([]byte(raw.String), &e.Metadata); err != nil {
+			return t, fmt.Errorf("sum totals: %w", err)
+		}
+		t.add(e)
+	}
+	if err := rows.Err(); err != nil {
+		return t, fmt.Errorf("sum totals: %w", err)
+	}
+	return t, nil
+}
+
+// dictTotals are the totals of a dictionary with its entry count.
+type dictTotals struct {
+	Totals
+	entries int
+}
+
+// totalsFor returns the totals of d, read from its provenance.json when it
+// is SQLite-backed: an imported data.db is never scanned at request time.
+// Without a record, only the entry count is known.
+func totalsFor(d *Dictionary) dictTotals {
+	t := dictTotals{entries: d.EntryCount()}
+	if d.totals != nil {
+		t.Totals = *d.totals
+	}
+	return t
+}
@@ -0,0 +1,140 @@
+package dict
+
+import (
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func setupRiskRegistry(t *testing.T) *Registry {
+	t.Helper()
+	dir := t.TempDir()
+	csvManifest := func(id, entityType, metaCol string) string {
+		m := "id: " + id + "\nversion: \"1.0\"\njurisdiction: fr\nentity_type: " + entityType +
+			"\nsource: test\ndata_file: data.csv\nformat:\n  delimiter: \";\"\n  has_header: true\n  key_column: \"term\"\n  normalize: lowercase_ascii\n"
+		if metaCol != "" {
+			m += "metadata_columns:\n  - name: " + metaCol + "\n    column: \"" + metaCol + "\"\n"
+		}
+		return m
+	}
+
+	writeDict(t, dir, "noms", csvManifest("noms", "surname", "frequency"), "term;frequency\nMARTIN;98999\nDUPONT;1000\nRAREX;1\n")
+	writeDict(t, dir, "communes", csvManifest("communes", "city", "population"), "term;population\nParis;2000000\nTinyville;100\n")
+	writeDict(t, dir, "prenoms", csvManifest("prenoms", "first_name", ""), "term\nJean\nMarie\nLouis\nEmma\n")
+	writeDict(t, dir, "honorifics", csvManifest("honorifics", "honorific", ""), "term\nMonsieur\n")
+	writeDict(t, dir, "email", `id: email
+version: "1.0"
+jurisdiction: intl
+entity_type: email
+source: test
+method: pattern
+patterns:
+  - name: email
+    regex: "^[^@]+@[^@]+$"
+`, "")
+
+	reg := NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+func TestRisk_RareCombination(t *testing.T) {
+	reg := setupRiskRegistry(t)
+	rep := reg.Risk([]string{"Monsieur", "1985", "RAREX", "Tinyville", "rarex"}, nil)
+
+	if rep.Level != "high" || rep.EstimatedK >= 1 || rep.Reidentification != 1 {
+		t.Errorf("level %s, estimated_k %g, reidentification %g", rep.Level, rep.EstimatedK, rep.Reidentification)
+	}
+	if len(rep.Ignored) != 1 || rep.Ignored[0] != "Monsieur" {
+		t.Errorf("ignored = %v", rep.Ignored)
+	}
+	if len(rep.Factors) != 3 {
+		t.Fatalf("factors = %+v", rep.Factors)
+	}
+	want := []struct{ term, basis string }{{"RAREX", "frequency"}, {"Tinyville", "population"}, {"1985", "birth_year"}}
+	var sum float64
+	for i, w := range want {
+		f := rep.Factors[i]
+		if f.Term != w.term || f.Basis != w.basis {
+			t.Errorf("factor %d = %+v, want %s by %s", i, f, w.term, w.basis)
+		}
+		sum += f.Contribution
+	}
+	if f := rep.Factors[0]; math.Abs(f.Share-1e-5) > 1e-12 {
+		t.Errorf("RAREX share = %g, want 1e-5", f.Share)
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("contributions sum to %g", sum)
+	}
+}
+
+func TestRisk_CommonTerms(t *testing.T) {
+	reg := setupRiskRegistry(t)
+	rep := reg.Risk([]string{"Martin", "Paris"}, &RiskOptions{Population: 1e6})
+	if rep.Level != "low" || rep.Population != 1e6 || rep.K != DefaultRiskK {
+		t.Errorf("report = %+v", rep)
+	}
+
+	// Without metadata, entries are equally likely.
+	rep = reg.Risk([]string{"Emma"}, nil)
+	if f := rep.Factors[0]; f.Basis != "uniform" || f.Share != 0.25 {
+		t.Errorf("factor = %+v", f)
+	}
+}
+
+func TestRisk_DirectIdentifier(t *testing.T) {
+	reg := setupRiskRegistry(t)
+	rep := reg.Risk([]string{"jean@example.org"}, &RiskOptions{K: 10})
+	if rep.Level != "high" || rep.K != 10 || math.Abs(rep.EstimatedK-1) > 1e-6 {
+		t.Errorf("report = %+v", rep)
+	}
+	if f := rep.Factors[0]; f.Basis != "direct" || f.EntityType != "email" {
+		t.Errorf("factor = %+v", f)
+	}
+}
+
+func TestRisk_ImportedTotals(t *testing.T) {
+	reg := setupRiskRegistry(t)
+	d, _ := reg.Get("noms")
+	dir := d.dir
+	entries := map[string]*Entry{}
+	if err := d.Each(func(key string, e *Entry) bool { entries[key] = e; return true }); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSQLite(entries, filepath.Join(dir, "data.db")); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals, err := SumTotals(db)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.Frequency != 100000 {
+		t.Fatalf("SumTotals = %+v, want frequency 100000", totals)
+	}
+
+	// Without a provenance record, a data.db is not scanned for totals.
+	if err := reg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if f := reg.Risk([]string{"DUPONT"}, nil).Factors[0]; f.Basis != "uniform" {
+		t.Errorf("factor without totals = %+v, want uniform", f)
+	}
+
+	if err := WriteProvenance(dir, &Provenance{Adapter: "test", Totals: &totals}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if f := reg.Risk([]string{"DUPONT"}, nil).Factors[0]; f.Basis != "frequency" || f.Share != 0.01 {
+		t.Errorf("factor = %+v, want frequency share 0.01", f)
+	}
+}
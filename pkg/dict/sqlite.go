@@ -4,69 +4,27 @@
 package dict
 
 import (
-	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"os"
 )
 
-// SaveSQLite writes entries to a SQLite database at path.
-// The table schema is: terms(key TEXT PRIMARY KEY, metadata TEXT) WITHOUT ROWID.
+// SaveSQLite writes entries to a SQLite database at path through a
+// SQLiteWriter. The table schema is: terms(key TEXT PRIMARY KEY, metadata
+// TEXT) WITHOUT ROWID, with range_index(hash TEXT PRIMARY KEY) WITHOUT ROWID
+// holding the TermHash of every key for range queries.
 func SaveSQLite(entries map[string]*Entry, path string) error {
-	_ = os.Remove(path) // start fresh
-
-	db, err := sql.Open("sqlite", path+"?_txlock=immediate&_pragma=journal_mode(wal)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)")
-	if err != nil {
-		return fmt.Errorf("open sqlite: %w", err)
-	}
-	defer db.Close()
-
-	if _, err := db.Exec(`CREATE TABLE terms (key TEXT PRIMARY KEY, metadata TEXT) WITHOUT ROWID`); err != nil {
-		return fmt.Errorf("create table: %w", err)
-	}
-
-	tx, err := db.BeginTx(context.Background(), nil)
-	if err != nil {
-		return fmt.Errorf("begin: %w", err)
-	}
-	defer tx.Rollback() //nolint:errcheck
-
-	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO terms (key, metadata) VALUES (?, ?)`)
+	w, err := CreateSQLite(path)
 	if err != nil {
-		return fmt.Errorf("prepare: %w", err)
+		return err
 	}
-	defer stmt.Close()
-
-	var n int
 	for key, entry := range entries {
-		var metaJSON []byte
-		if entry != nil && len(entry.Metadata) > 0 {
-			metaJSON, err = json.Marshal(entry.Metadata)
-			if err != nil {
-				return fmt.Errorf("marshal metadata for %q: %w", key, err)
-			}
-		}
-		if _, err := stmt.Exec(key, string(metaJSON)); err != nil {
-			return fmt.Errorf("insert %q: %w", key, err)
-		}
-		n++
-		if n%50000 == 0 {
-			if err := tx.Commit(); err != nil {
-				return fmt.Errorf("commit batch: %w", err)
-			}
-			tx, err = db.BeginTx(context.Background(), nil)
-			if err != nil {
-				return fmt.Errorf("begin batch: %w", err)
-			}
-			stmt, err = tx.Prepare(`INSERT OR REPLACE INTO terms (key, metadata) VALUES (?, ?)`)
-			if err != nil {
-				return fmt.Errorf("prepare batch: %w", err)
-			}
+		if err := w.Put(key, entry); err != nil {
+			w.Abort()
+			return err
 		}
 	}
-
-	return tx.Commit()
+	return w.Close()
 }
 
 // loadSQLite opens a SQLite dict in read-only mode and caches the entry count.
@@ -82,9 +40,15 @@ func (d *Dictionary) loadSQLite(path string) error {
 		db.Close()
 		return fmt.Errorf("count terms: %w", err)
 	}
+	indexed, err := hasRangeIndex(db)
+	if err != nil {
+		db.Close()
+		return err
+	}
 
 	d.db = db
 	d.entryCount = count
+	d.rangeIndexed = indexed
 	return nil
 }
 
@@ -104,3 +68,30 @@ func (d *Dictionary) lookupSQLite(key string) (*Entry, bool) {
 	}
 	return entry, true
 }
+
+// eachSQLite streams every row of the terms table to fn.
+func (d *Dictionary) eachSQLite(fn func(key string, e *Entry) bool) error {
+	rows, err := d.db.Query(`SELECT key, metadata FROM terms`)
+	if err != nil {
+		return fmt.Errorf("query terms: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var metadata sql.NullString
+		if err := rows.Scan(&key, &metadata); err != nil {
+			return fmt.Errorf("scan term: %w", err)
+		}
+		entry := &Entry{}
+		if metadata.Valid && metadata.String != "" {
+			if err := json.Unmarshal([]byte(metadata.String), &entry.Metadata); err != nil {
+				return fmt.Errorf("decode metadata for %q: %w", key, err)
+			}
+		}
+		if !fn(key, entry) {
+			break
+		}
+	}
+	return rows.Err()
+}
@@ -265,6 +265,40 @@ func TestEntryCountSQLite(t *testing.T) {
 	}
 }
 
+func TestEachSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	entries := map[string]*Entry{
+		"dupont": {Metadata: map[string]string{"frequency": "1200"}},
+		"martin": {},
+	}
+	if err := SaveSQLite(entries, path); err != nil {
+		t.Fatalf("SaveSQLite: %v", err)
+	}
+
+	d := &Dictionary{normalize: GetNormalizer("none")}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	defer d.Close()
+
+	got := map[string]string{}
+	if err := d.Each(func(key string, e *Entry) bool {
+		got[key] = e.Metadata["frequency"]
+		return true
+	}); err != nil {
+		t.Fatalf("Each: %v", err)
+	}
+	if len(got) != 2 || got["dupont"] != "1200" {
+		t.Errorf("Each = %v", got)
+	}
+
+	n := 0
+	_ = d.Each(func(string, *Entry) bool { n++; return false })
+	if n != 1 {
+		t.Errorf("Each after stop visited %d entries, want 1", n)
+	}
+}
+
 func TestRegistryTotalEntries_SQLite(t *testing.T) {
 	dir := t.TempDir()
 
@@ -0,0 +1,91 @@
+// CLAUDE:SUMMARY Global registry of named checksum validators referenced by pattern specs (checksums, SIV plates, IP/MAC addresses, crypto wallets).
+// CLAUDE:DEPENDS pkg/dict/pattern.go
+// CLAUDE:EXPORTS ValidatorFunc, RegisterValidator, GetValidator, ValidatorNames, CheckDigits, RegisterCheckDigits
+package dict
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ValidatorFunc reports whether a term (spaces already stripped) passes a checksum.
+type ValidatorFunc func(string) bool
+
+// CheckDigits computes the trailing check of a validator from the payload
+// before it, for validators whose check can be recomputed: a value with any
+// payload and its computed check passes the validator.
+type CheckDigits struct {
+	Len int // characters the check takes at the end of a term
+	// Compute returns the check of payload (spaces stripped), or false when
+	// payload cannot carry one.
+	Compute func(payload string) (string, bool)
+}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[string]ValidatorFunc)
+	checkDigits  = make(map[string]CheckDigits)
+)
+
+func init() {
+	RegisterValidator("mod97", validateMod97)
+	RegisterValidator("luhn", validateLuhn)
+	RegisterValidator("nir", validateNIR)
+	RegisterCheckDigits("nir", CheckDigits{Len: 2, Compute: nirKey})
+	RegisterValidator("siv", validateSIV)
+	RegisterValidator("ipv4", validateIPv4)
+	RegisterValidator("ipv6", validateIPv6)
+	RegisterValidator("mac", validateMAC)
+	RegisterValidator("base58check", validateBase58Check)
+	RegisterValidator("bech32", validateBech32)
+	RegisterValidator("eip55", validateEIP55)
+}
+
+// RegisterValidator adds a named validator to the global registry, replacing any
+// previous validator with the same name. Validators must be registered before
+// the dictionaries that reference them are loaded.
+func RegisterValidator(name string, fn func(string) bool) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = fn
+}
+
+// RegisterCheckDigits declares how the check of the validator name is
+// computed, replacing any previous declaration. Like validators, checks must
+// be registered before the dictionaries that use them are loaded.
+func RegisterCheckDigits(name string, c CheckDigits) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	checkDigits[name] = c
+}
+
+func getCheckDigits(name string) (CheckDigits, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	c, ok := checkDigits[name]
+	return c, ok
+}
+
+// GetValidator returns a registered validator by name, or an error if not found.
+func GetValidator(name string) (ValidatorFunc, error) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown validator %q", name)
+	}
+	return fn, nil
+}
+
+// ValidatorNames returns the names of all registered validators, sorted.
+func ValidatorNames() []string {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package dict
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetValidator_Builtins(t *testing.T) {
+	for _, name := range []string{"mod97", "luhn", "nir"} {
+		if _, err := GetValidator(name); err != nil {
+			t.Errorf("GetValidator(%q): %v", name, err)
+		}
+	}
+	if _, err := GetValidator("nope"); err == nil {
+		t.Error("expected error for unknown validator")
+	}
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("test-even-length", func(s string) bool { return len(s)%2 == 0 })
+	t.Cleanup(func() {
+		validatorsMu.Lock()
+		delete(validators, "test-even-length")
+		validatorsMu.Unlock()
+	})
+
+	found := false
+	for _, name := range ValidatorNames() {
+		if name == "test-even-length" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("ValidatorNames() = %v, missing test-even-length", ValidatorNames())
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(`id: even-test
+version: "1.0"
+jurisdiction: intl
+entity_type: code
+source: test
+method: pattern
+patterns:
+  - name: even
+    regex: "^[A-Z]+$"
+    validator: test-even-length
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := LoadDictionary(dir)
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+	if _, ok := d.Classify("ABCD"); !ok {
+		t.Error("expected match for even-length term")
+	}
+	if _, ok := d.Classify("ABC"); ok {
+		t.Error("expected no match for odd-length term")
+	}
+}
+
+func TestCompilePatterns_UnknownValidatorMessage(t *testing.T) {
+	_, err := compilePatterns([]PatternSpec{{Name: "p", Regex: `^x$`, Validator: "missing"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown validator "missing"`) {
+		t.Errorf("err = %v, want unknown validator", err)
+	}
+}
@@ -0,0 +1,186 @@
+// CLAUDE:SUMMARY Crypto wallet address validators: Bitcoin Base58Check (P2PKH/P2SH), Bech32/Bech32m segwit (BIP-173/350), Ethereum EIP-55 checksum.
+// CLAUDE:DEPENDS pkg/dict/validator.go
+package dict
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// validateBase58Check validates a Bitcoin legacy address: 25 bytes once
+// Base58-decoded (version, 20-byte hash, 4-byte checksum) whose checksum is
+// the first 4 bytes of the double SHA-256 of the rest. The version byte must
+// agree with the leading character (0x00 "1" P2PKH, 0x05 "3" P2SH, 0x6f/0xc4
+// testnet).
+func validateBase58Check(s string) bool {
+	b, ok := base58Decode(s)
+	if !ok || len(b) != 25 {
+		return false
+	}
+	switch b[0] {
+	case 0x00, 0x05, 0x6f, 0xc4:
+	default:
+		return false
+	}
+	first := sha256.Sum256(b[:21])
+	second := sha256.Sum256(first[:])
+	return string(second[:4]) == string(b[21:])
+}
+
+// base58Decode decodes a Base58 string, keeping one zero byte per leading "1".
+func base58Decode(s string) ([]byte, bool) {
+	var out []byte // big-endian
+	for i := 0; i < len(s); i++ {
+		carry := strings.IndexByte(base58Alphabet, s[i])
+		if carry < 0 {
+			return nil, false
+		}
+		for j := len(out) - 1; j >= 0; j-- {
+			carry += int(out[j]) * 58
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append([]byte{byte(carry)}, out...)
+			carry >>= 8
+		}
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	return append(make([]byte, zeros), out...), true
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of BIP-173 (bech32, witness v0) and BIP-350 (bech32m, v1+).
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// validateBech32 validates a segwit address (bc1… mainnet, tb1… testnet):
+// single-case, valid checksum with the constant required by the witness
+// version, and a witness program of legal length.
+func validateBech32(s string) bool {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return false
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || len(s)-sep-1 < 7 || len(s) > 90 {
+		return false
+	}
+	hrp, data := s[:sep], make([]byte, 0, len(s)-sep-1)
+	if hrp != "bc" && hrp != "tb" {
+		return false
+	}
+	for i := sep + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return false
+		}
+		data = append(data, byte(v))
+	}
+
+	version := data[0]
+	want := uint32(bech32Const)
+	if version > 0 {
+		want = bech32mConst
+	}
+	if version > 16 || bech32Polymod(hrp, data) != want {
+		return false
+	}
+
+	program, ok := convertBits(data[1:len(data)-6], 5, 8)
+	if !ok || len(program) < 2 || len(program) > 40 {
+		return false
+	}
+	return version != 0 || len(program) == 20 || len(program) == 32
+}
+
+func bech32Polymod(hrp string, data []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	step := func(v byte) {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 != 0 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] >> 5)
+	}
+	step(0)
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] & 31)
+	}
+	for _, v := range data {
+		step(v)
+	}
+	return chk
+}
+
+// convertBits regroups 5-bit groups into bytes, rejecting non-zero padding.
+func convertBits(data []byte, from, to uint) ([]byte, bool) {
+	var acc, bits uint
+	var out []byte
+	maxv := uint(1)<<to - 1
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if bits >= from || (acc<<(to-bits))&maxv != 0 {
+		return nil, false
+	}
+	return out, true
+}
+
+// validateEIP55 validates an Ethereum address (0x + 40 hex digits). All-lower
+// and all-upper addresses carry no checksum and are accepted; mixed-case ones
+// must follow EIP-55: a letter is uppercase iff the matching nibble of the
+// Keccak-256 of the lowercase address is 8 or more.
+func validateEIP55(s string) bool {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return false
+	}
+	addr := s[2:]
+	if _, err := hex.DecodeString(addr); err != nil {
+		return false
+	}
+	lower := strings.ToLower(addr)
+	if addr == lower || addr == strings.ToUpper(addr) {
+		return true
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	sum := h.Sum(nil)
+	for i := 0; i < len(addr); i++ {
+		c := addr[i]
+		if c < 'A' || (c > 'F' && c < 'a') {
+			continue // digit
+		}
+		nibble := sum[i/2] >> 4
+		if i%2 == 1 {
+			nibble = sum[i/2] & 0x0f
+		}
+		if (nibble >= 8) != (c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
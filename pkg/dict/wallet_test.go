@@ -0,0 +1,92 @@
+package dict
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateBase58Check(t *testing.T) {
+	for _, addr := range []string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", // genesis block
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", // P2SH
+	} {
+		if !validateBase58Check(addr) {
+			t.Errorf("validateBase58Check(%q) = false", addr)
+		}
+	}
+	for _, addr := range []string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", // checksum
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a", // 0 is not Base58
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7Div",    // length
+	} {
+		if validateBase58Check(addr) {
+			t.Errorf("validateBase58Check(%q) = true", addr)
+		}
+	}
+}
+
+func TestValidateBech32(t *testing.T) {
+	for _, addr := range []string{
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+		"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", // taproot, bech32m
+	} {
+		if !validateBech32(addr) {
+			t.Errorf("validateBech32(%q) = false", addr)
+		}
+	}
+	for _, addr := range []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",                     // checksum
+		"bc1QW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",                     // mixed case
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", // v1 with a bech32 checksum
+		"ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",                    // unknown hrp
+	} {
+		if validateBech32(addr) {
+			t.Errorf("validateBech32(%q) = true", addr)
+		}
+	}
+}
+
+func TestValidateEIP55(t *testing.T) {
+	for _, addr := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", // no checksum
+	} {
+		if !validateEIP55(addr) {
+			t.Errorf("validateEIP55(%q) = false", addr)
+		}
+	}
+	for _, addr := range []string{
+		"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", // wrong case
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe",  // length
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", // not hex
+	} {
+		if validateEIP55(addr) {
+			t.Errorf("validateEIP55(%q) = true", addr)
+		}
+	}
+}
+
+func TestCryptoWalletsDict(t *testing.T) {
+	d, err := LoadDictionary(filepath.Join("..", "..", "dicts", "crypto-wallets"))
+	if err != nil {
+		t.Fatalf("LoadDictionary: %v", err)
+	}
+	for term, want := range map[string]string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa":                             "btc_base58",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0": "btc_bech32",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed":                     "eth",
+		"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed":                     "",
+	} {
+		got := ""
+		if entry, ok := d.Classify(term); ok {
+			got = entry.Metadata["pattern"]
+		}
+		if got != want {
+			t.Errorf("Classify(%q) pattern = %q, want %q", term, got, want)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// CLAUDE:SUMMARY Streaming dictionary builder: EntryWriter interface and its SQLite implementation with batched inserts and on-disk deduplication, so imports of tens of millions of rows run in bounded memory; UpdateSQLite edits a copy of an existing data.db for delta imports.
+// CLAUDE:DEPENDS pkg/dict/sqlite.go, pkg/dict/rangeindex.go
+// CLAUDE:EXPORTS EntryWriter, SQLiteWriter, CreateSQLite, UpdateSQLite
+
+package dict
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EntryWriter receives the entries of a dictionary one at a time. Duplicate
+// keys are resolved by the writer, not by the caller, so an importer never
+// needs the whole dictionary in memory.
+type EntryWriter interface {
+	// Put stores e under key, replacing any entry stored there before.
+	Put(key string, e *Entry) error
+	// PutNew stores e under key unless the key is already stored.
+	PutNew(key string, e *Entry) error
+	// Merge stores e under key, or merge(old, e) when the key is already
+	// stored.
+	Merge(key string, e *Entry, merge func(old, e *Entry) *Entry) error
+	// Count returns the number of distinct keys stored so far.
+	Count() int
+}
+
+// sqliteBatchSize is the number of writes per transaction.
+const sqliteBatchSize = 50000
+
+// SQLiteWriter builds a data.db. Rows go to a temporary file next to the
+// target, committed every sqliteBatchSize writes; Close moves it into place,
+// so readers never see a half-built dictionary.
+type SQLiteWriter struct {
+	path, tmp string
+	db        *sql.DB
+	tx        *sql.Tx
+	stmts     map[string]*sql.Stmt // SQL → statement prepared on db
+	txStmts   map[string]*sql.Stmt // SQL → statement bound to tx
+	pending   int
+	count     int
+	collided  int
+}
+
+var _ EntryWriter = (*SQLiteWriter)(nil)
+
+const (
+	sqlInsertTerm = `INSERT OR IGNORE INTO terms (key, metadata) VALUES (?, ?)`
+	sqlUpdateTerm = `UPDATE terms SET metadata = ? WHERE key = ?`
+	sqlSelectTerm = `SELECT metadata FROM terms WHERE key = ?`
+	sqlInsertHash = `INSERT OR IGNORE INTO range_index (hash) VALUES (?)`
+	sqlDeleteTerm = `DELETE FROM terms WHERE key = ?`
+	sqlDeleteHash = `DELETE FROM range_index WHERE hash = ?`
+)
+
+// CreateSQLite starts building the SQLite dictionary at path, with the terms
+// and range_index tables. Close the writer to publish it, or Abort to
+// discard it.
+func CreateSQLite(path string) (*SQLiteWriter, error) {
+	w := newSQLiteWriter(path)
+	w.removeTmp()
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// UpdateSQLite starts building the SQLite dictionary at path from a copy of
+// the data.db at from, whose entries the writer then changes and deletes in
+// place of a full rebuild. from is never modified; Close publishes the copy
+// at path, Abort discards it.
+func UpdateSQLite(from, path string) (*SQLiteWriter, error) {
+	w := newSQLiteWriter(path)
+	w.removeTmp()
+	if err := copyFile(from, w.tmp); err != nil {
+		w.removeTmp()
+		return nil, fmt.Errorf("copy %s: %w", from, err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func newSQLiteWriter(path string) *SQLiteWriter {
+	return &SQLiteWriter{path: path, tmp: path + ".tmp", stmts: make(map[string]*sql.Stmt), txStmts: make(map[string]*sql.Stmt)}
+}
+
+// open opens the temporary database, creating the tables it lacks, and
+// starts the first transaction.
+func (w *SQLiteWriter) open() error {
+	db, err := sql.Open("sqlite", w.tmp+"?_txlock=immediate&_pragma=journal_mode(wal)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return fmt.Errorf("open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	w.db = db
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS terms (key TEXT PRIMARY KEY, metadata TEXT) WITHOUT ROWID`); err != nil {
+		w.Abort()
+		return fmt.Errorf("create table: %w", err)
+	}
+	if _, err := db.Exec(rangeIndexSchema); err != nil {
+		w.Abort()
+		return fmt.Errorf("create range index: %w", err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM terms`).Scan(&w.count); err != nil {
+		w.Abort()
+		return fmt.Errorf("count terms: %w", err)
+	}
+	for _, q := range []string{sqlInsertTerm, sqlUpdateTerm, sqlSelectTerm, sqlInsertHash, sqlDeleteTerm, sqlDeleteHash} {
+		stmt, err := db.Prepare(q)
+		if err != nil {
+			w.Abort()
+			return fmt.Errorf("prepare: %w", err)
+		}
+		w.stmts[q] = stmt
+	}
+	if w.tx, err = db.Begin(); err != nil {
+		w.Abort()
+		return fmt.Errorf("begin: %w", err)
+	}
+	return nil
+}
+
+// Put implements EntryWriter.
+func (w *SQLiteWriter) Put(key string, e *Entry) error {
+	meta, err := marshalMetadata(key, e)
+	if err != nil {
+		return err
+	}
+	inserted, err := w.insert(key, meta)
+	if err != nil || inserted {
+		return err
+	}
+	return w.update(key, meta)
+}
+
+// PutNew implements EntryWriter.
+func (w *SQLiteWriter) PutNew(key string, e *Entry) error {
+	meta, err := marshalMetadata(key, e)
+	if err != nil {
+		return err
+	}
+	_, err = w.insert(key, meta)
+	return err
+}
+
+// Merge implements EntryWriter.
+func (w *SQLiteWriter) Merge(key string, e *Entry, merge func(old, e *Entry) *Entry) error {
+	old, err := w.lookup(key)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return w.PutNew(key, e)
+	}
+	meta, err := marshalMetadata(key, merge(old, e))
+	if err != nil {
+		return err
+	}
+	w.collided++
+	return w.update(key, meta)
+}
+
+// Delete removes key when match, given the entry stored there, returns true,
+// or whatever the entry when match is nil. A missing key is not an error.
+func (w *SQLiteWriter) Delete(key string, match func(old *Entry) bool) error {
+	if match != nil {
+		old, err := w.lookup(key)
+		if err != nil || old == nil || !match(old) {
+			return err
+		}
+	}
+	res, err := w.stmt(sqlDeleteTerm).Exec(key)
+	if err != nil {
+		return fmt.Errorf("delete %q: %w", key, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return w.wrote()
+	}
+	if _, err := w.stmt(sqlDeleteHash).Exec(TermHash(key)); err != nil {
+		return fmt.Errorf("delete hash of %q: %w", key, err)
+	}
+	w.count--
+	return w.wrote()
+}
+
+// Count implements EntryWriter.
+func (w *SQLiteWriter) Count() int {
+	return w.count
+}
+
+// Collisions returns the number of writes so far that landed on a key
+// already stored: replaced by Put, ignored by PutNew or merged by Merge.
+func (w *SQLiteWriter) Collisions() int {
+	return w.collided
+}
+
+// Check commits the rows written so far and runs check against the database
+// being built, before it is published. The writer stays usable.
+func (w *SQLiteWriter) Check(check func(db *sql.DB) error) error {
+	return w.commitBatch(check)
+}
+
+// Close commits the remaining rows and moves the database into place.
+func (w *SQLiteWriter) Close() error {
+	if err := w.tx.Commit(); err != nil {
+		w.Abort()
+		return fmt.Errorf("commit: %w", err)
+	}
+	w.tx = nil
+	if err := w.closeDB(); err != nil {
+		w.removeTmp()
+		return err
+	}
+	// A WAL left by a previous database would be replayed onto this one.
+	_ = os.Remove(w.path + "-wal")
+	_ = os.Remove(w.path + "-shm")
+	if err := os.Rename(w.tmp, w.path); err != nil {
+		w.removeTmp()
+		return fmt.Errorf("publish %s: %w", w.path, err)
+	}
+	return nil
+}
+
+// Abort discards the database being built, leaving any previous one at the
+// target path untouched.
+func (w *SQLiteWriter) Abort() {
+	if w.tx != nil {
+		_ = w.tx.Rollback()
+		w.tx = nil
+	}
+	_ = w.closeDB()
+	w.removeTmp()
+}
+
+func (w *SQLiteWriter) closeDB() error {
+	for q, stmt := range w.stmts {
+		stmt.Close()
+		delete(w.stmts, q)
+	}
+	if w.db == nil {
+		return nil
+	}
+	err := w.db.Close()
+	w.db = nil
+	if err != nil {
+		return fmt.Errorf("close sqlite: %w", err)
+	}
+	return nil
+}
+
+func (w *SQLiteWriter) removeTmp() {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(w.tmp + suffix)
+	}
+}
+
+// stmt returns the statement q bound to the current transaction.
+func (w *SQLiteWriter) stmt(q string) *sql.Stmt {
+	s, ok := w.txStmts[q]
+	if !ok {
+		s = w.tx.Stmt(w.stmts[q])
+		w.txStmts[q] = s
+	}
+	return s
+}
+
+// lookup returns the entry stored under key, nil when there is none.
+func (w *SQLiteWriter) lookup(key string) (*Entry, error) {
+	var raw sql.NullString
+	err := w.stmt(sqlSelectTerm).QueryRow(key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup %q: %w", key, err)
+	}
+	old := &Entry{}
+	if raw.Valid && raw.String != "" {
+		if err := json.Unmarshal([]byte(raw.String), &old.Metadata); err != nil {
+			return nil, fmt.Errorf("decode metadata for %q: %w", key, err)
+		}
+	}
+	return old, nil
+}
+
+// insert adds key unless present and reports whether it did.
+func (w *SQLiteWriter) insert(key, meta string) (bool, error) {
+	res, err := w.stmt(sqlInsertTerm).Exec(key, meta)
+	if err != nil {
+		return false, fmt.Errorf("insert %q: %w", key, err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		w.collided++
+		return false, w.wrote()
+	}
+	if _, err := w.stmt(sqlInsertHash).Exec(TermHash(key)); err != nil {
+		return false, fmt.Errorf("insert hash of %q: %w", key, err)
+	}
+	w.count++
+	return true, w.wrote()
+}
+
+func (w *SQLiteWriter) update(key, meta string) error {
+	if _, err := w.stmt(sqlUpdateTerm).Exec(meta, key); err != nil {
+		return fmt.Errorf("update %q: %w", key, err)
+	}
+	return w.wrote()
+}
+
+// wrote counts a write and commits the batch when full.
+func (w *SQLiteWriter) wrote() error {
+	w.pending++
+	if w.pending < sqliteBatchSize {
+		return nil
+	}
+	return w.commitBatch(nil)
+}
+
+// commitBatch commits the current transaction, runs between, if any, and
+// starts the next transaction.
+func (w *SQLiteWriter) commitBatch(between func(db *sql.DB) error) error {
+	w.pending = 0
+	clear(w.txStmts)
+	if err := w.tx.Commit(); err != nil {
+		return fmt.Errorf("commit batch: %w", err)
+	}
+	var err error
+	if between != nil {
+		err = between(w.db)
+	}
+	tx, beginErr := w.db.Begin()
+	if beginErr != nil {
+		return fmt.Errorf("begin batch: %w", beginErr)
+	}
+	w.tx = tx
+	return err
+}
+
+func marshalMetadata(key string, e *Entry) (string, error) {
+	if e == nil || len(e.Metadata) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("marshal metadata for %q: %w", key, err)
+	}
+	return string(b), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
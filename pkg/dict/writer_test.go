@@ -0,0 +1,221 @@
+package dict
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestSQLiteWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	w, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatalf("CreateSQLite: %v", err)
+	}
+	sum := func(old, e *Entry) *Entry {
+		a, _ := strconv.Atoi(old.Metadata["frequency"])
+		b, _ := strconv.Atoi(e.Metadata["frequency"])
+		return &Entry{Metadata: map[string]string{"frequency": strconv.Itoa(a + b)}}
+	}
+	steps := []error{
+		w.Put("dupont", &Entry{Metadata: map[string]string{"v": "1"}}),
+		w.Put("dupont", &Entry{Metadata: map[string]string{"v": "2"}}),
+		w.PutNew("martin", &Entry{Metadata: map[string]string{"v": "1"}}),
+		w.PutNew("martin", &Entry{Metadata: map[string]string{"v": "2"}}),
+		w.Merge("durand", &Entry{Metadata: map[string]string{"frequency": "10"}}, sum),
+		w.Merge("durand", &Entry{Metadata: map[string]string{"frequency": "5"}}, sum),
+		w.Put("empty", nil),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if w.Count() != 4 {
+		t.Errorf("Count = %d, want 4", w.Count())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("database published before Close")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	d := &Dictionary{Manifest: &Manifest{ID: "w"}, normalize: GetNormalizer("none")}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	defer d.Close()
+	for key, want := range map[string]string{"dupont": "2", "martin": "1"} {
+		if e, ok := d.lookupSQLite(key); !ok || e.Metadata["v"] != want {
+			t.Errorf("%s = %+v, want v=%s", key, e, want)
+		}
+	}
+	if e, ok := d.lookupSQLite("durand"); !ok || e.Metadata["frequency"] != "15" {
+		t.Errorf("durand = %+v, want merged frequency 15", e)
+	}
+	if d.entryCount != 4 || !d.rangeIndexed {
+		t.Errorf("entryCount = %d, rangeIndexed = %v", d.entryCount, d.rangeIndexed)
+	}
+	if hashes, _ := d.rangeHashes(TermHash("martin")[:8]); len(hashes) != 1 {
+		t.Errorf("range index: %v", hashes)
+	}
+}
+
+func TestSQLiteWriter_Batches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	w, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := sqliteBatchSize + 10
+	for i := 0; i < n; i++ {
+		if err := w.PutNew(fmt.Sprintf("k%d", i%(n-5)), &Entry{}); err != nil {
+			t.Fatalf("PutNew %d: %v", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w.Count() != n-5 {
+		t.Errorf("Count = %d, want %d", w.Count(), n-5)
+	}
+}
+
+func TestSQLiteWriter_Check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	w, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Put("a", &Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("boom")
+	err = w.Check(func(db *sql.DB) error {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM terms`).Scan(&n); err != nil || n != 1 {
+			t.Errorf("terms = %d, %v", n, err)
+		}
+		return boom
+	})
+	if err != boom {
+		t.Errorf("Check = %v, want the check error", err)
+	}
+	if err := w.Put("b", &Entry{}); err != nil {
+		t.Fatalf("Put after Check: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w.Count() != 2 {
+		t.Errorf("Count = %d, want 2", w.Count())
+	}
+}
+
+func TestSQLiteWriter_Abort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.db")
+	if err := SaveSQLite(map[string]*Entry{"old": {}}, path); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := CreateSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Put("new", &Entry{}); err != nil {
+		t.Fatal(err)
+	}
+	w.Abort()
+
+	d := &Dictionary{normalize: GetNormalizer("none")}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	defer d.Close()
+	if _, ok := d.lookupSQLite("old"); !ok {
+		t.Error("previous database must survive an aborted build")
+	}
+	if tmp, _ := filepath.Glob(path + ".tmp*"); len(tmp) != 0 {
+		t.Errorf("leftover files: %v", tmp)
+	}
+}
+
+func TestUpdateSQLite(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "live.db")
+	if err := SaveSQLite(map[string]*Entry{
+		"dupont": {Metadata: map[string]string{"id": "1"}},
+		"martin": {Metadata: map[string]string{"id": "2"}},
+		"durand": {Metadata: map[string]string{"id": "3"}},
+	}, from); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "data.db")
+	w, err := UpdateSQLite(from, path)
+	if err != nil {
+		t.Fatalf("UpdateSQLite: %v", err)
+	}
+	if w.Count() != 3 {
+		t.Errorf("Count = %d, want the 3 copied entries", w.Count())
+	}
+	isID := func(id string) func(*Entry) bool {
+		return func(old *Entry) bool { return old.Metadata["id"] == id }
+	}
+	steps := []error{
+		w.Put("martin", &Entry{Metadata: map[string]string{"id": "2", "v": "new"}}),
+		w.Put("bernard", &Entry{Metadata: map[string]string{"id": "4"}}),
+		w.Delete("dupont", nil),
+		w.Delete("durand", isID("9")), // a homonym: kept
+		w.Delete("missing", nil),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w.Count() != 3 {
+		t.Errorf("Count = %d, want 3", w.Count())
+	}
+
+	d := &Dictionary{Manifest: &Manifest{ID: "u"}, normalize: GetNormalizer("none")}
+	if err := d.loadSQLite(path); err != nil {
+		t.Fatalf("loadSQLite: %v", err)
+	}
+	defer d.Close()
+	if _, ok := d.lookupSQLite("dupont"); ok {
+		t.Error("deleted key still present")
+	}
+	if e, ok := d.lookupSQLite("martin"); !ok || e.Metadata["v"] != "new" {
+		t.Errorf("martin = %+v, want the updated entry", e)
+	}
+	for _, key := range []string{"durand", "bernard"} {
+		if _, ok := d.lookupSQLite(key); !ok {
+			t.Errorf("%s missing", key)
+		}
+	}
+	if hashes, _ := d.rangeHashes(TermHash("dupont")[:8]); len(hashes) != 0 {
+		t.Errorf("hash of a deleted key still indexed: %v", hashes)
+	}
+	if hashes, _ := d.rangeHashes(TermHash("bernard")[:8]); len(hashes) != 1 {
+		t.Errorf("hash of an inserted key not indexed: %v", hashes)
+	}
+
+	live := &Dictionary{Manifest: &Manifest{ID: "l"}, normalize: GetNormalizer("none")}
+	if err := live.loadSQLite(from); err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+	if _, ok := live.lookupSQLite("dupont"); !ok {
+		t.Error("the source database must not be modified")
+	}
+}
@@ -30,35 +30,30 @@ func (a *arrondissementsAdapter) DefaultURL() string {
 func (a *arrondissementsAdapter) License() string { return "CC0" }
 
 func (a *arrondissementsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "communes.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseArrondissements(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseArrondissements(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "arrondissement",
 		Source:       "INSEE COG communes",
@@ -69,10 +64,10 @@ func (a *arrondissementsAdapter) Import(ctx context.Context, sourceURL, outputDi
 	})
 }
 
-func parseArrondissements(path string) (map[string]*dict.Entry, error) {
+func parseArrondissements(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,7 +78,7 @@ func parseArrondissements(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -96,7 +91,6 @@ func parseArrondissements(path string) (map[string]*dict.Entry, error) {
 	nameCol := colByNames(colIdx, "LIBELLE", "NCC", "NCCENR")
 	actualCol := colByNames(colIdx, "ACTUAL")
 
-	entries := make(map[string]*dict.Entry, 100)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -105,6 +99,7 @@ func parseArrondissements(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		// Only current entries
 		if actual := safeCol(record, actualCol); actual != "" && actual != "1" {
@@ -129,12 +124,16 @@ func parseArrondissements(path string) (map[string]*dict.Entry, error) {
 			"type": "arrondissement",
 		}
 
-		entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if code != "" {
-			entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d arrondissements\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d arrondissements\n", w.Count())
+	return nil
 }
@@ -30,35 +30,30 @@ func (a *atcAdapter) DefaultURL() string {
 func (a *atcAdapter) License() string { return "CC0" }
 
 func (a *atcAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	path := filepath.Join(dlDir, "atc.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, path); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseATC(path)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseATC(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "drug_code",
 		Source:     "WHO ATC/DDD",
 		SourceURL:  sourceURL,
@@ -68,14 +63,13 @@ func (a *atcAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 	})
 }
 
-func parseATC(path string) (map[string]*dict.Entry, error) {
+func parseATC(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
-	entries := make(map[string]*dict.Entry, 8000)
 	scanner := bufio.NewScanner(f)
 	scanner.Buffer(make([]byte, 1<<20), 1<<20)
 	first := true
@@ -88,6 +82,7 @@ func parseATC(path string) (map[string]*dict.Entry, error) {
 				continue
 			}
 		}
+		rowRead(w)
 
 		// CSV format: atc_code,atc_name,ddd,uom,adm_r,note
 		fields := strings.SplitN(line, ",", 6)
@@ -120,10 +115,14 @@ func parseATC(path string) (map[string]*dict.Entry, error) {
 			meta["uom"] = uom
 		}
 
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
-		entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
+		if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("  %d codes ATC\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d codes ATC\n", w.Count())
+	return nil
 }
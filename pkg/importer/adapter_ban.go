@@ -30,15 +30,13 @@ func (a *banAdapter) DefaultURL() string {
 func (a *banAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *banAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	gzPath := filepath.Join(dlDir, "ban.csv.gz")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
-	fmt.Println("  (fichier tres volumineux ~1.5 Go, patience...)")
 	if err := downloadFile(ctx, sourceURL, gzPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -49,23 +47,19 @@ func (a *banAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 		return fmt.Errorf("gunzip: %w", err)
 	}
 
-	entries, err := parseBAN(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseBAN(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "address",
 		Source:       "BAN (Base Adresse Nationale)",
@@ -79,10 +73,10 @@ func (a *banAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 	})
 }
 
-func parseBAN(path string) (map[string]*dict.Entry, error) {
+func parseBAN(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -94,12 +88,12 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ','
@@ -108,7 +102,7 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (comma): %w", err)
+			return fmt.Errorf("read header (comma): %w", err)
 		}
 	}
 
@@ -123,13 +117,10 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 	communeCol := colByNames(colIdx, "nom_commune", "commune")
 	inseeCol := colByNames(colIdx, "code_insee", "code_commune")
 
-	// We index street names (voies) — the most useful for PII detection in addresses
-	// We deduplicate by (voie, cp) to avoid 25M entries
-	type voieKey struct{ voie, cp string }
-	seen := make(map[voieKey]bool, 500000)
-
-	entries := make(map[string]*dict.Entry, 1000000)
-	var count, total int
+	// We index street names (voies) — the most useful for PII detection in addresses.
+	// The 25M address lines collapse to unique voies: the writer keeps the
+	// first line of each key, on disk.
+	var total int
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -138,6 +129,7 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 		total++
 
 		voie := strings.TrimSpace(safeCol(record, voieCol))
@@ -148,13 +140,6 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 			continue
 		}
 
-		// Deduplicate: only keep unique (voie, cp) pairs
-		vk := voieKey{voie: strings.ToLower(voie), cp: cp}
-		if seen[vk] {
-			continue
-		}
-		seen[vk] = true
-
 		meta := map[string]string{
 			"voie":         voie,
 			"code_postal":  cp,
@@ -164,23 +149,24 @@ func parseBAN(path string) (map[string]*dict.Entry, error) {
 
 		// Index by normalized street name
 		key := dict.NormalizeLowercaseASCII(voie)
-		if _, exists := entries[key]; !exists {
-			entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
 		}
 
 		// Index by "voie, cp commune" (full address line)
 		if cp != "" && commune != "" {
 			fullAddr := fmt.Sprintf("%s %s %s", voie, cp, commune)
 			addrKey := dict.NormalizeLowercaseASCII(fullAddr)
-			entries[addrKey] = &dict.Entry{Metadata: meta}
+			if err := w.PutNew(addrKey, &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
-		count++
-		if count%200000 == 0 {
-			fmt.Printf("  %d voies BAN traitees (sur %d lignes)...\n", count, total)
+		if total%1000000 == 0 {
+			fmt.Printf("  %d lignes BAN traitees (%d entrees)...\n", total, w.Count())
 		}
 	}
 
-	fmt.Printf("  %d voies BAN uniques (de %d lignes)\n", count, total)
-	return entries, nil
+	fmt.Printf("  %d entrees BAN uniques (de %d lignes)\n", w.Count(), total)
+	return nil
 }
@@ -30,35 +30,30 @@ func (a *cogPaysAdapter) DefaultURL() string {
 func (a *cogPaysAdapter) License() string { return "CC0" }
 
 func (a *cogPaysAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "pays.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseCOGPays(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseCOGPays(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "country",
 		Source:       "INSEE COG pays",
@@ -71,10 +66,10 @@ func (a *cogPaysAdapter) Import(ctx context.Context, sourceURL, outputDir string
 
 // parseCOGPays reads the INSEE COG pays CSV.
 // Columns include: COG, ACTUAL, LIBCOG, LIBENR, CODEISO2, CODEISO3, CODENUM3.
-func parseCOGPays(path string) (map[string]*dict.Entry, error) {
+func parseCOGPays(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -85,7 +80,7 @@ func parseCOGPays(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -100,10 +95,9 @@ func parseCOGPays(path string) (map[string]*dict.Entry, error) {
 	actualCol := colByNames(colIdx, "ACTUAL")
 
 	if libelleCol < 0 {
-		return nil, fmt.Errorf("no name column found in header %v", header)
+		return fmt.Errorf("no name column found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -112,6 +106,7 @@ func parseCOGPays(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		// Skip historical entries (ACTUAL != 1).
 		if actual := safeCol(record, actualCol); actual != "" && actual != "1" {
@@ -130,14 +125,18 @@ func parseCOGPays(path string) (map[string]*dict.Entry, error) {
 		}
 
 		key := dict.NormalizeLowercaseASCII(name)
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by COG code.
 		if cog := meta["cog"]; cog != "" {
-			entries[strings.ToLower(cog)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(cog), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d pays COG\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d pays COG\n", w.Count())
+	return nil
 }
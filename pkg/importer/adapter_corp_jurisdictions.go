@@ -24,7 +24,7 @@ func (a *corpJurisdictionsAdapter) Description() string {
 func (a *corpJurisdictionsAdapter) DefaultURL() string { return "static://corp-jurisdictions" }
 func (a *corpJurisdictionsAdapter) License() string    { return "CC0" }
 
-func (a *corpJurisdictionsAdapter) Import(_ context.Context, sourceURL, outputDir string) error {
+func (a *corpJurisdictionsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
 	entries := buildCorpJurisdictions()
 
 	dictDir := filepath.Join(outputDir, a.DictID())
@@ -32,13 +32,14 @@ func (a *corpJurisdictionsAdapter) Import(_ context.Context, sourceURL, outputDi
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return putEntries(w, entries)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "jurisdiction",
 		Source:     "Corporate registries compilation",
 		SourceURL:  sourceURL,
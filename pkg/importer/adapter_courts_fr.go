@@ -23,7 +23,7 @@ func (a *courtsFRAdapter) Description() string {
 func (a *courtsFRAdapter) DefaultURL() string { return "static://courts-fr" }
 func (a *courtsFRAdapter) License() string    { return "CC0" }
 
-func (a *courtsFRAdapter) Import(_ context.Context, sourceURL, outputDir string) error {
+func (a *courtsFRAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
 	entries := buildCourtsFR()
 
 	dictDir := filepath.Join(outputDir, a.DictID())
@@ -31,13 +31,14 @@ func (a *courtsFRAdapter) Import(_ context.Context, sourceURL, outputDir string)
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return putEntries(w, entries)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "court",
 		Source:       "Ministere de la Justice",
@@ -30,35 +30,30 @@ func (a *ebaAdapter) DefaultURL() string {
 func (a *ebaAdapter) License() string { return "Public" }
 
 func (a *ebaAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "eba.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseEBA(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseEBA(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "eu",
 		EntityType:   "credit_institution",
 		Source:       "EBA Credit Institutions Register",
@@ -69,10 +64,10 @@ func (a *ebaAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 	})
 }
 
-func parseEBA(path string) (map[string]*dict.Entry, error) {
+func parseEBA(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,12 +78,12 @@ func parseEBA(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -96,7 +91,7 @@ func parseEBA(path string) (map[string]*dict.Entry, error) {
 		r.FieldsPerRecord = -1
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 
@@ -112,7 +107,6 @@ func parseEBA(path string) (map[string]*dict.Entry, error) {
 	countryCol := colByNames(colIdx, "country", "country_code", "country of establishment")
 	typeCol := colByNames(colIdx, "type", "entity_type", "institution type")
 
-	entries := make(map[string]*dict.Entry, 6000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -121,6 +115,7 @@ func parseEBA(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		name := strings.TrimSpace(safeCol(record, nameCol))
 		if name == "" {
@@ -134,12 +129,16 @@ func parseEBA(path string) (map[string]*dict.Entry, error) {
 			"type":    safeCol(record, typeCol),
 		}
 
-		entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if lei := meta["lei"]; lei != "" {
-			entries[strings.ToLower(lei)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(lei), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d etablissements de credit EU\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d etablissements de credit EU\n", w.Count())
+	return nil
 }
@@ -23,7 +23,7 @@ func (a *euInstitutionsAdapter) Description() string {
 func (a *euInstitutionsAdapter) DefaultURL() string { return "static://eu-institutions" }
 func (a *euInstitutionsAdapter) License() string    { return "CC0" }
 
-func (a *euInstitutionsAdapter) Import(_ context.Context, sourceURL, outputDir string) error {
+func (a *euInstitutionsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
 	entries := buildEUInstitutions()
 
 	dictDir := filepath.Join(outputDir, a.DictID())
@@ -31,13 +31,14 @@ func (a *euInstitutionsAdapter) Import(_ context.Context, sourceURL, outputDir s
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return putEntries(w, entries)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "eu",
 		EntityType:   "institution",
 		Source:       "EU institutions directory",
@@ -30,35 +30,30 @@ func (a *finessAdapter) DefaultURL() string {
 func (a *finessAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *finessAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "finess.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseFINESS(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseFINESS(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "health_establishment",
 		Source:       "FINESS (data.gouv.fr)",
@@ -69,10 +64,10 @@ func (a *finessAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 	})
 }
 
-func parseFINESS(path string) (map[string]*dict.Entry, error) {
+func parseFINESS(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -80,7 +75,8 @@ func parseFINESS(path string) (map[string]*dict.Entry, error) {
 	// No real header row — columns are positional:
 	// 0:type, 1:nofinesset, 2:nofinessej, 3:rs, 4:rslongue, 5-7:address fields...
 	// 18:categetab, 19:categetablib, ...
-	r := csv.NewReader(f)
+	// The file is published in Windows-1252.
+	r := csv.NewReader(windows1252(f))
 	r.Comma = ';'
 	r.LazyQuotes = true
 	r.FieldsPerRecord = -1
@@ -89,7 +85,7 @@ func parseFINESS(path string) (map[string]*dict.Entry, error) {
 	// Skip first line (metadata)
 	first, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read first line: %w", err)
+		return fmt.Errorf("read first line: %w", err)
 	}
 
 	// Check if it's actually a header with column names.
@@ -110,21 +106,21 @@ func parseFINESS(path string) (map[string]*dict.Entry, error) {
 		for i, h := range first {
 			colIdx[strings.TrimSpace(strings.ToLower(h))] = i
 		}
-		return parseFINESSWithHeader(r, colIdx)
+		return parseFINESSWithHeader(r, colIdx, w)
 	}
 
 	// Positional parsing (etalab format)
-	entries := make(map[string]*dict.Entry, 80000)
-	processLine := func(record []string) {
+	processLine := func(record []string) error {
+		rowRead(w)
 		if len(record) < 5 {
-			return
+			return nil
 		}
 		recType := strings.TrimSpace(record[0])
 		if recType != "structureet" && recType != "geolocalisation" {
-			return
+			return nil
 		}
 		if recType == "geolocalisation" {
-			return // Skip geolocalisation lines
+			return nil // Skip geolocalisation lines
 		}
 
 		finess := strings.TrimSpace(record[1])
@@ -161,15 +157,22 @@ func parseFINESS(path string) (map[string]*dict.Entry, error) {
 		}
 
 		if finess != "" {
-			entries[strings.ToLower(finess)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(finess), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if name != "" {
-			entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
 	// Process first data line (it's the first record we already read, if not header)
-	processLine(first)
+	if err := processLine(first); err != nil {
+		return err
+	}
 
 	for {
 		record, err := r.Read()
@@ -179,21 +182,22 @@ func parseFINESS(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
-		processLine(record)
+		if err := processLine(record); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("  %d etablissements FINESS\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d etablissements FINESS\n", w.Count())
+	return nil
 }
 
-func parseFINESSWithHeader(r *csv.Reader, colIdx map[string]int) (map[string]*dict.Entry, error) {
+func parseFINESSWithHeader(r *csv.Reader, colIdx map[string]int, w dict.EntryWriter) error {
 	finessCol := colByNames(colIdx, "nofinesset", "finess")
 	nameCol := colByNames(colIdx, "rs", "rslongue", "raison_sociale")
 	catCol := colByNames(colIdx, "categetab", "categorie")
 	deptCol := colByNames(colIdx, "departement", "dept")
 	communeCol := colByNames(colIdx, "commune", "libcommune", "communeet")
 
-	entries := make(map[string]*dict.Entry, 80000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -202,6 +206,7 @@ func parseFINESSWithHeader(r *csv.Reader, colIdx map[string]int) (map[string]*di
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		finess := strings.TrimSpace(safeCol(record, finessCol))
 		name := strings.TrimSpace(safeCol(record, nameCol))
@@ -215,13 +220,17 @@ func parseFINESSWithHeader(r *csv.Reader, colIdx map[string]int) (map[string]*di
 		}
 
 		if finess != "" {
-			entries[strings.ToLower(finess)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(finess), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if name != "" {
-			entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d etablissements FINESS\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d etablissements FINESS\n", w.Count())
+	return nil
 }
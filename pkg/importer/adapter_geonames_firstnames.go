@@ -29,35 +29,30 @@ func (a *geonamesFirstnamesAdapter) DefaultURL() string {
 func (a *geonamesFirstnamesAdapter) License() string { return "CC0" }
 
 func (a *geonamesFirstnamesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	path := filepath.Join(dlDir, "names.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, path); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseGeonamesFirstnames(path)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseGeonamesFirstnames(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "firstname",
 		Source:     "Global first names dataset",
 		SourceURL:  sourceURL,
@@ -67,14 +62,13 @@ func (a *geonamesFirstnamesAdapter) Import(ctx context.Context, sourceURL, outpu
 	})
 }
 
-func parseGeonamesFirstnames(path string) (map[string]*dict.Entry, error) {
+func parseGeonamesFirstnames(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
-	entries := make(map[string]*dict.Entry, 300000)
 	scanner := bufio.NewScanner(f)
 	scanner.Buffer(make([]byte, 1<<20), 1<<20)
 	first := true
@@ -88,6 +82,7 @@ func parseGeonamesFirstnames(path string) (map[string]*dict.Entry, error) {
 				continue
 			}
 		}
+		rowRead(w)
 
 		// Handle CSV or simple one-name-per-line
 		fields := strings.Split(line, ",")
@@ -116,11 +111,11 @@ func parseGeonamesFirstnames(path string) (map[string]*dict.Entry, error) {
 		}
 
 		key := dict.NormalizeLowercaseASCII(name)
-		if _, exists := entries[key]; !exists {
-			entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
 		}
 	}
 
-	fmt.Printf("  %d prenoms internationaux\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d prenoms internationaux\n", w.Count())
+	return nil
 }
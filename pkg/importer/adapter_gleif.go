@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Import adapter for GLEIF LEI (Legal Entity Identifiers) golden copy CSV from gleif.org.
+// CLAUDE:SUMMARY Import adapter for GLEIF LEI (Legal Entity Identifiers) golden copy CSV from gleif.org; golden copy delta files applied incrementally.
 package importer
 
 import (
@@ -20,7 +20,11 @@ func init() {
 	Register(&gleifAdapter{})
 }
 
-type gleifAdapter struct{}
+type gleifAdapter struct {
+	deltaURL string // golden copy API URL of the latest publish; "" for the default
+}
+
+var _ IncrementalAdapter = (*gleifAdapter)(nil)
 
 func (a *gleifAdapter) ID() string      { return "gleif-lei" }
 func (a *gleifAdapter) DictID() string  { return "lei" }
@@ -33,7 +37,7 @@ func (a *gleifAdapter) DefaultURL() string {
 func (a *gleifAdapter) License() string { return "CC0" }
 
 func (a *gleifAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
@@ -46,8 +50,6 @@ func (a *gleifAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 	}
 
 	zipPath := filepath.Join(dlDir, "lei.zip")
-	fmt.Printf("  telechargement %s...\n", actualURL)
-	fmt.Println("  (fichier volumineux ~450 Mo, patience...)")
 	if dlErr := downloadFile(ctx, actualURL, zipPath); dlErr != nil {
 		return fmt.Errorf("download: %w", dlErr)
 	}
@@ -68,23 +70,19 @@ func (a *gleifAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 		return fmt.Errorf("no CSV found in GLEIF ZIP")
 	}
 
-	entries, err := parseGLEIF(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseGLEIF(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "legal_entity",
 		Source:     "GLEIF Golden Copy",
 		SourceURL:  sourceURL,
@@ -99,54 +97,161 @@ func (a *gleifAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 	})
 }
 
-// resolveGLEIFURL queries the GLEIF API to get the actual CSV ZIP download URL.
-func resolveGLEIFURL(ctx context.Context, apiURL string) (string, error) {
+// gleifPublish is a golden copy publish as described by the GLEIF API.
+type gleifPublish struct {
+	PublishDate string    `json:"publish_date"` // "2006-01-02 15:04:05" UTC
+	FullFile    gleifFile `json:"full_file"`
+	DeltaFiles  struct {
+		LastDay   gleifFile `json:"LastDay"`
+		LastWeek  gleifFile `json:"LastWeek"`
+		LastMonth gleifFile `json:"LastMonth"`
+	} `json:"delta_files"`
+}
+
+type gleifFile struct {
+	CSV struct {
+		URL string `json:"url"`
+	} `json:"csv"`
+}
+
+// fetchGLEIFPublish queries the GLEIF API for the golden copy publish at apiURL.
+func fetchGLEIFPublish(ctx context.Context, apiURL string) (*gleifPublish, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "touchstone-registry/1.0 (open-data-import)")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GLEIF API HTTP %d", resp.StatusCode)
+		return nil, fmt.Errorf("GLEIF API HTTP %d", resp.StatusCode)
 	}
 
 	var result struct {
-		Data struct {
-			FullFile struct {
-				CSV struct {
-					URL string `json:"url"`
-				} `json:"csv"`
-			} `json:"full_file"`
-		} `json:"data"`
+		Data gleifPublish `json:"data"`
 	}
-
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode GLEIF response: %w", err)
+		return nil, fmt.Errorf("decode GLEIF response: %w", err)
 	}
+	return &result.Data, nil
+}
 
-	url := result.Data.FullFile.CSV.URL
+// resolveGLEIFURL queries the GLEIF API to get the actual CSV ZIP download URL.
+func resolveGLEIFURL(ctx context.Context, apiURL string) (string, error) {
+	p, err := fetchGLEIFPublish(ctx, apiURL)
+	if err != nil {
+		return "", err
+	}
+	url := p.FullFile.CSV.URL
 	if url == "" {
 		return "", fmt.Errorf("no CSV URL in GLEIF API response")
 	}
-
 	return url, nil
 }
 
-// parseGLEIF reads the GLEIF LEI CSV.
+// ImportDelta applies the smallest golden copy delta (last day, week or
+// month) covering the changes since: changed entities are stored, the ones
+// no longer active removed. A renamed entity keeps its former name as a key
+// until the next full import.
+func (a *gleifAdapter) ImportDelta(ctx context.Context, since time.Time, w DeltaWriter) error {
+	apiURL := a.deltaURL
+	if apiURL == "" {
+		apiURL = "https://goldencopy.gleif.org/api/v2/golden-copies/publishes/lei2/latest"
+	}
+	p, err := fetchGLEIFPublish(ctx, apiURL)
+	if err != nil {
+		return fmt.Errorf("resolve GLEIF deltas: %w", err)
+	}
+	published, err := time.Parse(time.DateTime, p.PublishDate)
+	if err != nil {
+		if published, err = time.Parse(time.RFC3339, p.PublishDate); err != nil {
+			return fmt.Errorf("%w: publish date %q", ErrDeltaChainBroken, p.PublishDate)
+		}
+	}
+	if !published.After(since) {
+		return fmt.Errorf("%w: no publish since %s", ErrNotModified, since.Format(time.RFC3339))
+	}
+
+	var url string
+	switch age := published.Sub(since); {
+	case age <= 24*time.Hour:
+		url = p.DeltaFiles.LastDay.CSV.URL
+	case age <= 7*24*time.Hour:
+		url = p.DeltaFiles.LastWeek.CSV.URL
+	case age <= 30*24*time.Hour:
+		url = p.DeltaFiles.LastMonth.CSV.URL
+	}
+	if url == "" {
+		return fmt.Errorf("%w: no GLEIF delta covers %s", ErrDeltaChainBroken, since.Format(time.RFC3339))
+	}
+
+	files := []deltaFile{{Day: published.UTC().Truncate(24 * time.Hour), URL: url}}
+	return applyDeltas(ctx, a, files, func(csvPath string) error {
+		return readGLEIF(csvPath, func(lei, key string, meta map[string]string, active bool) error {
+			rowRead(w)
+			if !active {
+				if err := w.Delete(strings.ToLower(lei), nil); err != nil {
+					return err
+				}
+				return w.Delete(key, ownedBy("lei", lei))
+			}
+			if err := w.Put(strings.ToLower(lei), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
+			return w.Put(key, &dict.Entry{Metadata: meta})
+		})
+	})
+}
+
+// parseGLEIF reads the GLEIF LEI CSV, keeping active entities only.
+func parseGLEIF(path string, w dict.EntryWriter) error {
+	var count, skipped int
+	err := readGLEIF(path, func(lei, key string, meta map[string]string, active bool) error {
+		rowRead(w)
+		if !active {
+			skipped++
+			return nil
+		}
+
+		// Index by LEI code.
+		if err := w.Put(strings.ToLower(lei), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
+
+		// Also index by company name.
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
+
+		count++
+		if count%500000 == 0 {
+			fmt.Printf("  %d entites GLEIF traitees...\n", count)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  %d entites LEI actives (%d inactives ignorees)\n", count, skipped)
+	return nil
+}
+
+// readGLEIF streams the entities of a GLEIF CSV, golden copy or delta, to
+// fn: the LEI, the normalized legal name, the metadata and whether the
+// entity is active.
 // Key columns: LEI, Entity.LegalName, Entity.LegalJurisdiction, Entity.EntityStatus.
-func parseGLEIF(path string) (map[string]*dict.Entry, error) {
+func readGLEIF(path string, fn func(lei, key string, meta map[string]string, active bool) error) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -157,7 +262,7 @@ func parseGLEIF(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -173,11 +278,9 @@ func parseGLEIF(path string) (map[string]*dict.Entry, error) {
 	categoryCol := colByNames(colIdx, "Entity.EntityCategory")
 
 	if leiCol < 0 || nameCol < 0 {
-		return nil, fmt.Errorf("required columns (LEI, Entity.LegalName) not found in header")
+		return fmt.Errorf("required columns (LEI, Entity.LegalName) not found in header")
 	}
 
-	entries := make(map[string]*dict.Entry, 500000)
-	var count, skipped int
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -187,13 +290,6 @@ func parseGLEIF(path string) (map[string]*dict.Entry, error) {
 			continue
 		}
 
-		// Only active entities.
-		status := safeCol(record, statusCol)
-		if status != "" && status != "ACTIVE" {
-			skipped++
-			continue
-		}
-
 		lei := safeCol(record, leiCol)
 		name := safeCol(record, nameCol)
 		if lei == "" || name == "" {
@@ -209,19 +305,10 @@ func parseGLEIF(path string) (map[string]*dict.Entry, error) {
 			meta["category"] = cat
 		}
 
-		// Index by LEI code.
-		entries[strings.ToLower(lei)] = &dict.Entry{Metadata: meta}
-
-		// Also index by company name.
-		key := dict.NormalizeLowercaseASCII(name)
-		entries[key] = &dict.Entry{Metadata: meta}
-
-		count++
-		if count%500000 == 0 {
-			fmt.Printf("  %d entites GLEIF traitees...\n", count)
+		status := safeCol(record, statusCol)
+		if err := fn(lei, dict.NormalizeLowercaseASCII(name), meta, status == "" || status == "ACTIVE"); err != nil {
+			return err
 		}
 	}
-
-	fmt.Printf("  %d entites LEI actives (%d inactives ignorees)\n", count, skipped)
-	return entries, nil
+	return nil
 }
@@ -24,7 +24,7 @@ func (a *honorificsAdapter) Description() string {
 func (a *honorificsAdapter) DefaultURL() string { return "static://honorifics" }
 func (a *honorificsAdapter) License() string    { return "CC0" }
 
-func (a *honorificsAdapter) Import(_ context.Context, sourceURL, outputDir string) error {
+func (a *honorificsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
 	entries := buildHonorifics()
 
 	dictDir := filepath.Join(outputDir, a.DictID())
@@ -32,13 +32,14 @@ func (a *honorificsAdapter) Import(_ context.Context, sourceURL, outputDir strin
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return putEntries(w, entries)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "honorific",
 		Source:     "Static table (multilingual honorifics)",
 		SourceURL:  sourceURL,
@@ -27,35 +27,30 @@ func (a *ianaTLDAdapter) DefaultURL() string {
 func (a *ianaTLDAdapter) License() string { return "Public Domain" }
 
 func (a *ianaTLDAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	path := filepath.Join(dlDir, "tlds.txt")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, path); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseTLDs(path)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseTLDs(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "tld",
 		Source:     "IANA",
 		SourceURL:  sourceURL,
@@ -65,26 +60,30 @@ func (a *ianaTLDAdapter) Import(ctx context.Context, sourceURL, outputDir string
 	})
 }
 
-func parseTLDs(path string) (map[string]*dict.Entry, error) {
+func parseTLDs(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
-	entries := make(map[string]*dict.Entry)
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		rowRead(w)
 		tld := strings.ToLower(line)
-		entries[tld] = &dict.Entry{Metadata: map[string]string{"tld": tld}}
+		if err := w.Put(tld, &dict.Entry{Metadata: map[string]string{"tld": tld}}); err != nil {
+			return err
+		}
 		// Also index with dot prefix
-		entries["."+tld] = &dict.Entry{Metadata: map[string]string{"tld": tld}}
+		if err := w.Put("."+tld, &dict.Entry{Metadata: map[string]string{"tld": tld}}); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("  %d TLDs\n", len(entries)/2)
-	return entries, nil
+	fmt.Printf("  %d TLDs\n", w.Count()/2)
+	return nil
 }
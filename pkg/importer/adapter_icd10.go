@@ -30,35 +30,30 @@ func (a *icd10Adapter) DefaultURL() string {
 func (a *icd10Adapter) License() string { return "OMS / Public" }
 
 func (a *icd10Adapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	path := filepath.Join(dlDir, "icd10.txt")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, path); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseICD10(path)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseICD10(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "medical_code",
 		Source:       "ATIH CIM-10",
@@ -66,13 +61,14 @@ func (a *icd10Adapter) Import(ctx context.Context, sourceURL, outputDir string)
 		License:      "OMS / Public",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		ParentColumn: "chapter",
 	})
 }
 
-func parseICD10(path string) (map[string]*dict.Entry, error) {
+func parseICD10(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -85,13 +81,13 @@ func parseICD10(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	// If semicolon doesn't work, try tab
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = '\t'
@@ -100,7 +96,7 @@ func parseICD10(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (tab): %w", err)
+			return fmt.Errorf("read header (tab): %w", err)
 		}
 	}
 
@@ -116,7 +112,6 @@ func parseICD10(path string) (map[string]*dict.Entry, error) {
 	chapCol := colByNames(colIdx, "chapitre_libelle", "chapter")
 	familyCol := colByNames(colIdx, "famille_code", "family")
 
-	entries := make(map[string]*dict.Entry, 20000)
 	seen := make(map[string]bool, 20000)
 	var count int
 	for {
@@ -127,6 +122,7 @@ func parseICD10(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := strings.TrimSpace(safeCol(record, codeCol))
 		label := strings.TrimSpace(safeCol(record, labelCol))
@@ -147,15 +143,21 @@ func parseICD10(path string) (map[string]*dict.Entry, error) {
 			"family":  safeCol(record, familyCol),
 		}
 
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
-		entries[strings.ToLower(strings.ReplaceAll(code, ".", ""))] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
+		if err := w.Put(strings.ToLower(strings.ReplaceAll(code, ".", "")), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if label != "" {
-			entries[dict.NormalizeLowercaseASCII(label)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(label), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		count++
 	}
 
 	fmt.Printf("  %d codes CIM-10\n", count)
-	return entries, nil
+	return nil
 }
@@ -26,35 +26,30 @@ func (a *inseeCommunesAdapter) DefaultURL() string   { return "https://www.insee
 func (a *inseeCommunesAdapter) License() string      { return "CC0" }
 
 func (a *inseeCommunesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "communes.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseINSEECommunes(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseINSEECommunes(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-02",
 		Jurisdiction: "fr",
 		EntityType:   "city",
 		Source:       "INSEE COG",
@@ -62,15 +57,18 @@ func (a *inseeCommunesAdapter) Import(ctx context.Context, sourceURL, outputDir
 		License:      "CC0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		ParentDict:   "departements-fr",
+		ParentColumn: "departement",
+		Surrogates:   true,
 	})
 }
 
 // parseINSEECommunes reads the INSEE COG CSV (comma-delimited).
 // Columns include: COM, TYPECOM, LIBELLE, DEP.
-func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
+func parseINSEECommunes(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -81,7 +79,7 @@ func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -89,17 +87,15 @@ func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
 		colIdx[strings.TrimSpace(strings.ToUpper(h))] = i
 	}
 
-	libelleCol := -1
+	// LIBELLE carries the article ("L'Abergement-Clémenciat"), NCC and
+	// NCCENR do not.
+	libelleCol := colByNames(colIdx, "LIBELLE", "NCCENR", "NCC")
 	depCol := -1
 	comCol := -1
 	typecomCol := -1
 
 	for k, v := range colIdx {
 		switch {
-		case k == "LIBELLE" || k == "NCC" || k == "NCCENR":
-			if libelleCol < 0 {
-				libelleCol = v
-			}
 		case k == "DEP":
 			depCol = v
 		case k == "COM":
@@ -109,10 +105,9 @@ func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
 		}
 	}
 	if libelleCol < 0 {
-		return nil, fmt.Errorf("no name column found in header %v", header)
+		return fmt.Errorf("no name column found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -121,6 +116,7 @@ func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		// Only keep actual communes (TYPECOM = COM).
 		if typecomCol >= 0 && typecomCol < len(record) {
@@ -146,9 +142,11 @@ func parseINSEECommunes(path string) (map[string]*dict.Entry, error) {
 			meta["code_commune"] = strings.TrimSpace(record[comCol])
 		}
 
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("  %d communes\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d communes\n", w.Count())
+	return nil
 }
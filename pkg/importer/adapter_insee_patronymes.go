@@ -26,14 +26,13 @@ func (a *inseePatronymesAdapter) DefaultURL() string   { return "https://www.ins
 func (a *inseePatronymesAdapter) License() string      { return "CC0" }
 
 func (a *inseePatronymesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "patronymes.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -55,23 +54,19 @@ func (a *inseePatronymesAdapter) Import(ctx context.Context, sourceURL, outputDi
 		return fmt.Errorf("no data file found in ZIP")
 	}
 
-	entries, err := parseINSEEPatronymes(dataPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseINSEEPatronymes(dataPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-02",
 		Jurisdiction: "fr",
 		EntityType:   "surname",
 		Source:       "INSEE fichier des noms de famille",
@@ -79,15 +74,16 @@ func (a *inseePatronymesAdapter) Import(ctx context.Context, sourceURL, outputDi
 		License:      "CC0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		Surrogates:   true,
 	})
 }
 
 // parseINSEEPatronymes reads the INSEE patronymes file (tab-delimited).
 // Columns: NOM;NOMBRE (or tab-separated). Aggregates by normalized name.
-func parseINSEEPatronymes(path string) (map[string]*dict.Entry, error) {
+func parseINSEEPatronymes(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -99,7 +95,7 @@ func parseINSEEPatronymes(path string) (map[string]*dict.Entry, error) {
 	// Read header.
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -123,11 +119,6 @@ func parseINSEEPatronymes(path string) (map[string]*dict.Entry, error) {
 		nameCol = 0
 	}
 
-	type agg struct {
-		total int
-	}
-	aggregated := make(map[string]*agg)
-
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -136,6 +127,7 @@ func parseINSEEPatronymes(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 		if nameCol >= len(record) {
 			continue
 		}
@@ -151,22 +143,15 @@ func parseINSEEPatronymes(path string) (map[string]*dict.Entry, error) {
 			_, _ = fmt.Sscanf(strings.TrimSpace(record[nombreCol]), "%d", &nombre)
 		}
 
-		if existing, ok := aggregated[key]; ok {
-			existing.total += nombre
-		} else {
-			aggregated[key] = &agg{total: nombre}
-		}
-	}
-
-	entries := make(map[string]*dict.Entry, len(aggregated))
-	for key, a := range aggregated {
 		meta := make(map[string]string)
-		if a.total > 0 {
-			meta["frequency"] = fmt.Sprintf("%d", a.total)
+		if nombre > 0 {
+			meta["frequency"] = fmt.Sprintf("%d", nombre)
+		}
+		if err := w.Merge(key, &dict.Entry{Metadata: meta}, sumFrequency); err != nil {
+			return err
 		}
-		entries[key] = &dict.Entry{Metadata: meta}
 	}
 
-	fmt.Printf("  %d patronymes uniques\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d patronymes uniques\n", w.Count())
+	return nil
 }
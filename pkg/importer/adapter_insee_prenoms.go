@@ -26,14 +26,13 @@ func (a *inseePrenomsAdapter) DefaultURL() string   { return "https://www.insee.
 func (a *inseePrenomsAdapter) License() string      { return "CC0" }
 
 func (a *inseePrenomsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "prenoms.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -55,23 +54,19 @@ func (a *inseePrenomsAdapter) Import(ctx context.Context, sourceURL, outputDir s
 		return fmt.Errorf("no CSV found in ZIP")
 	}
 
-	entries, err := parseINSEEPrenoms(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseINSEEPrenoms(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-02",
 		Jurisdiction: "fr",
 		EntityType:   "first_name",
 		Source:       "INSEE fichier des prenoms",
@@ -79,15 +74,16 @@ func (a *inseePrenomsAdapter) Import(ctx context.Context, sourceURL, outputDir s
 		License:      "CC0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		Surrogates:   true,
 	})
 }
 
 // parseINSEEPrenoms reads the INSEE prenoms CSV (semicolon-delimited) and aggregates
 // by first name, summing frequencies across years. Columns: sexe;preusuel;annais;nombre
-func parseINSEEPrenoms(path string) (map[string]*dict.Entry, error) {
+func parseINSEEPrenoms(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -98,7 +94,7 @@ func parseINSEEPrenoms(path string) (map[string]*dict.Entry, error) {
 	// Read header.
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -110,15 +106,9 @@ func parseINSEEPrenoms(path string) (map[string]*dict.Entry, error) {
 	sexeCol, hasSexe := colIdx["sexe"]
 	nombreCol, hasNombre := colIdx["nombre"]
 	if !hasName {
-		return nil, fmt.Errorf("column 'preusuel' not found in header %v", header)
+		return fmt.Errorf("column 'preusuel' not found in header %v", header)
 	}
 
-	type agg struct {
-		sexe  string
-		total int
-	}
-	aggregated := make(map[string]*agg)
-
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -127,6 +117,7 @@ func parseINSEEPrenoms(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		name := strings.TrimSpace(record[nameCol])
 		if name == "" || strings.EqualFold(name, "_prenoms_rares") {
@@ -144,28 +135,18 @@ func parseINSEEPrenoms(path string) (map[string]*dict.Entry, error) {
 			_, _ = fmt.Sscanf(strings.TrimSpace(record[nombreCol]), "%d", &nombre)
 		}
 
-		if existing, ok := aggregated[key]; ok {
-			existing.total += nombre
-			if sexe != "" && existing.sexe == "" {
-				existing.sexe = sexe
-			}
-		} else {
-			aggregated[key] = &agg{sexe: sexe, total: nombre}
-		}
-	}
-
-	entries := make(map[string]*dict.Entry, len(aggregated))
-	for key, a := range aggregated {
 		meta := make(map[string]string)
-		if a.sexe != "" {
-			meta["sexe"] = a.sexe
+		if sexe != "" {
+			meta["sexe"] = sexe
+		}
+		if nombre > 0 {
+			meta["frequency"] = fmt.Sprintf("%d", nombre)
 		}
-		if a.total > 0 {
-			meta["frequency"] = fmt.Sprintf("%d", a.total)
+		if err := w.Merge(key, &dict.Entry{Metadata: meta}, sumFrequency); err != nil {
+			return err
 		}
-		entries[key] = &dict.Entry{Metadata: meta}
 	}
 
-	fmt.Printf("  %d prenoms uniques\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d prenoms uniques\n", w.Count())
+	return nil
 }
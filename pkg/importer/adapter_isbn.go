@@ -24,7 +24,7 @@ func (a *isbnAdapter) Description() string {
 func (a *isbnAdapter) DefaultURL() string { return "static://isbn-groups" }
 func (a *isbnAdapter) License() string    { return "CC0" }
 
-func (a *isbnAdapter) Import(_ context.Context, sourceURL, outputDir string) error {
+func (a *isbnAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
 	entries := buildISBNGroups()
 
 	dictDir := filepath.Join(outputDir, a.DictID())
@@ -32,13 +32,14 @@ func (a *isbnAdapter) Import(_ context.Context, sourceURL, outputDir string) err
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return putEntries(w, entries)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "isbn_group",
 		Source:     "ISBN International Agency",
 		SourceURL:  sourceURL,
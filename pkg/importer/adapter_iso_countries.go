@@ -30,35 +30,30 @@ func (a *isoCountriesAdapter) DefaultURL() string {
 func (a *isoCountriesAdapter) License() string { return "CC0" }
 
 func (a *isoCountriesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "countries.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseISOCountries(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseISOCountries(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "country",
 		Source:     "ISO 3166-1",
 		SourceURL:  sourceURL,
@@ -70,10 +65,10 @@ func (a *isoCountriesAdapter) Import(ctx context.Context, sourceURL, outputDir s
 
 // parseISOCountries reads the ISO 3166 CSV.
 // Columns: name, alpha-2, alpha-3, country-code, iso_3166-2, region, sub-region, ...
-func parseISOCountries(path string) (map[string]*dict.Entry, error) {
+func parseISOCountries(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -82,7 +77,7 @@ func parseISOCountries(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -97,10 +92,9 @@ func parseISOCountries(path string) (map[string]*dict.Entry, error) {
 	regionCol := colIdx["region"]
 	subRegionCol := colIdx["sub-region"]
 	if !hasName {
-		return nil, fmt.Errorf("column 'name' not found in header %v", header)
+		return fmt.Errorf("column 'name' not found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -109,6 +103,7 @@ func parseISOCountries(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		name := strings.TrimSpace(record[nameCol])
 		if name == "" {
@@ -133,17 +128,23 @@ func parseISOCountries(path string) (map[string]*dict.Entry, error) {
 		}
 
 		key := dict.NormalizeLowercaseASCII(name)
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by alpha-2 and alpha-3 codes.
 		if a2 := meta["alpha2"]; a2 != "" {
-			entries[strings.ToLower(a2)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(a2), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if a3 := meta["alpha3"]; a3 != "" {
-			entries[strings.ToLower(a3)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(a3), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d entries pays ISO 3166\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d entries pays ISO 3166\n", w.Count())
+	return nil
 }
@@ -30,35 +30,30 @@ func (a *isoCurrenciesAdapter) DefaultURL() string {
 func (a *isoCurrenciesAdapter) License() string { return "PDDL" }
 
 func (a *isoCurrenciesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "currencies.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseISOCurrencies(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseISOCurrencies(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "currency",
 		Source:     "ISO 4217",
 		SourceURL:  sourceURL,
@@ -70,10 +65,10 @@ func (a *isoCurrenciesAdapter) Import(ctx context.Context, sourceURL, outputDir
 
 // parseISOCurrencies reads the ISO 4217 CSV.
 // Columns: Entity, Currency, AlphabeticCode, NumericCode, MinorUnit, WithdrawalDate
-func parseISOCurrencies(path string) (map[string]*dict.Entry, error) {
+func parseISOCurrencies(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -82,7 +77,7 @@ func parseISOCurrencies(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -95,7 +90,6 @@ func parseISOCurrencies(path string) (map[string]*dict.Entry, error) {
 	codeCol := colByNames(colIdx, "AlphabeticCode")
 	numericCol := colByNames(colIdx, "NumericCode")
 
-	entries := make(map[string]*dict.Entry)
 	seen := make(map[string]bool)
 	for {
 		record, err := r.Read()
@@ -105,6 +99,7 @@ func parseISOCurrencies(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := safeCol(record, codeCol)
 		currency := safeCol(record, currencyCol)
@@ -121,17 +116,19 @@ func parseISOCurrencies(path string) (map[string]*dict.Entry, error) {
 		}
 
 		// Index by code (e.g., "eur") and by currency name.
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if currency != "" {
 			key := dict.NormalizeLowercaseASCII(currency)
-			if _, exists := entries[key]; !exists {
-				entries[key] = &dict.Entry{Metadata: meta}
+			if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+				return err
 			}
 		}
 	}
 
-	fmt.Printf("  %d devises ISO 4217\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d devises ISO 4217\n", w.Count())
+	return nil
 }
 
 func colByNames(idx map[string]int, names ...string) int {
@@ -30,35 +30,30 @@ func (a *lauEUAdapter) DefaultURL() string {
 func (a *lauEUAdapter) License() string { return "CC BY 4.0" }
 
 func (a *lauEUAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "lau.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseLAU(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseLAU(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "eu",
 		EntityType:   "municipality",
 		Source:       "Eurostat LAU",
@@ -66,13 +61,15 @@ func (a *lauEUAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 		License:      "CC BY 4.0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		ParentDict:   "nuts-eu",
+		ParentColumn: "nuts3",
 	})
 }
 
-func parseLAU(path string) (map[string]*dict.Entry, error) {
+func parseLAU(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -84,12 +81,12 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ','
@@ -98,7 +95,7 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (comma): %w", err)
+			return fmt.Errorf("read header (comma): %w", err)
 		}
 	}
 
@@ -116,7 +113,6 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 	popCol := colByNames(colIdx, "POPULATION", "POP_2021", "POP_2024", "TOTAL_POP")
 	giscoCol := colByNames(colIdx, "GISCO_ID")
 
-	entries := make(map[string]*dict.Entry, 120000)
 	var count int
 	for {
 		record, err := r.Read()
@@ -126,6 +122,7 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		lau := strings.TrimSpace(safeCol(record, lauCol))
 		name := strings.TrimSpace(safeCol(record, nameCol))
@@ -155,10 +152,14 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 		}
 
 		if lau != "" {
-			entries[strings.ToLower(lau)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(lau), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if name != "" {
-			entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		count++
@@ -168,5 +169,5 @@ func parseLAU(path string) (map[string]*dict.Entry, error) {
 	}
 
 	fmt.Printf("  %d communes LAU EU\n", count)
-	return entries, nil
+	return nil
 }
@@ -30,35 +30,30 @@ func (a *legalFormsFRAdapter) DefaultURL() string {
 func (a *legalFormsFRAdapter) License() string { return "CC0" }
 
 func (a *legalFormsFRAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "cj.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseLegalFormsFR(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseLegalFormsFR(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "legal_form",
 		Source:       "INSEE categories juridiques",
@@ -69,10 +64,10 @@ func (a *legalFormsFRAdapter) Import(ctx context.Context, sourceURL, outputDir s
 	})
 }
 
-func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
+func parseLegalFormsFR(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,13 +78,13 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	// Try comma if tab gives single column
 	if len(header) <= 1 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ','
@@ -97,13 +92,13 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 		r.FieldsPerRecord = -1
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (comma): %w", err)
+			return fmt.Errorf("read header (comma): %w", err)
 		}
 	}
 	// Try semicolon
 	if len(header) <= 1 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -111,7 +106,7 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 		r.FieldsPerRecord = -1
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 
@@ -125,7 +120,6 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 	codeCol := colByNames(colIdx, "code", "cj")
 	labelCol := colByNames(colIdx, "libelle", "libellé", "label")
 
-	entries := make(map[string]*dict.Entry, 200)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -134,6 +128,7 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := strings.TrimSpace(safeCol(record, codeCol))
 		label := strings.TrimSpace(safeCol(record, labelCol))
@@ -147,11 +142,15 @@ func parseLegalFormsFR(path string) (map[string]*dict.Entry, error) {
 		}
 
 		if code != "" {
-			entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
+		}
+		if err := w.Put(dict.NormalizeLowercaseASCII(label), &dict.Entry{Metadata: meta}); err != nil {
+			return err
 		}
-		entries[dict.NormalizeLowercaseASCII(label)] = &dict.Entry{Metadata: meta}
 	}
 
-	fmt.Printf("  %d formes juridiques\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d formes juridiques\n", w.Count())
+	return nil
 }
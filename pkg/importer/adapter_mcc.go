@@ -30,35 +30,30 @@ func (a *mccAdapter) DefaultURL() string {
 func (a *mccAdapter) License() string { return "Public Domain" }
 
 func (a *mccAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "mcc.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseMCC(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseMCC(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "merchant_category",
 		Source:     "MCC Codes (ISO 18245)",
 		SourceURL:  sourceURL,
@@ -70,10 +65,10 @@ func (a *mccAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 
 // parseMCC reads the MCC codes CSV.
 // Columns: mcc, edited_description, combined_description, usda_description, irs_description, irs_reportable, id
-func parseMCC(path string) (map[string]*dict.Entry, error) {
+func parseMCC(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,7 +78,7 @@ func parseMCC(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -96,7 +91,6 @@ func parseMCC(path string) (map[string]*dict.Entry, error) {
 	irsDescCol := colByNames(colIdx, "irs_description")
 	reportableCol := colByNames(colIdx, "irs_reportable")
 
-	entries := make(map[string]*dict.Entry)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -105,6 +99,7 @@ func parseMCC(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := safeCol(record, codeCol)
 		desc := safeCol(record, descCol)
@@ -124,17 +119,19 @@ func parseMCC(path string) (map[string]*dict.Entry, error) {
 		}
 
 		// Index by code.
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by description.
 		if desc != "" {
 			key := dict.NormalizeLowercaseASCII(desc)
-			if _, exists := entries[key]; !exists {
-				entries[key] = &dict.Entry{Metadata: meta}
+			if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+				return err
 			}
 		}
 	}
 
-	fmt.Printf("  %d codes MCC\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d codes MCC\n", w.Count())
+	return nil
 }
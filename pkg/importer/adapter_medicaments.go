@@ -30,35 +30,30 @@ func (a *medicamentsAdapter) DefaultURL() string {
 func (a *medicamentsAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *medicamentsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	path := filepath.Join(dlDir, "medicaments.txt")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, path); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseMedicaments(path)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseMedicaments(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "medication",
 		Source:       "ANSM base publique medicaments",
@@ -69,22 +64,21 @@ func (a *medicamentsAdapter) Import(ctx context.Context, sourceURL, outputDir st
 	})
 }
 
-func parseMedicaments(path string) (map[string]*dict.Entry, error) {
+func parseMedicaments(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
-	// ANSM format: tab-separated, no header line
+	// ANSM format: tab-separated, no header line, Windows-1252
 	// Columns: CIS, denomination, forme, voie, statut_AMM, type_procedure, etat, date_AMM, statut_bdm, numero_autorisation, titulaire, surveillance_renforcee
-	r := csv.NewReader(f)
+	r := csv.NewReader(windows1252(f))
 	r.Comma = '\t'
 	r.LazyQuotes = true
 	r.FieldsPerRecord = -1
 	r.ReuseRecord = true
 
-	entries := make(map[string]*dict.Entry, 20000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -93,6 +87,7 @@ func parseMedicaments(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		if len(record) < 2 {
 			continue
@@ -121,20 +116,26 @@ func parseMedicaments(path string) (map[string]*dict.Entry, error) {
 
 		// Index by CIS code
 		if cis != "" {
-			entries[strings.ToLower(cis)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(cis), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		// Index by denomination (extract first word = active ingredient usually)
 		key := dict.NormalizeLowercaseASCII(denomination)
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by short name (before first comma)
 		if idx := strings.Index(denomination, ","); idx > 0 {
 			shortName := strings.TrimSpace(denomination[:idx])
-			entries[dict.NormalizeLowercaseASCII(shortName)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(shortName), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d medicaments\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d medicaments\n", w.Count())
+	return nil
 }
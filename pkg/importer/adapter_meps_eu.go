@@ -30,35 +30,30 @@ func (a *mepsEUAdapter) DefaultURL() string {
 func (a *mepsEUAdapter) License() string { return "CC BY 4.0" }
 
 func (a *mepsEUAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "meps.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseMEPs(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseMEPs(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "eu",
 		EntityType:   "politician",
 		Source:       "European Parliament",
@@ -69,10 +64,10 @@ func (a *mepsEUAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 	})
 }
 
-func parseMEPs(path string) (map[string]*dict.Entry, error) {
+func parseMEPs(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,12 +78,12 @@ func parseMEPs(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -96,7 +91,7 @@ func parseMEPs(path string) (map[string]*dict.Entry, error) {
 		r.FieldsPerRecord = -1
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 
@@ -113,7 +108,6 @@ func parseMEPs(path string) (map[string]*dict.Entry, error) {
 	countryCol := colByNames(colIdx, "country", "country_of_representation", "countryofrepresentation", "mep_country_of_representation")
 	groupCol := colByNames(colIdx, "political_group", "politicalgroup", "group", "mep_political_group")
 
-	entries := make(map[string]*dict.Entry, 1500)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -122,6 +116,7 @@ func parseMEPs(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		fullName := strings.TrimSpace(safeCol(record, nameCol))
 		family := strings.TrimSpace(safeCol(record, familyCol))
@@ -143,13 +138,17 @@ func parseMEPs(path string) (map[string]*dict.Entry, error) {
 			"group":   safeCol(record, groupCol),
 		}
 
-		entries[dict.NormalizeLowercaseASCII(fullName)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(dict.NormalizeLowercaseASCII(fullName), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		// Also index by family name
 		if family != "" {
-			entries[dict.NormalizeLowercaseASCII(family)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(family), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d deputes europeens\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d deputes europeens\n", w.Count())
+	return nil
 }
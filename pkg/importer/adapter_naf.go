@@ -30,35 +30,30 @@ func (a *nafAdapter) DefaultURL() string {
 func (a *nafAdapter) License() string { return "CC0" }
 
 func (a *nafAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "naf.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseNAF(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseNAF(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "activity_code",
 		Source:       "INSEE NAF Rev.2",
@@ -66,14 +61,15 @@ func (a *nafAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 		License:      "CC0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		ParentColumn: "section",
 	})
 }
 
 // parseNAF reads the INSEE NAF CSV. Expected columns: code;libelle (semicolon-delimited).
-func parseNAF(path string) (map[string]*dict.Entry, error) {
+func parseNAF(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -93,7 +89,7 @@ func parseNAF(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -123,7 +119,6 @@ func parseNAF(path string) (map[string]*dict.Entry, error) {
 		labelCol = 1
 	}
 
-	entries := make(map[string]*dict.Entry)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -132,6 +127,7 @@ func parseNAF(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := ""
 		label := ""
@@ -149,32 +145,65 @@ func parseNAF(path string) (map[string]*dict.Entry, error) {
 			"code":  code,
 			"label": label,
 		}
+		if section := nafSection(code); section != "" {
+			meta["section"] = section
+		}
 
 		lowerCode := strings.ToLower(code)
-		entries[lowerCode] = &dict.Entry{Metadata: meta}
+		if err := w.Put(lowerCode, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index without letter suffix (e.g., "6201z" → "6201").
 		stripped := strings.TrimRight(lowerCode, "abcdefghijklmnopqrstuvwxyz")
 		if stripped != lowerCode {
-			entries[stripped] = &dict.Entry{Metadata: meta}
+			if err := w.Put(stripped, &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		// Also index with dot format (e.g., "6201" → "62.01").
 		if len(stripped) == 4 {
 			dotted := stripped[:2] + "." + stripped[2:]
-			entries[dotted] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dotted, &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		} else if len(stripped) == 5 {
 			dotted := stripped[:2] + "." + stripped[2:]
-			entries[dotted] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dotted, &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if label != "" {
 			key := dict.NormalizeLowercaseASCII(label)
-			if _, exists := entries[key]; !exists {
-				entries[key] = &dict.Entry{Metadata: meta}
+			if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+				return err
 			}
 		}
 	}
 
-	fmt.Printf("  %d codes NAF\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d codes NAF\n", w.Count())
+	return nil
+}
+
+// nafSectionStarts lists the first division of every NAF Rev.2 section, A to U.
+var nafSectionStarts = []int{1, 5, 10, 35, 36, 41, 45, 49, 55, 58, 64, 68, 69, 77, 84, 85, 86, 90, 94, 97, 99}
+
+// nafSection returns the section letter of a NAF code from its two-digit
+// division ("62.01Z" → "J"), or "" for codes without one.
+func nafSection(code string) string {
+	if len(code) < 2 || code[0] < '0' || code[0] > '9' || code[1] < '0' || code[1] > '9' {
+		return ""
+	}
+	div := int(code[0]-'0')*10 + int(code[1]-'0')
+	if div < 1 {
+		return ""
+	}
+	section := ""
+	for i, start := range nafSectionStarts {
+		if div >= start {
+			section = string(rune('A' + i))
+		}
+	}
+	return section
 }
@@ -30,35 +30,30 @@ func (a *nutsEUAdapter) DefaultURL() string {
 func (a *nutsEUAdapter) License() string { return "CC BY 4.0" }
 
 func (a *nutsEUAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "nuts.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseNUTS(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseNUTS(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "eu",
 		EntityType:   "region",
 		Source:       "Eurostat NUTS",
@@ -66,13 +61,15 @@ func (a *nutsEUAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 		License:      "CC BY 4.0",
 		DataFile:     "data.db",
 		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		ParentDict:   a.DictID(),
+		ParentColumn: "parent",
 	})
 }
 
-func parseNUTS(path string) (map[string]*dict.Entry, error) {
+func parseNUTS(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,7 +80,7 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -99,7 +96,7 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 	if codeCol < 0 || nameCol < 0 {
 		// Try comma-separated fallback
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r2 := csv.NewReader(f)
 		r2.Comma = ','
@@ -107,7 +104,7 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 		r2.FieldsPerRecord = -1
 		header, err = r2.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (comma): %w", err)
+			return fmt.Errorf("read header (comma): %w", err)
 		}
 		colIdx = make(map[string]int)
 		for i, h := range header {
@@ -121,10 +118,9 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 	}
 
 	if codeCol < 0 {
-		return nil, fmt.Errorf("NUTS CODE column not found in header %v", header)
+		return fmt.Errorf("NUTS CODE column not found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry, 2000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -133,6 +129,7 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := strings.TrimSpace(safeCol(record, codeCol))
 		name := strings.TrimSpace(safeCol(record, nameCol))
@@ -146,13 +143,21 @@ func parseNUTS(path string) (map[string]*dict.Entry, error) {
 			"level":     safeCol(record, levelCol),
 			"country":   safeCol(record, countryCol),
 		}
+		// NUTS codes nest by prefix: FRK26 → FRK2 → FRK → FR.
+		if len(code) > 2 {
+			meta["parent"] = code[:len(code)-1]
+		}
 
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if name != "" {
-			entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d codes NUTS\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d codes NUTS\n", w.Count())
+	return nil
 }
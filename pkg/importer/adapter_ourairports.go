@@ -30,35 +30,30 @@ func (a *ourAirportsAdapter) DefaultURL() string {
 func (a *ourAirportsAdapter) License() string { return "Public Domain" }
 
 func (a *ourAirportsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "airports.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseOurAirports(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseOurAirports(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "airport",
 		Source:     "OurAirports",
 		SourceURL:  sourceURL,
@@ -70,10 +65,10 @@ func (a *ourAirportsAdapter) Import(ctx context.Context, sourceURL, outputDir st
 
 // parseOurAirports reads the OurAirports CSV.
 // Columns: id, ident, type, name, latitude_deg, longitude_deg, ..., iso_country, ..., iata_code, ...
-func parseOurAirports(path string) (map[string]*dict.Entry, error) {
+func parseOurAirports(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,7 +78,7 @@ func parseOurAirports(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -99,10 +94,9 @@ func parseOurAirports(path string) (map[string]*dict.Entry, error) {
 	municipalityCol := colByNames(colIdx, "municipality")
 
 	if nameCol < 0 {
-		return nil, fmt.Errorf("column 'name' not found in header %v", header)
+		return fmt.Errorf("column 'name' not found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry, 30000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -111,6 +105,7 @@ func parseOurAirports(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		name := safeCol(record, nameCol)
 		if name == "" {
@@ -141,17 +136,23 @@ func parseOurAirports(path string) (map[string]*dict.Entry, error) {
 
 		// Index by name.
 		key := dict.NormalizeLowercaseASCII(name)
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by IATA and ICAO codes.
 		if iata != "" {
-			entries[strings.ToLower(iata)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(iata), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if icao != "" {
-			entries[strings.ToLower(icao)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(icao), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d aeroports\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d aeroports\n", w.Count())
+	return nil
 }
@@ -30,35 +30,30 @@ func (a *postcodesFRAdapter) DefaultURL() string {
 func (a *postcodesFRAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *postcodesFRAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "postcodes.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parsePostcodesFR(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parsePostcodesFR(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "postcode",
 		Source:       "La Poste (Datanova)",
@@ -69,10 +64,10 @@ func (a *postcodesFRAdapter) Import(ctx context.Context, sourceURL, outputDir st
 	})
 }
 
-func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
+func parsePostcodesFR(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,13 +78,13 @@ func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	// Try semicolon if comma gives single column
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -97,7 +92,7 @@ func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
 		r.FieldsPerRecord = -1
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 
@@ -111,10 +106,9 @@ func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
 	codeINSEECol := colByNames(colIdx, "code_commune_insee")
 
 	if codeCol < 0 {
-		return nil, fmt.Errorf("column code_postal not found in header %v", header)
+		return fmt.Errorf("column code_postal not found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry, 70000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -123,6 +117,7 @@ func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		code := safeCol(record, codeCol)
 		commune := safeCol(record, communeCol)
@@ -136,13 +131,17 @@ func parsePostcodesFR(path string) (map[string]*dict.Entry, error) {
 			"code_commune": safeCol(record, codeINSEECol),
 		}
 
-		entries[strings.ToLower(code)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(code), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 		if commune != "" {
 			key := dict.NormalizeLowercaseASCII(commune)
-			entries[key] = &dict.Entry{Metadata: meta}
+			if err := w.Put(key, &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 	}
 
-	fmt.Printf("  %d codes postaux FR\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d codes postaux FR\n", w.Count())
+	return nil
 }
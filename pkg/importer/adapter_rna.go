@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Import adapter for French RNA (Repertoire National des Associations) from data.gouv.fr.
+// CLAUDE:SUMMARY Import adapter for French RNA (Repertoire National des Associations) from data.gouv.fr; daily deltas applied incrementally.
 package importer
 
 import (
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
 )
@@ -17,7 +18,11 @@ func init() {
 	Register(&rnaAdapter{})
 }
 
-type rnaAdapter struct{}
+type rnaAdapter struct {
+	deltaURL string // data.gouv.fr API URL of the dataset publishing the deltas; "" for the default
+}
+
+var _ IncrementalAdapter = (*rnaAdapter)(nil)
 
 func (a *rnaAdapter) ID() string      { return "rna-associations-fr" }
 func (a *rnaAdapter) DictID() string  { return "associations-fr" }
@@ -30,15 +35,13 @@ func (a *rnaAdapter) DefaultURL() string {
 func (a *rnaAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *rnaAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "rna.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
-	fmt.Println("  (fichier volumineux ~300 Mo, patience...)")
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -70,23 +73,19 @@ func (a *rnaAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 		return fmt.Errorf("no CSV found in RNA ZIP")
 	}
 
-	entries, err := parseRNA(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseRNA(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "association",
 		Source:       "RNA (Ministere de l'Interieur)",
@@ -97,12 +96,59 @@ func (a *rnaAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 	})
 }
 
-// parseRNA reads the RNA CSV (semicolon-delimited).
-// Key columns: id (RNA W number), titre, objet, adrs_codepostal, adrs_libcommune.
-func parseRNA(path string) (map[string]*dict.Entry, error) {
+// ImportDelta applies the daily RNA deltas: updated associations are
+// stored, dissolved or deleted ones (position "D" or "S") removed. A renamed
+// association keeps its former title as a key until the next full import.
+func (a *rnaAdapter) ImportDelta(ctx context.Context, since time.Time, w DeltaWriter) error {
+	feed := a.deltaURL
+	if feed == "" {
+		feed = "https://www.data.gouv.fr/api/1/datasets/repertoire-national-des-associations/"
+	}
+	files, err := dataGouvDeltas(ctx, feed, since)
+	if err != nil {
+		return err
+	}
+	return applyDeltas(ctx, a, files, func(csvPath string) error {
+		return readRNA(csvPath, func(key string, meta map[string]string, active bool) error {
+			rowRead(w)
+			if !active {
+				return w.Delete(key, ownedBy("rna_id", meta["rna_id"]))
+			}
+			return w.Put(key, &dict.Entry{Metadata: meta})
+		})
+	})
+}
+
+// parseRNA reads the RNA CSV, skipping dissolved and deleted associations.
+func parseRNA(path string, w dict.EntryWriter) error {
+	var count int
+	err := readRNA(path, func(key string, meta map[string]string, active bool) error {
+		rowRead(w)
+		if !active {
+			return nil
+		}
+		count++
+		if count%500000 == 0 {
+			fmt.Printf("  %d associations traitees...\n", count)
+		}
+		return w.Put(key, &dict.Entry{Metadata: meta})
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %d associations RNA\n", w.Count())
+	return nil
+}
+
+// readRNA streams the associations of an RNA CSV (semicolon-delimited),
+// stock or delta, to fn: the normalized title, the metadata and whether the
+// association is active, i.e. its position is neither "D" (dissolved) nor
+// "S" (deleted).
+// Key columns: id (RNA W number), titre, position, adrs_codepostal, adrs_libcommune.
+func readRNA(path string, fn func(key string, meta map[string]string, active bool) error) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -114,7 +160,7 @@ func parseRNA(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -126,13 +172,12 @@ func parseRNA(path string) (map[string]*dict.Entry, error) {
 	titreCol := colByNames(colIdx, "titre")
 	cpCol := colByNames(colIdx, "adrs_codepostal")
 	communeCol := colByNames(colIdx, "adrs_libcommune")
+	positionCol := colByNames(colIdx, "position")
 
 	if titreCol < 0 {
-		return nil, fmt.Errorf("column 'titre' not found in header %v", header)
+		return fmt.Errorf("column 'titre' not found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry, 500000)
-	var count int
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -158,15 +203,11 @@ func parseRNA(path string) (map[string]*dict.Entry, error) {
 			meta["commune"] = commune
 		}
 
+		position := safeCol(record, positionCol)
 		key := dict.NormalizeLowercaseASCII(titre)
-		entries[key] = &dict.Entry{Metadata: meta}
-		count++
-
-		if count%500000 == 0 {
-			fmt.Printf("  %d associations traitees...\n", count)
+		if err := fn(key, meta, position != "D" && position != "S"); err != nil {
+			return err
 		}
 	}
-
-	fmt.Printf("  %d associations RNA\n", len(entries))
-	return entries, nil
+	return nil
 }
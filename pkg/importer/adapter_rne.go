@@ -30,36 +30,30 @@ func (a *rneAdapter) DefaultURL() string {
 func (a *rneAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *rneAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "rne.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
-	fmt.Println("  (fichier tres volumineux, patience...)")
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseRNE(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseRNE(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "company",
 		Source:       "INPI RNE",
@@ -74,10 +68,10 @@ func (a *rneAdapter) Import(ctx context.Context, sourceURL, outputDir string) er
 	})
 }
 
-func parseRNE(path string) (map[string]*dict.Entry, error) {
+func parseRNE(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -89,13 +83,13 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	// Try semicolon
 	if len(header) <= 2 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -104,7 +98,7 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 
@@ -122,7 +116,6 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 	cpCol := colByNames(colIdx, "code_postal", "adresse_code_postal")
 	communeCol := colByNames(colIdx, "commune", "adresse_commune", "libelle_commune")
 
-	entries := make(map[string]*dict.Entry, 15000000)
 	var count int
 	for {
 		record, err := r.Read()
@@ -132,6 +125,7 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		siren := strings.TrimSpace(safeCol(record, sirenCol))
 		name := strings.TrimSpace(safeCol(record, nameCol))
@@ -150,10 +144,14 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 		}
 
 		if siren != "" {
-			entries[strings.ToLower(siren)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(siren), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		if name != "" {
-			entries[dict.NormalizeLowercaseASCII(name)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		count++
@@ -163,5 +161,5 @@ func parseRNE(path string) (map[string]*dict.Entry, error) {
 	}
 
 	fmt.Printf("  %d entreprises RNE\n", count)
-	return entries, nil
+	return nil
 }
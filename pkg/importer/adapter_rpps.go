@@ -30,15 +30,13 @@ func (a *rppsAdapter) DefaultURL() string {
 func (a *rppsAdapter) License() string { return "Licence Ouverte v2" }
 
 func (a *rppsAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "rpps.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
-	fmt.Println("  (fichier volumineux, patience...)")
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -46,11 +44,10 @@ func (a *rppsAdapter) Import(ctx context.Context, sourceURL, outputDir string) e
 	files, err := unzipFile(zipPath, dlDir)
 	if err != nil {
 		// Might be a direct CSV, not a ZIP
-		entries, parseErr := parseRPPS(zipPath)
-		if parseErr != nil {
+		if parseErr := a.save(ctx, zipPath, sourceURL, outputDir); parseErr != nil {
 			return fmt.Errorf("unzip: %w (also tried CSV: %v)", err, parseErr)
 		}
-		return a.saveEntries(entries, sourceURL, outputDir)
+		return nil
 	}
 
 	var csvPath string
@@ -64,27 +61,24 @@ func (a *rppsAdapter) Import(ctx context.Context, sourceURL, outputDir string) e
 		return fmt.Errorf("no CSV found in RPPS archive")
 	}
 
-	entries, err := parseRPPS(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
-	return a.saveEntries(entries, sourceURL, outputDir)
+	return a.save(ctx, csvPath, sourceURL, outputDir)
 }
 
-func (a *rppsAdapter) saveEntries(entries map[string]*dict.Entry, sourceURL, outputDir string) error {
+// save parses the RPPS CSV at path into the dictionary and its manifest.
+func (a *rppsAdapter) save(ctx context.Context, path, sourceURL, outputDir string) error {
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseRPPS(path, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-03",
 		Jurisdiction: "fr",
 		EntityType:   "health_professional",
 		Source:       "RPPS (annuaire.sante.fr)",
@@ -99,10 +93,10 @@ func (a *rppsAdapter) saveEntries(entries map[string]*dict.Entry, sourceURL, out
 	})
 }
 
-func parseRPPS(path string) (map[string]*dict.Entry, error) {
+func parseRPPS(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -114,13 +108,13 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	// Try pipe, then semicolon, then comma
 	if len(header) <= 1 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ';'
@@ -129,12 +123,12 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (semicolon): %w", err)
+			return fmt.Errorf("read header (semicolon): %w", err)
 		}
 	}
 	if len(header) <= 1 {
 		if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
-			return nil, fmt.Errorf("seek: %w", seekErr)
+			return fmt.Errorf("seek: %w", seekErr)
 		}
 		r = csv.NewReader(f)
 		r.Comma = ','
@@ -143,7 +137,7 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 		r.ReuseRecord = true
 		header, err = r.Read()
 		if err != nil {
-			return nil, fmt.Errorf("read header (comma): %w", err)
+			return fmt.Errorf("read header (comma): %w", err)
 		}
 	}
 
@@ -157,11 +151,10 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 
 	rppsCol := colByNames(colIdx, "identification nationale pp", "rpps", "identifiant_pp", "numero rpps")
 	nomCol := colByNames(colIdx, "nom d'exercice", "nom exercice", "nom", "nom_exercice")
-	prenomCol := colByNames(colIdx, "prenom d'exercice", "prenom exercice", "prenom", "prenom_exercice")
-	profCol := colByNames(colIdx, "libelle profession", "profession", "lib_profession")
-	specCol := colByNames(colIdx, "libelle savoir-faire", "specialite", "savoir_faire")
+	prenomCol := colByNames(colIdx, "prénom d'exercice", "prenom d'exercice", "prenom exercice", "prenom", "prenom_exercice")
+	profCol := colByNames(colIdx, "libellé profession", "libelle profession", "profession", "lib_profession")
+	specCol := colByNames(colIdx, "libellé savoir-faire", "libelle savoir-faire", "specialite", "savoir_faire")
 
-	entries := make(map[string]*dict.Entry, 2000000)
 	var count int
 	for {
 		record, err := r.Read()
@@ -171,6 +164,7 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		rpps := strings.TrimSpace(safeCol(record, rppsCol))
 		nom := strings.TrimSpace(safeCol(record, nomCol))
@@ -189,15 +183,21 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 		}
 
 		if rpps != "" {
-			entries[strings.ToLower(rpps)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(strings.ToLower(rpps), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		fullName := strings.TrimSpace(nom + " " + prenom)
 		if fullName != "" {
-			entries[dict.NormalizeLowercaseASCII(fullName)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(fullName), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 		// Also index by last name alone
 		if nom != "" {
-			entries[dict.NormalizeLowercaseASCII(nom)] = &dict.Entry{Metadata: meta}
+			if err := w.Put(dict.NormalizeLowercaseASCII(nom), &dict.Entry{Metadata: meta}); err != nil {
+				return err
+			}
 		}
 
 		count++
@@ -207,5 +207,5 @@ func parseRPPS(path string) (map[string]*dict.Entry, error) {
 	}
 
 	fmt.Printf("  %d professionnels RPPS\n", count)
-	return entries, nil
+	return nil
 }
@@ -1,4 +1,4 @@
-// CLAUDE:SUMMARY Import adapter for French SIRENE company registry filtering active legal entities with SIREN numbers.
+// CLAUDE:SUMMARY Import adapter for French SIRENE company registry filtering active legal entities with SIREN numbers; daily deltas from data.gouv.fr applied incrementally.
 package importer
 
 import (
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
 )
@@ -17,7 +18,11 @@ func init() {
 	Register(&sireneAdapter{})
 }
 
-type sireneAdapter struct{}
+type sireneAdapter struct {
+	deltaURL string // data.gouv.fr API URL of the dataset publishing the deltas; "" for the default
+}
+
+var _ IncrementalAdapter = (*sireneAdapter)(nil)
 
 func (a *sireneAdapter) ID() string          { return "sirene-fr" }
 func (a *sireneAdapter) DictID() string      { return "sirene-fr" }
@@ -28,15 +33,13 @@ func (a *sireneAdapter) DefaultURL() string {
 func (a *sireneAdapter) License() string      { return "Licence Ouverte v2" }
 
 func (a *sireneAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "sirene.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
-	fmt.Println("  (fichier tres volumineux ~8 Go, patience...)")
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -57,23 +60,19 @@ func (a *sireneAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 		return fmt.Errorf("no CSV found in ZIP")
 	}
 
-	entries, err := parseSIRENE(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseSIRENE(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:           a.DictID(),
-		Version:      "2026-02",
 		Jurisdiction: "fr",
 		EntityType:   "company",
 		Source:       "SIRENE (INSEE)",
@@ -84,13 +83,55 @@ func (a *sireneAdapter) Import(ctx context.Context, sourceURL, outputDir string)
 	})
 }
 
+// ImportDelta applies the daily SIRENE deltas: updated units are stored,
+// ceased ones (etat "C") removed. A renamed unit keeps its former name as a
+// key until the next full import.
+func (a *sireneAdapter) ImportDelta(ctx context.Context, since time.Time, w DeltaWriter) error {
+	feed := a.deltaURL
+	if feed == "" {
+		feed = "https://www.data.gouv.fr/api/1/datasets/base-sirene-des-entreprises-et-de-leurs-etablissements-siren-siret/"
+	}
+	files, err := dataGouvDeltas(ctx, feed, since)
+	if err != nil {
+		return err
+	}
+	return applyDeltas(ctx, a, files, func(csvPath string) error {
+		return readSIRENE(csvPath, func(key string, meta map[string]string, active bool) error {
+			rowRead(w)
+			if !active {
+				return w.Delete(key, ownedBy("siren", meta["siren"]))
+			}
+			return w.Put(key, &dict.Entry{Metadata: meta})
+		})
+	})
+}
+
 // parseSIRENE reads the SIRENE StockUniteLegale CSV in streaming mode.
 // Filters by etatAdministratifUniteLegale = "A" (active).
+func parseSIRENE(path string, w dict.EntryWriter) error {
+	var skipped int
+	err := readSIRENE(path, func(key string, meta map[string]string, active bool) error {
+		rowRead(w)
+		if !active {
+			skipped++
+			return nil
+		}
+		return w.Put(key, &dict.Entry{Metadata: meta})
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %d entreprises actives SIRENE (%d inactives ignorees)\n", w.Count(), skipped)
+	return nil
+}
+
+// readSIRENE streams the legal units of a SIRENE CSV, stock or delta, to fn:
+// the normalized name, the metadata and whether the unit is active.
 // Key columns: denominationUniteLegale (or denominationUsuelleUniteLegale), siren.
-func parseSIRENE(path string) (map[string]*dict.Entry, error) {
+func readSIRENE(path string, fn func(key string, meta map[string]string, active bool) error) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -101,7 +142,7 @@ func parseSIRENE(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -127,11 +168,9 @@ func parseSIRENE(path string) (map[string]*dict.Entry, error) {
 	}
 
 	if denomCol < 0 && denomUsuelCol < 0 {
-		return nil, fmt.Errorf("no denomination column found in header")
+		return fmt.Errorf("no denomination column found in header")
 	}
 
-	entries := make(map[string]*dict.Entry)
-	var skipped int
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -141,13 +180,9 @@ func parseSIRENE(path string) (map[string]*dict.Entry, error) {
 			continue
 		}
 
-		// Filter: only active entities.
+		active := true
 		if etatCol >= 0 && etatCol < len(record) {
-			etat := strings.TrimSpace(record[etatCol])
-			if etat != "A" {
-				skipped++
-				continue
-			}
+			active = strings.TrimSpace(record[etatCol]) == "A"
 		}
 
 		// Get company name: prefer denominationUniteLegale, fallback to usuelle.
@@ -168,9 +203,9 @@ func parseSIRENE(path string) (map[string]*dict.Entry, error) {
 		if sirenCol >= 0 && sirenCol < len(record) {
 			meta["siren"] = strings.TrimSpace(record[sirenCol])
 		}
-		entries[key] = &dict.Entry{Metadata: meta}
+		if err := fn(key, meta, active); err != nil {
+			return err
+		}
 	}
-
-	fmt.Printf("  %d entreprises actives SIRENE (%d inactives ignorees)\n", len(entries), skipped)
-	return entries, nil
+	return nil
 }
@@ -30,14 +30,13 @@ func (a *ssaBabyNamesAdapter) DefaultURL() string {
 func (a *ssaBabyNamesAdapter) License() string      { return "Public Domain" }
 
 func (a *ssaBabyNamesAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	zipPath := filepath.Join(dlDir, "names.zip")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, zipPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
@@ -47,13 +46,33 @@ func (a *ssaBabyNamesAdapter) Import(ctx context.Context, sourceURL, outputDir s
 		return fmt.Errorf("unzip: %w", err)
 	}
 
-	// Parse all yobYYYY.txt files and aggregate.
-	type agg struct {
-		sex   string
-		total int
+	dictDir := filepath.Join(outputDir, a.DictID())
+	if err := ensureDir(dictDir); err != nil {
+		return err
+	}
+
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseSSABabyNames(files, w)
+	}); err != nil {
+		return err
 	}
-	aggregated := make(map[string]*agg)
 
+	return writeManifest(dictDir, &dict.Manifest{
+		ID:           a.DictID(),
+		Jurisdiction: "us",
+		EntityType:   "first_name",
+		Source:       "SSA Baby Names",
+		SourceURL:    sourceURL,
+		License:      "Public Domain",
+		DataFile:     "data.db",
+		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
+		Surrogates:   true,
+	})
+}
+
+// parseSSABabyNames aggregates all yobYYYY.txt files (Name,Sex,Count) by
+// normalized name.
+func parseSSABabyNames(files []string, w dict.EntryWriter) error {
 	for _, f := range files {
 		base := filepath.Base(f)
 		if !strings.HasPrefix(base, "yob") || !strings.HasSuffix(base, ".txt") {
@@ -68,6 +87,7 @@ func (a *ssaBabyNamesAdapter) Import(ctx context.Context, sourceURL, outputDir s
 		scanner := bufio.NewScanner(file)
 		for scanner.Scan() {
 			line := scanner.Text()
+			rowRead(w)
 			// Format: Name,Sex,Count
 			parts := strings.SplitN(line, ",", 3)
 			if len(parts) != 3 {
@@ -78,47 +98,20 @@ func (a *ssaBabyNamesAdapter) Import(ctx context.Context, sourceURL, outputDir s
 			var count int
 			_, _ = fmt.Sscanf(strings.TrimSpace(parts[2]), "%d", &count)
 
-			key := dict.NormalizeLowercaseASCII(name)
-			if existing, ok := aggregated[key]; ok {
-				existing.total += count
-			} else {
-				aggregated[key] = &agg{sex: sex, total: count}
+			meta := map[string]string{
+				"frequency": fmt.Sprintf("%d", count),
+			}
+			if sex != "" {
+				meta["sex"] = sex
+			}
+			if err := w.Merge(dict.NormalizeLowercaseASCII(name), &dict.Entry{Metadata: meta}, sumFrequency); err != nil {
+				file.Close()
+				return err
 			}
 		}
 		file.Close()
 	}
 
-	entries := make(map[string]*dict.Entry, len(aggregated))
-	for key, a := range aggregated {
-		meta := map[string]string{
-			"frequency": fmt.Sprintf("%d", a.total),
-		}
-		if a.sex != "" {
-			meta["sex"] = a.sex
-		}
-		entries[key] = &dict.Entry{Metadata: meta}
-	}
-
-	fmt.Printf("  %d prenoms uniques US\n", len(entries))
-
-	dictDir := filepath.Join(outputDir, a.DictID())
-	if err := ensureDir(dictDir); err != nil {
-		return err
-	}
-
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
-	}
-
-	return writeManifest(dictDir, &dict.Manifest{
-		ID:           a.DictID(),
-		Version:      "2026-02",
-		Jurisdiction: "us",
-		EntityType:   "first_name",
-		Source:       "SSA Baby Names",
-		SourceURL:    sourceURL,
-		License:      "Public Domain",
-		DataFile:     "data.db",
-		Format:       dict.FormatSpec{Normalize: "lowercase_ascii"},
-	})
+	fmt.Printf("  %d prenoms uniques US\n", w.Count())
+	return nil
 }
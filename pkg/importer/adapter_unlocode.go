@@ -30,35 +30,30 @@ func (a *unlocodeAdapter) DefaultURL() string {
 func (a *unlocodeAdapter) License() string { return "PDDL" }
 
 func (a *unlocodeAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
-	dlDir := filepath.Join(outputDir, "_download")
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
 	if err := ensureDir(dlDir); err != nil {
 		return err
 	}
 	defer os.RemoveAll(dlDir)
 
 	csvPath := filepath.Join(dlDir, "locode.csv")
-	fmt.Printf("  telechargement %s...\n", sourceURL)
 	if err := downloadFile(ctx, sourceURL, csvPath); err != nil {
 		return fmt.Errorf("download: %w", err)
 	}
 
-	entries, err := parseUNLOCODE(csvPath)
-	if err != nil {
-		return fmt.Errorf("parse: %w", err)
-	}
-
 	dictDir := filepath.Join(outputDir, a.DictID())
 	if err := ensureDir(dictDir); err != nil {
 		return err
 	}
 
-	if err := dict.SaveSQLite(entries, filepath.Join(dictDir, "data.db")); err != nil {
-		return fmt.Errorf("save sqlite: %w", err)
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return parseUNLOCODE(csvPath, w)
+	}); err != nil {
+		return err
 	}
 
 	return writeManifest(dictDir, &dict.Manifest{
 		ID:         a.DictID(),
-		Version:    "2026-03",
 		EntityType: "location",
 		Source:     "UN/LOCODE (UNECE)",
 		SourceURL:  sourceURL,
@@ -70,10 +65,10 @@ func (a *unlocodeAdapter) Import(ctx context.Context, sourceURL, outputDir strin
 
 // parseUNLOCODE reads the UN/LOCODE CSV from DataHub.
 // Columns: Country, Location, Name, NameWoDiacritics, Subdivision, Status, Function, ...
-func parseUNLOCODE(path string) (map[string]*dict.Entry, error) {
+func parseUNLOCODE(path string, w dict.EntryWriter) error {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
@@ -83,7 +78,7 @@ func parseUNLOCODE(path string) (map[string]*dict.Entry, error) {
 
 	header, err := r.Read()
 	if err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+		return fmt.Errorf("read header: %w", err)
 	}
 
 	colIdx := make(map[string]int)
@@ -99,10 +94,9 @@ func parseUNLOCODE(path string) (map[string]*dict.Entry, error) {
 	functionCol := colByNames(colIdx, "Function")
 
 	if nameCol < 0 && nameAsciiCol < 0 {
-		return nil, fmt.Errorf("no name column found in header %v", header)
+		return fmt.Errorf("no name column found in header %v", header)
 	}
 
-	entries := make(map[string]*dict.Entry, 100000)
 	for {
 		record, err := r.Read()
 		if err == io.EOF {
@@ -111,6 +105,7 @@ func parseUNLOCODE(path string) (map[string]*dict.Entry, error) {
 		if err != nil {
 			continue
 		}
+		rowRead(w)
 
 		country := safeCol(record, countryCol)
 		location := safeCol(record, locationCol)
@@ -135,15 +130,17 @@ func parseUNLOCODE(path string) (map[string]*dict.Entry, error) {
 		}
 
 		// Index by LOCODE.
-		entries[strings.ToLower(locode)] = &dict.Entry{Metadata: meta}
+		if err := w.Put(strings.ToLower(locode), &dict.Entry{Metadata: meta}); err != nil {
+			return err
+		}
 
 		// Also index by name.
 		key := dict.NormalizeLowercaseASCII(name)
-		if _, exists := entries[key]; !exists {
-			entries[key] = &dict.Entry{Metadata: meta}
+		if err := w.PutNew(key, &dict.Entry{Metadata: meta}); err != nil {
+			return err
 		}
 	}
 
-	fmt.Printf("  %d localisations UN/LOCODE\n", len(entries))
-	return entries, nil
+	fmt.Printf("  %d localisations UN/LOCODE\n", w.Count())
+	return nil
 }
@@ -0,0 +1,506 @@
+// CLAUDE:SUMMARY Declarative import adapters: a YAML Spec (URL, archive type, file selector, CSV dialect, row filters, key expressions, metadata mapping, manifest) run by a generic adapter, so new CSV sources are added without recompiling. Built-in specs are embedded from specs/.
+// CLAUDE:DEPENDS pkg/importer/adapter.go, pkg/importer/helpers.go, pkg/dict/writer.go
+// CLAUDE:EXPORTS Spec, CSVSpec, FilterSpec, KeySpec, ParseSpec, RegisterSpec, LoadSpecs
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed specs/*.yaml
+var builtinSpecs embed.FS
+
+func init() {
+	names, err := builtinSpecs.ReadDir("specs")
+	if err != nil {
+		panic(err)
+	}
+	for _, n := range names {
+		data, err := builtinSpecs.ReadFile("specs/" + n.Name())
+		if err == nil {
+			err = RegisterSpec(data)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("importer: builtin spec %s: %v", n.Name(), err))
+		}
+	}
+}
+
+// Spec declares an import adapter. Expressions (keys, metadata) name a CSV
+// column, matched case-insensitively against the header; "a|b" takes the
+// first non-empty of several columns, and "{a}-{b}" is a template, empty
+// when any of its columns is.
+type Spec struct {
+	ID          string            `yaml:"id"`
+	DictID      string            `yaml:"dict_id"`
+	Description string            `yaml:"description"`
+	URL         string            `yaml:"url"`
+	License     string            `yaml:"license"`
+	Archive     string            `yaml:"archive"` // "" (plain file), "zip" or "gzip"
+	File        string            `yaml:"file"`    // glob selecting the data file of a zip, default "*.csv"
+	CSV         CSVSpec           `yaml:"csv"`
+	Filters     []FilterSpec      `yaml:"filters"`
+	Keys        []KeySpec         `yaml:"keys"`
+	Metadata    map[string]string `yaml:"metadata"` // metadata key → expression
+	Manifest    dict.Manifest     `yaml:"manifest"` // id, source_url and data_file are filled in
+}
+
+// CSVSpec is the dialect of the data file.
+type CSVSpec struct {
+	Delimiter string   `yaml:"delimiter"` // one character, default ","
+	Columns   []string `yaml:"columns"`   // column names of a file without header row
+}
+
+// FilterSpec keeps the rows whose column passes every set condition. The
+// import fails when the column is missing from the file.
+type FilterSpec struct {
+	Column     string   `yaml:"column"`
+	Equals     string   `yaml:"equals"`
+	In         []string `yaml:"in"`
+	NotEmpty   bool     `yaml:"not_empty"`
+	IgnoreCase bool     `yaml:"ignore_case"`
+}
+
+// KeySpec indexes each row under one more key.
+type KeySpec struct {
+	Expr      string `yaml:"expr"`
+	Normalize string `yaml:"normalize"`  // lowercase_ascii (default), lowercase_utf8 or none
+	KeepFirst bool   `yaml:"keep_first"` // a duplicate key keeps its first row instead of the last
+}
+
+// ParseSpec decodes and validates a YAML adapter spec. Unknown fields are
+// rejected so that a typo does not silently drop a filter.
+func ParseSpec(data []byte) (*Spec, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var s Spec
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode spec: %w", err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Spec) validate() error {
+	switch {
+	case s.ID == "":
+		return fmt.Errorf("spec: id is required")
+	case s.DictID == "":
+		return fmt.Errorf("spec %s: dict_id is required", s.ID)
+	case s.Manifest.EntityType == "":
+		return fmt.Errorf("spec %s: manifest.entity_type is required", s.ID)
+	case len(s.Keys) == 0:
+		return fmt.Errorf("spec %s: at least one key is required", s.ID)
+	}
+	switch s.Archive {
+	case "", "zip", "gzip":
+	default:
+		return fmt.Errorf("spec %s: unknown archive %q", s.ID, s.Archive)
+	}
+	if _, err := path.Match(s.File, ""); err != nil {
+		return fmt.Errorf("spec %s: file: %w", s.ID, err)
+	}
+	if d := s.CSV.Delimiter; d != "" && utf8.RuneCountInString(d) != 1 {
+		return fmt.Errorf("spec %s: delimiter must be one character, got %q", s.ID, d)
+	}
+	for _, f := range s.Filters {
+		if f.Column == "" {
+			return fmt.Errorf("spec %s: filter without column", s.ID)
+		}
+	}
+	for _, k := range s.Keys {
+		if _, err := parseExpr(k.Expr); err != nil {
+			return fmt.Errorf("spec %s: key: %w", s.ID, err)
+		}
+		switch k.Normalize {
+		case "", "lowercase_ascii", "lowercase_utf8", "none":
+		default:
+			return fmt.Errorf("spec %s: unknown normalize %q", s.ID, k.Normalize)
+		}
+	}
+	for name, e := range s.Metadata {
+		if _, err := parseExpr(e); err != nil {
+			return fmt.Errorf("spec %s: metadata %s: %w", s.ID, name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterSpec parses a YAML spec and registers its adapter, replacing any
+// adapter registered under the same ID.
+func RegisterSpec(data []byte) error {
+	s, err := ParseSpec(data)
+	if err != nil {
+		return err
+	}
+	Register(&declarativeAdapter{spec: s})
+	return nil
+}
+
+// LoadSpecs registers the adapters of every *.yaml and *.yml file in dir and
+// returns how many were loaded.
+func LoadSpecs(dir string) (int, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return 0, err
+	}
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		m, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, m...)
+	}
+	for i, f := range files {
+		data, err := os.ReadFile(f)
+		if err == nil {
+			err = RegisterSpec(data)
+		}
+		if err != nil {
+			return i, fmt.Errorf("%s: %w", f, err)
+		}
+	}
+	return len(files), nil
+}
+
+// declarativeAdapter imports the CSV source described by a Spec.
+type declarativeAdapter struct {
+	spec *Spec
+}
+
+func (a *declarativeAdapter) ID() string          { return a.spec.ID }
+func (a *declarativeAdapter) DictID() string      { return a.spec.DictID }
+func (a *declarativeAdapter) Description() string { return a.spec.Description }
+func (a *declarativeAdapter) DefaultURL() string  { return a.spec.URL }
+func (a *declarativeAdapter) License() string     { return a.spec.License }
+
+// AdapterVersion identifies the spec by a digest of its normalized YAML, so
+// provenance.json tells builds of an edited spec apart.
+func (a *declarativeAdapter) AdapterVersion() string {
+	data, err := yaml.Marshal(a.spec)
+	if err != nil {
+		return "1"
+	}
+	sum := sha256.Sum256(data)
+	return "spec-" + hex.EncodeToString(sum[:6])
+}
+
+func (a *declarativeAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
+	dlDir := filepath.Join(outputDir, "_download", a.ID())
+	if err := ensureDir(dlDir); err != nil {
+		return err
+	}
+	defer os.RemoveAll(dlDir)
+
+	dataPath, err := a.fetch(ctx, sourceURL, dlDir)
+	if err != nil {
+		return err
+	}
+
+	dictDir := filepath.Join(outputDir, a.DictID())
+	if err := ensureDir(dictDir); err != nil {
+		return err
+	}
+
+	if err := writeSQLite(ctx, dictDir, func(w dict.EntryWriter) error {
+		return a.parse(dataPath, w)
+	}); err != nil {
+		return err
+	}
+
+	m := a.spec.Manifest
+	m.ID = a.DictID()
+	m.SourceURL = sourceURL
+	m.DataFile = "data.db"
+	if m.License == "" {
+		m.License = a.spec.License
+	}
+	return writeManifest(dictDir, &m)
+}
+
+// fetch downloads the source into dlDir and returns the path of its data file.
+func (a *declarativeAdapter) fetch(ctx context.Context, sourceURL, dlDir string) (string, error) {
+	dest := filepath.Join(dlDir, "source")
+	switch a.spec.Archive {
+	case "zip":
+		dest += ".zip"
+	case "gzip":
+		dest += ".gz"
+	}
+	if err := downloadFile(ctx, sourceURL, dest); err != nil {
+		return "", fmt.Errorf("download: %w", err)
+	}
+
+	switch a.spec.Archive {
+	case "zip":
+		files, err := unzipFile(dest, dlDir)
+		if err != nil {
+			return "", fmt.Errorf("unzip: %w", err)
+		}
+		pattern := a.spec.File
+		if pattern == "" {
+			pattern = "*.csv"
+		}
+		for _, f := range files {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(filepath.Base(f))); ok {
+				return f, nil
+			}
+		}
+		return "", fmt.Errorf("no file matching %q in ZIP", pattern)
+	case "gzip":
+		out := filepath.Join(dlDir, "source.csv")
+		if err := gunzipFile(dest, out); err != nil {
+			return "", fmt.Errorf("gunzip: %w", err)
+		}
+		return out, nil
+	}
+	return dest, nil
+}
+
+// parse streams the rows of the data file at p into w.
+func (a *declarativeAdapter) parse(p string, w dict.EntryWriter) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if d := a.spec.CSV.Delimiter; d != "" {
+		r.Comma, _ = utf8.DecodeRuneInString(d)
+	}
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+
+	header := a.spec.CSV.Columns
+	if len(header) == 0 {
+		first, err := r.Read()
+		if err != nil {
+			return fmt.Errorf("read header: %w", err)
+		}
+		header = slices.Clone(first)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+
+	type key struct {
+		expr      expr
+		normalize dict.Normalizer
+		keepFirst bool
+	}
+	keys := make([]key, len(a.spec.Keys))
+	for i, k := range a.spec.Keys {
+		e, _ := parseExpr(k.Expr)
+		if !e.resolve(cols) {
+			return fmt.Errorf("key %q: no such column in header %v", k.Expr, header)
+		}
+		keys[i] = key{expr: e, normalize: dict.GetNormalizer(k.Normalize), keepFirst: k.KeepFirst}
+	}
+	meta := make(map[string]expr, len(a.spec.Metadata))
+	for name, s := range a.spec.Metadata {
+		e, _ := parseExpr(s)
+		if !e.resolve(cols) {
+			return fmt.Errorf("metadata %s %q: no such column in header %v", name, s, header)
+		}
+		meta[name] = e
+	}
+	filters := make([]int, len(a.spec.Filters))
+	for i, fs := range a.spec.Filters {
+		if filters[i] = colByNames(cols, strings.ToLower(fs.Column)); filters[i] < 0 {
+			return fmt.Errorf("filter %q: no such column in header %v", fs.Column, header)
+		}
+	}
+
+	var rows, skipped int
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			continue
+		}
+		rowRead(w)
+		rows++
+
+		kept := true
+		for i, fs := range a.spec.Filters {
+			if !fs.keep(safeCol(record, filters[i])) {
+				kept = false
+				break
+			}
+		}
+		if !kept {
+			skipped++
+			continue
+		}
+
+		m := make(map[string]string, len(meta))
+		for name, e := range meta {
+			if v := e.eval(record); v != "" {
+				m[name] = v
+			}
+		}
+		for _, k := range keys {
+			kv := k.normalize(k.expr.eval(record))
+			if kv == "" {
+				continue
+			}
+			put := w.Put
+			if k.keepFirst {
+				put = w.PutNew
+			}
+			if err := put(kv, &dict.Entry{Metadata: m}); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("  %d entrees %s (%d lignes, %d filtrees)\n", w.Count(), a.DictID(), rows, skipped)
+	return nil
+}
+
+func (fs *FilterSpec) keep(v string) bool {
+	eq := func(want string) bool {
+		if fs.IgnoreCase {
+			return strings.EqualFold(v, want)
+		}
+		return v == want
+	}
+	switch {
+	case fs.NotEmpty && v == "":
+		return false
+	case fs.Equals != "" && !eq(fs.Equals):
+		return false
+	case len(fs.In) > 0 && !slices.ContainsFunc(fs.In, eq):
+		return false
+	}
+	return true
+}
+
+// expr is a parsed key or metadata expression: literal text and column
+// references, each holding alternative column names.
+type expr []exprPart
+
+type exprPart struct {
+	lit   string
+	names []string // alternatives; nil for literal text
+	cols  []int    // resolved indexes of names
+}
+
+func parseExpr(s string) (expr, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	if !strings.ContainsAny(s, "{}") {
+		return expr{{names: splitAlternatives(s)}}, nil
+	}
+	var e expr
+	for rest := s; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced braces in %q", s)
+			}
+			e = append(e, exprPart{lit: rest})
+			break
+		}
+		if open > 0 {
+			if strings.IndexByte(rest[:open], '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced braces in %q", s)
+			}
+			e = append(e, exprPart{lit: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unbalanced braces in %q", s)
+		}
+		ref := rest[open+1 : open+end]
+		if strings.TrimSpace(ref) == "" || strings.IndexByte(ref, '{') >= 0 {
+			return nil, fmt.Errorf("bad column reference in %q", s)
+		}
+		e = append(e, exprPart{names: splitAlternatives(ref)})
+		rest = rest[open+end+1:]
+	}
+	return e, nil
+}
+
+func splitAlternatives(s string) []string {
+	names := strings.Split(s, "|")
+	for i, n := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(n))
+	}
+	return names
+}
+
+// resolve maps column names to indexes and reports whether every column
+// reference found at least one of its alternatives.
+func (e expr) resolve(cols map[string]int) bool {
+	ok := true
+	for i := range e {
+		p := &e[i]
+		if p.names == nil {
+			continue
+		}
+		p.cols = p.cols[:0]
+		for _, n := range p.names {
+			if c, found := cols[n]; found {
+				p.cols = append(p.cols, c)
+			}
+		}
+		if len(p.cols) == 0 {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (e expr) eval(record []string) string {
+	if len(e) == 1 && e[0].names != nil {
+		return firstCol(record, e[0].cols)
+	}
+	var b strings.Builder
+	for _, p := range e {
+		if p.names == nil {
+			b.WriteString(p.lit)
+			continue
+		}
+		v := firstCol(record, p.cols)
+		if v == "" {
+			return ""
+		}
+		b.WriteString(v)
+	}
+	return b.String()
+}
+
+func firstCol(record []string, cols []int) string {
+	for _, c := range cols {
+		if v := safeCol(record, c); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func TestParseSpec_Errors(t *testing.T) {
+	base := "id: x\ndict_id: x\nmanifest: {entity_type: t}\n"
+	for name, yml := range map[string]string{
+		"unknown field":  base + "keys: [{expr: a}]\nfilter: []\n",
+		"no keys":        base,
+		"braces":         base + "keys: [{expr: \"{a\"}]\n",
+		"empty ref":      base + "keys: [{expr: \"{}-x\"}]\n",
+		"normalizer":     base + "keys: [{expr: a, normalize: upper}]\n",
+		"archive":        base + "archive: rar\nkeys: [{expr: a}]\n",
+		"delimiter":      base + "csv: {delimiter: \";;\"}\nkeys: [{expr: a}]\n",
+		"no entity type": "id: x\ndict_id: x\nkeys: [{expr: a}]\n",
+	} {
+		if _, err := ParseSpec([]byte(yml)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBuiltinSpecs(t *testing.T) {
+	for _, id := range []string{"census-surnames-us", "companies-house-uk", "insee-cog-departements", "geonames-postcodes"} {
+		a, err := Get(id)
+		if err != nil {
+			t.Errorf("%s: %v", id, err)
+			continue
+		}
+		if _, ok := a.(*declarativeAdapter); !ok || a.DefaultURL() == "" {
+			t.Errorf("%s: %T with URL %q", id, a, a.DefaultURL())
+		}
+	}
+}
+
+func TestDeclarativeAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range map[string]string{
+		"readme.txt": "not data\n",
+		"data.txt": "FR\t75001\tParis 1er\n" +
+			"FR\t75001\tParis Louvre\n" +
+			"BE\t1000\tBruxelles\n" +
+			"DE\t\tNowhere\n" +
+			"XX\t99\tClosed\n",
+	} {
+		f, _ := zw.Create(name)
+		_, _ = f.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer ts.Close()
+
+	spec := `
+id: test-postcodes
+dict_id: test-postcodes
+archive: zip
+file: DATA.txt
+csv:
+  delimiter: "\t"
+  columns: [country, code, place]
+filters:
+  - column: country
+    in: [fr, be, de]
+    ignore_case: true
+keys:
+  - expr: "{country}-{code}"
+    normalize: lowercase_utf8
+    keep_first: true
+  - expr: place
+metadata:
+  country: country
+  code: code|place
+manifest:
+  entity_type: postcode
+  format: {normalize: lowercase_utf8}
+`
+	s, err := ParseSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("ParseSpec: %v", err)
+	}
+	dir := t.TempDir()
+	a := &declarativeAdapter{spec: s}
+	if err := a.Import(context.Background(), ts.URL, dir); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "_download", "test-postcodes")); !os.IsNotExist(err) {
+		t.Error("download directory left behind")
+	}
+
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	defer reg.Close()
+	d, ok := reg.Get("test-postcodes")
+	if !ok {
+		t.Fatal("dictionary not loaded")
+	}
+	if d.Manifest.SourceURL != ts.URL || d.Manifest.DataFile != "data.db" {
+		t.Errorf("manifest = %+v", d.Manifest)
+	}
+
+	for term, want := range map[string]string{
+		"FR-75001":  "75001",
+		"be-1000":   "1000",
+		"paris 1er": "75001",
+		"nowhere":   "Nowhere", // code|place falls back to place
+		"Bruxelles": "1000",
+	} {
+		e, ok := d.Lookup(term)
+		if !ok {
+			t.Errorf("%s: not found", term)
+			continue
+		}
+		if e.Metadata["code"] != want {
+			t.Errorf("%s: code = %q, want %q", term, e.Metadata["code"], want)
+		}
+	}
+	if e, _ := d.Lookup("fr-75001"); e == nil || e.Metadata["country"] != "FR" {
+		t.Errorf("fr-75001 = %+v", e)
+	}
+	if _, ok := d.Lookup("de-"); ok {
+		t.Error("template with an empty column must not produce a key")
+	}
+	if _, ok := d.Lookup("closed"); ok {
+		t.Error("filtered row imported")
+	}
+	if n := d.EntryCount(); n != 6 {
+		t.Errorf("EntryCount = %d, want 6", n)
+	}
+
+	// Like keys, filters and metadata must name columns of the file.
+	for _, bad := range []string{
+		strings.Replace(spec, "  - column: country\n", "  - column: missing\n", 1),
+		strings.Replace(spec, "  country: country\n", "  country: missing\n", 1),
+	} {
+		s, err := ParseSpec([]byte(bad))
+		if err != nil {
+			t.Fatalf("ParseSpec: %v", err)
+		}
+		a := &declarativeAdapter{spec: s}
+		if err := a.Import(context.Background(), ts.URL, t.TempDir()); err == nil || !strings.Contains(err.Error(), "no such column") {
+			t.Errorf("Import with a missing column: err = %v", err)
+		}
+	}
+}
+
+func TestLoadSpecs(t *testing.T) {
+	dir := t.TempDir()
+	spec := "id: test-local\ndict_id: local\nkeys: [{expr: name}]\nmanifest: {entity_type: thing}\n"
+	if err := os.WriteFile(filepath.Join(dir, "local.yml"), []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(adapters, "test-local")
+		registryMu.Unlock()
+	})
+	n, err := LoadSpecs(dir)
+	if err != nil || n != 1 {
+		t.Fatalf("LoadSpecs = %d, %v", n, err)
+	}
+	if a, err := Get("test-local"); err != nil || a.DictID() != "local" {
+		t.Errorf("Get = %v, %v", a, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("id: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSpecs(dir); err == nil || !strings.Contains(err.Error(), "bad.yaml") {
+		t.Errorf("bad spec: err = %v", err)
+	}
+	if _, err := LoadSpecs(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing directory: expected error")
+	}
+}
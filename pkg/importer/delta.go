@@ -0,0 +1,223 @@
+// CLAUDE:SUMMARY Incremental imports: an IncrementalAdapter applies the delta files its source published since the last import to a copy of the live data.db; Run falls back to a full import when the delta chain is broken.
+// CLAUDE:DEPENDS pkg/dict/writer.go, pkg/importer/run.go
+// CLAUDE:EXPORTS IncrementalAdapter, DeltaWriter, ErrDeltaChainBroken
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// ErrDeltaChainBroken is returned, wrapped, by IncrementalAdapter.ImportDelta
+// when the deltas the source still publishes do not reach back to the last
+// import. Run then rebuilds the dictionary from the full source.
+var ErrDeltaChainBroken = errors.New("delta chain broken")
+
+// DeltaWriter applies a delta to a dictionary: Put inserts or updates an
+// entry, Delete removes the entry of a closed entity.
+type DeltaWriter interface {
+	dict.EntryWriter
+	// Delete removes key when match, given the entry stored there, returns
+	// true, or whatever the entry when match is nil.
+	Delete(key string, match func(old *dict.Entry) bool) error
+}
+
+// IncrementalAdapter is an Adapter whose source also publishes delta files.
+// Run applies them to the live dictionary instead of rebuilding it when the
+// adapter has been imported before.
+type IncrementalAdapter interface {
+	Adapter
+	// ImportDelta applies to w the inserts, updates and closures the source
+	// published since the given time. It returns an error wrapping
+	// ErrNotModified when there is none yet, and ErrDeltaChainBroken when
+	// the published deltas do not cover since.
+	ImportDelta(ctx context.Context, since time.Time, w DeltaWriter) error
+}
+
+// importDelta stages a copy of the live dictionary of a, with the deltas
+// published since applied to it, for publishStaged.
+func importDelta(ctx context.Context, a IncrementalAdapter, since time.Time, staging, outputDir string) error {
+	live := filepath.Join(outputDir, a.DictID())
+	dictDir := filepath.Join(staging, a.DictID())
+	if err := ensureDir(dictDir); err != nil {
+		return err
+	}
+	manifest, err := os.ReadFile(filepath.Join(live, "manifest.yaml"))
+	if err != nil {
+		return fmt.Errorf("read manifest: %w", err)
+	}
+	if err := updateSQLite(ctx, filepath.Join(live, "data.db"), dictDir, func(w DeltaWriter) error {
+		return a.ImportDelta(ctx, since, w)
+	}); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dictDir, "manifest.yaml"), manifest, 0o644)
+}
+
+// updateSQLite is writeSQLite for deltas: apply edits a copy of the data.db
+// at from, written to dictDir/data.db.
+func updateSQLite(ctx context.Context, from, dictDir string, apply func(w DeltaWriter) error) error {
+	sw, err := dict.UpdateSQLite(from, filepath.Join(dictDir, "data.db"))
+	if err != nil {
+		return fmt.Errorf("open sqlite: %w", err)
+	}
+	return fillSQLite(ctx, sw, dictDir, func(pw *progressEntryWriter) error {
+		return apply(&deltaEntryWriter{progressEntryWriter: pw, sw: sw})
+	})
+}
+
+// deltaEntryWriter adds the deletions of a delta to a progressEntryWriter.
+type deltaEntryWriter struct {
+	*progressEntryWriter
+	sw *dict.SQLiteWriter
+}
+
+func (w *deltaEntryWriter) Delete(key string, match func(old *dict.Entry) bool) error {
+	if err := w.tick(); err != nil {
+		return err
+	}
+	return w.sw.Delete(key, match)
+}
+
+// ownedBy matches an entry whose metadata field holds id, so that closing
+// an entity leaves a homonym's entry in place.
+func ownedBy(field, id string) func(old *dict.Entry) bool {
+	return func(old *dict.Entry) bool {
+		return id != "" && old.Metadata[field] == id
+	}
+}
+
+// deltaFile is a delta published by a source: the changes of one day.
+type deltaFile struct {
+	Day time.Time
+	URL string
+}
+
+// resourceDay finds the day of a delta resource in its title, as 2026-10-17
+// or 20261017.
+var resourceDay = regexp.MustCompile(`(\d{4})-?(\d{2})-?(\d{2})`)
+
+// dataGouvDeltas returns, oldest first, the daily deltas of the data.gouv.fr
+// dataset described at datasetURL (its API URL) that hold changes made on
+// since's day or later: the resources of type "update" whose title carries
+// a day. The delta of since's day is applied again, which is harmless as
+// deltas are idempotent. Every day up to the last delta must be published.
+func dataGouvDeltas(ctx context.Context, datasetURL string, since time.Time) ([]deltaFile, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, datasetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "touchstone-registry/1.0 (open-data-import)")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dataset API HTTP %d", resp.StatusCode)
+	}
+
+	var dataset struct {
+		Resources []struct {
+			Type  string `json:"type"`
+			Title string `json:"title"`
+			URL   string `json:"url"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&dataset); err != nil {
+		return nil, fmt.Errorf("decode dataset: %w", err)
+	}
+
+	first := since.UTC().Truncate(24 * time.Hour)
+	byDay := make(map[time.Time]string)
+	for _, r := range dataset.Resources {
+		m := resourceDay.FindStringSubmatch(r.Title)
+		if r.Type != "update" || m == nil {
+			continue
+		}
+		day, err := time.Parse("20060102", m[1]+m[2]+m[3])
+		if err != nil || day.Before(first) {
+			continue
+		}
+		byDay[day] = r.URL
+	}
+	if len(byDay) == 0 {
+		return nil, fmt.Errorf("%w: no delta since %s", ErrNotModified, first.Format(time.DateOnly))
+	}
+
+	files := make([]deltaFile, 0, len(byDay))
+	for day, url := range byDay {
+		files = append(files, deltaFile{Day: day, URL: url})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Day.Before(files[j].Day) })
+	for i, day := 0, first; i < len(files); i, day = i+1, day.AddDate(0, 0, 1) {
+		if !files[i].Day.Equal(day) {
+			return nil, fmt.Errorf("%w: no delta for %s", ErrDeltaChainBroken, day.Format(time.DateOnly))
+		}
+	}
+	return files, nil
+}
+
+// applyDeltas downloads each delta of files in turn, in a temporary
+// directory, and applies the CSV it holds with apply.
+func applyDeltas(ctx context.Context, a Adapter, files []deltaFile, apply func(csvPath string) error) error {
+	dir, err := os.MkdirTemp("", a.ID()+"-delta-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range files {
+		csvPath, err := fetchCSV(ctx, f.URL, filepath.Join(dir, f.Day.Format("20060102")))
+		if err != nil {
+			return fmt.Errorf("delta of %s: %w", f.Day.Format(time.DateOnly), err)
+		}
+		if err := apply(csvPath); err != nil {
+			return fmt.Errorf("delta of %s: %w", f.Day.Format(time.DateOnly), err)
+		}
+	}
+	return nil
+}
+
+// fetchCSV downloads url into dir and returns the CSV it is, or the first
+// one of the ZIP it is.
+func fetchCSV(ctx context.Context, url, dir string) (string, error) {
+	if err := ensureDir(dir); err != nil {
+		return "", err
+	}
+	name := path.Base(strings.SplitN(url, "?", 2)[0])
+	if !strings.HasSuffix(strings.ToLower(name), ".zip") && !strings.HasSuffix(strings.ToLower(name), ".csv") {
+		name = "delta.zip"
+	}
+	dest := filepath.Join(dir, name)
+	if err := downloadFile(ctx, url, dest); err != nil {
+		return "", fmt.Errorf("download: %w", err)
+	}
+	if strings.HasSuffix(strings.ToLower(dest), ".csv") {
+		return dest, nil
+	}
+	files, err := unzipFile(dest, dir)
+	if err != nil {
+		return "", fmt.Errorf("unzip: %w", err)
+	}
+	for _, f := range files {
+		if strings.HasSuffix(strings.ToLower(f), ".csv") {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("no CSV found in %s", name)
+}
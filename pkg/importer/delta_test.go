@@ -0,0 +1,245 @@
+package importer
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// deltaServer serves the fixture files of testdata/deltas, name.csv also
+// zipped as name.zip, and /dataset: a data.gouv.fr dataset listing the
+// fixtures of *days as daily updates.
+func deltaServer(t *testing.T, prefix string, days *[]string) *httptest.Server {
+	t.Helper()
+	files := http.FileServer(http.Dir("testdata/deltas"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch ext := filepath.Ext(r.URL.Path); {
+		case r.URL.Path == "/dataset":
+			type resource struct {
+				Type  string `json:"type"`
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			res := []resource{{Type: "main", Title: "Stock", URL: "http://" + r.Host + "/" + prefix + "_stock.zip"}}
+			for _, day := range *days {
+				res = append(res, resource{Type: "update", Title: "Mise a jour du " + day, URL: "http://" + r.Host + "/" + prefix + "_" + day + ".csv"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"resources": res})
+		case ext == ".zip":
+			csvName := r.URL.Path[1:len(r.URL.Path)-len(ext)] + ".csv"
+			b, err := os.ReadFile(filepath.Join("testdata/deltas", csvName))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			zw := zip.NewWriter(w)
+			f, _ := zw.Create(csvName)
+			_, _ = f.Write(b)
+			_ = zw.Close()
+		default:
+			files.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// lookupTerm returns the metadata stored under key in the data.db at path,
+// and whether the key is there.
+func lookupTerm(t *testing.T, path, key string) (map[string]string, bool) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var raw string
+	err = db.QueryRow(`SELECT metadata FROM terms WHERE key = ?`, key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, false
+	}
+	if err != nil {
+		t.Fatalf("lookup %q: %v", key, err)
+	}
+	meta := map[string]string{}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return meta, true
+}
+
+func TestRun_SIRENEDelta(t *testing.T) {
+	days := []string{"20261016", "20261017"}
+	ts := deltaServer(t, "sirene", &days)
+	a := &sireneAdapter{deltaURL: ts.URL + "/dataset"}
+	dir := t.TempDir()
+	sdb, err := OpenSourceDB(filepath.Join(dir, "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+	if err := sdb.Seed([]Adapter{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.SetURL(a.ID(), ts.URL+"/sirene_stock.zip"); err != nil {
+		t.Fatal(err)
+	}
+	run := func() error { return Run(context.Background(), sdb, a, dir, RunOptions{}) }
+	live := filepath.Join(dir, a.DictID(), "data.db")
+	since := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	if err := run(); err != nil {
+		t.Fatalf("full import: %v", err)
+	}
+	if at, err := sdb.ImportedAt(a.ID()); err != nil || at.IsZero() {
+		t.Fatalf("ImportedAt = %v, %v", at, err)
+	}
+	if err := sdb.SetImportedAt(a.ID(), since); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("delta import: %v", err)
+	}
+	if _, ok := lookupTerm(t, live, "acme"); ok {
+		t.Error("a ceased unit must be removed")
+	}
+	if meta, ok := lookupTerm(t, live, "nouvelle sas"); !ok || meta["siren"] != "111111111" {
+		t.Errorf("nouvelle sas = %v, %v; a homonym's closure must not remove it", meta, ok)
+	}
+	if _, ok := lookupTerm(t, live, "boulangerie martin"); !ok {
+		t.Error("an updated unit must be kept")
+	}
+	if _, ok := lookupTerm(t, filepath.Join(dir, a.DictID()+dict.PrevSuffix, "data.db"), "acme"); !ok {
+		t.Error("the build before the delta must be kept for rollback")
+	}
+	if at, _ := sdb.ImportedAt(a.ID()); !at.After(since) {
+		t.Errorf("ImportedAt = %v, want the time of the delta import", at)
+	}
+
+	// No delta published since the last import.
+	if err := sdb.SetImportedAt(a.ID(), time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(); !errors.Is(err, ErrNotModified) {
+		t.Errorf("no delta: err = %v, want ErrNotModified", err)
+	}
+
+	// The delta of 2026-10-16 is no longer published: full import.
+	days = []string{"20261017"}
+	if err := sdb.SetImportedAt(a.ID(), since); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(); err != nil {
+		t.Fatalf("fallback: %v", err)
+	}
+	if _, ok := lookupTerm(t, live, "acme"); !ok {
+		t.Error("a broken chain must rebuild the dictionary from the stock")
+	}
+	if _, ok := lookupTerm(t, live, "nouvelle sas"); ok {
+		t.Error("fallback kept an entry the stock does not hold")
+	}
+}
+
+func TestRNAAdapter_ImportDelta(t *testing.T) {
+	days := []string{"20261017"}
+	ts := deltaServer(t, "rna", &days)
+	a := &rnaAdapter{deltaURL: ts.URL + "/dataset"}
+	dir := t.TempDir()
+	ctx := context.Background()
+	if err := writeSQLite(ctx, dir, func(w dict.EntryWriter) error {
+		return parseRNA("testdata/deltas/rna_stock.csv", w)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	live := filepath.Join(dir, "data.db")
+	out := filepath.Join(dir, "new")
+	if err := ensureDir(out); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	if err := updateSQLite(ctx, live, out, func(w DeltaWriter) error { return a.ImportDelta(ctx, since, w) }); err != nil {
+		t.Fatalf("ImportDelta: %v", err)
+	}
+
+	got := filepath.Join(out, "data.db")
+	if _, ok := lookupTerm(t, got, "amis du louvre"); ok {
+		t.Error("a dissolved association must be removed")
+	}
+	if meta, _ := lookupTerm(t, got, "club de lyon"); meta["code_postal"] != "69002" {
+		t.Errorf("club de lyon = %v, want the updated address", meta)
+	}
+	if meta, _ := lookupTerm(t, got, "jardins de marseille"); meta["rna_id"] != "W131000003" {
+		t.Errorf("jardins de marseille = %v, want the new association", meta)
+	}
+
+	days = []string{"20261018"}
+	err := updateSQLite(ctx, live, out, func(w DeltaWriter) error { return a.ImportDelta(ctx, since, w) })
+	if !errors.Is(err, ErrDeltaChainBroken) {
+		t.Errorf("missing day: err = %v, want ErrDeltaChainBroken", err)
+	}
+}
+
+func TestGLEIFAdapter_ImportDelta(t *testing.T) {
+	published := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest" {
+			http.ServeFile(w, r, filepath.Join("testdata/deltas", filepath.Base(r.URL.Path)))
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"publish_date": %q, "delta_files": {
+			"LastDay": {"csv": {"url": "http://%[2]s/missing.csv"}},
+			"LastWeek": {"csv": {"url": "http://%[2]s/gleif_lastweek.csv"}}}}}`,
+			published.Format(time.DateTime), r.Host)
+	}))
+	defer ts.Close()
+	a := &gleifAdapter{deltaURL: ts.URL + "/latest"}
+	dir := t.TempDir()
+	ctx := context.Background()
+	if err := writeSQLite(ctx, dir, func(w dict.EntryWriter) error {
+		return parseGLEIF("testdata/deltas/gleif_stock.csv", w)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	live := filepath.Join(dir, "data.db")
+	out := filepath.Join(dir, "new")
+	if err := ensureDir(out); err != nil {
+		t.Fatal(err)
+	}
+	importSince := func(since time.Time) error {
+		return updateSQLite(ctx, live, out, func(w DeltaWriter) error { return a.ImportDelta(ctx, since, w) })
+	}
+
+	if err := importSince(published.AddDate(0, 0, -3)); err != nil {
+		t.Fatalf("ImportDelta: %v", err)
+	}
+	got := filepath.Join(out, "data.db")
+	for _, key := range []string{"969500x1y8g7la3q3b12", "acme sa"} {
+		if _, ok := lookupTerm(t, got, key); ok {
+			t.Errorf("%s: an inactive entity must be removed", key)
+		}
+	}
+	if meta, _ := lookupTerm(t, got, "globex corporation"); meta["country"] != "CA" {
+		t.Errorf("globex corporation = %v, want the updated country", meta)
+	}
+
+	if err := importSince(published.AddDate(0, -2, 0)); !errors.Is(err, ErrDeltaChainBroken) {
+		t.Errorf("two months: err = %v, want ErrDeltaChainBroken", err)
+	}
+	if err := importSince(published); !errors.Is(err, ErrNotModified) {
+		t.Errorf("up to date: err = %v, want ErrNotModified", err)
+	}
+}
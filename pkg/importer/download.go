@@ -0,0 +1,297 @@
+// CLAUDE:SUMMARY Resumable source downloads: HTTP Range resume across retries, ETag/Last-Modified conditional requests skipping unchanged sources, optional SHA-256 verification, progress callbacks and a record of every file downloaded for provenance.json, driven by a Download carried in the import context.
+// CLAUDE:DEPENDS pkg/importer/sourcedb.go
+// CLAUDE:EXPORTS Download, ProgressFunc, EntriesFunc, ErrNotModified, WithDownload
+
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// ErrNotModified is returned, wrapped, by Adapter.Import when the server
+// answered a conditional request with 304: the source is unchanged since the
+// last import and nothing was rebuilt.
+var ErrNotModified = errors.New("source not modified")
+
+// ProgressFunc receives download progress: bytes done so far and the total
+// size, -1 when the server does not announce it. It is called as each
+// attempt starts, with the bytes already on disk, then as the body arrives.
+type ProgressFunc func(url string, done, total int64)
+
+// EntriesFunc receives parse progress: the entries the parser has written so
+// far, and the distinct keys they stored. It is called periodically while
+// the dictionary is written, then once when parsing ends.
+type EntriesFunc func(written, keys int)
+
+// Download is the download state of one import. The conditional-request
+// validators and the expected digest apply to the download of URL only;
+// Progress applies to every download of the import and Entries to its
+// dictionary writes. After a successful
+// download of URL, ETag and LastModified hold the server's new validators.
+type Download struct {
+	URL          string
+	ETag         string       // sent as If-None-Match
+	LastModified string       // sent as If-Modified-Since
+	SHA256       string       // expected hex digest; empty skips verification
+	Progress     ProgressFunc // nil reports nothing
+	Entries      EntriesFunc  // nil reports nothing
+	Gates        *Gates       // checked before the new data.db is published; nil checks nothing
+
+	liveDir string // where Run publishes the staged build; see writeSQLite
+
+	mu        sync.Mutex
+	downloads []dict.SourceDownload     // every file downloaded, for provenance.json
+	rows      map[string]dict.RowCounts // dictionary ID → row counts of its data.db
+	totals    map[string]dict.Totals    // dictionary ID → metadata totals of its data.db
+}
+
+// record notes a finished download of the import.
+func (d *Download) record(sd dict.SourceDownload) {
+	d.mu.Lock()
+	d.downloads = append(d.downloads, sd)
+	d.mu.Unlock()
+}
+
+// resetRecord forgets the downloads, row counts and totals recorded so far.
+func (d *Download) resetRecord() {
+	d.mu.Lock()
+	d.downloads, d.rows, d.totals = nil, nil, nil
+	d.mu.Unlock()
+}
+
+// recordBuild notes the row counts and metadata totals of the data.db of
+// dictionary dictID.
+func (d *Download) recordBuild(dictID string, rc dict.RowCounts, t dict.Totals) {
+	d.mu.Lock()
+	if d.rows == nil {
+		d.rows = make(map[string]dict.RowCounts)
+		d.totals = make(map[string]dict.Totals)
+	}
+	d.rows[dictID] = rc
+	d.totals[dictID] = t
+	d.mu.Unlock()
+}
+
+// retryBackoff is the base delay between download attempts, doubled on
+// each retry.
+var retryBackoff = time.Second
+
+type downloadKey struct{}
+
+// WithDownload returns ctx carrying d for the downloads of Adapter.Import.
+func WithDownload(ctx context.Context, d *Download) context.Context {
+	return context.WithValue(ctx, downloadKey{}, d)
+}
+
+// downloadFrom returns the download state of ctx for url, and the progress
+// callback of ctx, either of which may be nil.
+func downloadFrom(ctx context.Context, url string) (*Download, ProgressFunc) {
+	d, _ := ctx.Value(downloadKey{}).(*Download)
+	if d == nil {
+		return nil, nil
+	}
+	if d.URL != url {
+		return nil, d.Progress
+	}
+	return d, d.Progress
+}
+
+// downloadFile downloads url to dest with retries and timeout. The body is
+// written to dest.part, and a retry resumes it with a Range request guarded
+// by If-Range, so a dropped connection never restarts a large download from
+// zero. dest is renamed into place once complete and verified.
+func downloadFile(ctx context.Context, url, dest string) error {
+	client := &http.Client{Timeout: 30 * time.Minute}
+	state, progress := downloadFrom(ctx, url)
+	rec, _ := ctx.Value(downloadKey{}).(*Download) // records every download of the import
+	part := dest + ".part"
+	_ = os.Remove(part)
+
+	var validator string // If-Range value of the partial body
+	var lastErr error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt)) * retryBackoff
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("User-Agent", "touchstone-registry/1.0 (open-data-import)")
+
+		var offset int64
+		if fi, err := os.Stat(part); err == nil && validator != "" {
+			offset = fi.Size()
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", validator)
+		} else if state != nil {
+			if state.ETag != "" {
+				req.Header.Set("If-None-Match", state.ETag)
+			}
+			if state.LastModified != "" {
+				req.Header.Set("If-Modified-Since", state.LastModified)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && offset == 0:
+			resp.Body.Close()
+			return ErrNotModified
+		case resp.StatusCode == http.StatusPartialContent && offset > 0 && rangeStart(resp) == offset:
+			// Resume: append to the partial body.
+		case resp.StatusCode == http.StatusOK:
+			offset = 0
+		default:
+			resp.Body.Close()
+			_ = os.Remove(part)
+			validator = ""
+			lastErr = fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
+			continue
+		}
+		if offset == 0 {
+			validator = resp.Header.Get("ETag")
+			if validator == "" || strings.HasPrefix(validator, "W/") {
+				// If-Range needs a strong validator.
+				validator = resp.Header.Get("Last-Modified")
+			}
+		}
+
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if offset > 0 {
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(part, flags, 0o644)
+		if err != nil {
+			resp.Body.Close()
+			return fmt.Errorf("create file: %w", err)
+		}
+
+		total := int64(-1)
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+		var w io.Writer = f
+		if progress != nil {
+			progress(url, offset, total)
+			w = &progressWriter{w: f, url: url, done: offset, total: total, fn: progress}
+		}
+		_, copyErr := io.Copy(w, resp.Body)
+		resp.Body.Close()
+		closeErr := f.Close()
+
+		if copyErr != nil {
+			lastErr = copyErr
+			continue
+		}
+		if closeErr != nil {
+			return closeErr
+		}
+
+		var sum string
+		var size int64
+		if rec != nil || (state != nil && state.SHA256 != "") {
+			if sum, size, err = fileSHA256(part); err != nil {
+				_ = os.Remove(part)
+				return fmt.Errorf("download %s: %w", url, err)
+			}
+		}
+		if state != nil && state.SHA256 != "" && !strings.EqualFold(sum, state.SHA256) {
+			_ = os.Remove(part)
+			return fmt.Errorf("download %s: SHA-256 mismatch: got %s, want %s", url, sum, state.SHA256)
+		}
+		if err := os.Rename(part, dest); err != nil {
+			return fmt.Errorf("rename download: %w", err)
+		}
+		if state != nil {
+			state.ETag = resp.Header.Get("ETag")
+			state.LastModified = resp.Header.Get("Last-Modified")
+		}
+		if rec != nil {
+			rec.record(dict.SourceDownload{
+				URL:          url,
+				ResolvedURL:  resp.Request.URL.String(),
+				SHA256:       sum,
+				LastModified: resp.Header.Get("Last-Modified"),
+				Bytes:        size,
+			})
+		}
+		return nil
+	}
+	_ = os.Remove(part)
+	return fmt.Errorf("download %s failed after 3 attempts: %w", url, lastErr)
+}
+
+// rangeStart returns the first byte position of a 206 response's
+// Content-Range, or -1.
+func rangeStart(resp *http.Response) int64 {
+	cr, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(cr, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// fileSHA256 returns the hex SHA-256 and the size of the file at path.
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("hash download: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// progressWriter reports the bytes written through it.
+type progressWriter struct {
+	w           io.Writer
+	url         string
+	done, total int64
+	fn          ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.fn(p.url, p.done, p.total)
+	return n, err
+}
@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer serves content with an ETag, dropping the connection halfway
+// through the first drops responses.
+func flakyServer(t *testing.T, content []byte, drops int) (*httptest.Server, *[]string) {
+	t.Helper()
+	prev := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = prev })
+
+	var mu sync.Mutex
+	var ranges []string
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		n := len(ranges)
+		mu.Unlock()
+
+		w.Header().Set("ETag", `"v1"`)
+		if n <= drops {
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			start := 0
+			if rng := r.Header.Get("Range"); rng != "" {
+				fmt.Sscanf(rng, "bytes=%d-", &start)
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+				w.Header().Set("Content-Length", fmt.Sprint(len(content)-start))
+				w.WriteHeader(http.StatusPartialContent)
+			}
+			_, _ = w.Write(content[start : start+(len(content)-start)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "data.csv", modTime, bytes.NewReader(content))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &ranges
+}
+
+func TestDownloadFile_Resume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	ts, ranges := flakyServer(t, content, 2)
+
+	dest := filepath.Join(t.TempDir(), "data.csv")
+	if err := downloadFile(context.Background(), ts.URL, dest); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("content: got %d bytes, want %d", len(got), len(content))
+	}
+	if len(*ranges) != 3 || (*ranges)[0] != "" || (*ranges)[1] != "bytes=50000-" || (*ranges)[2] != "bytes=75000-" {
+		t.Errorf("Range headers = %q, want resumes from 50000 then 75000", *ranges)
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Error("partial file left behind")
+	}
+}
+
+func TestDownloadFile_NotModified(t *testing.T) {
+	content := []byte("code;label\n")
+	ts, _ := flakyServer(t, content, 0)
+	dest := filepath.Join(t.TempDir(), "data.csv")
+
+	d := &Download{URL: ts.URL}
+	ctx := WithDownload(context.Background(), d)
+	if err := downloadFile(ctx, ts.URL, dest); err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	if d.ETag != `"v1"` || d.LastModified == "" {
+		t.Errorf("validators = %q, %q", d.ETag, d.LastModified)
+	}
+
+	_ = os.Remove(dest)
+	if err := downloadFile(ctx, ts.URL, dest); !errors.Is(err, ErrNotModified) {
+		t.Errorf("second download: err = %v, want ErrNotModified", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("unchanged source must not be written")
+	}
+}
+
+func TestDownloadFile_SHA256(t *testing.T) {
+	content := []byte("code;label\n01;Ain\n")
+	ts, _ := flakyServer(t, content, 0)
+	sum := sha256.Sum256(content)
+	dir := t.TempDir()
+
+	ctx := WithDownload(context.Background(), &Download{URL: ts.URL, SHA256: strings.ToUpper(hex.EncodeToString(sum[:]))})
+	if err := downloadFile(ctx, ts.URL, filepath.Join(dir, "ok.csv")); err != nil {
+		t.Fatalf("matching digest: %v", err)
+	}
+
+	ctx = WithDownload(context.Background(), &Download{URL: ts.URL, SHA256: strings.Repeat("0", 64)})
+	err := downloadFile(ctx, ts.URL, filepath.Join(dir, "bad.csv"))
+	if err == nil || !strings.Contains(err.Error(), "SHA-256 mismatch") {
+		t.Errorf("mismatching digest: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.csv")); !os.IsNotExist(err) {
+		t.Error("corrupt download must not be kept")
+	}
+}
+
+func TestDownloadFile_Progress(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 100000)
+	ts, _ := flakyServer(t, content, 1)
+
+	var calls int
+	var last, total int64
+	ctx := WithDownload(context.Background(), &Download{Progress: func(_ string, done, tot int64) {
+		calls++
+		last, total = done, tot
+	}})
+	if err := downloadFile(ctx, ts.URL, filepath.Join(t.TempDir(), "x")); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	if calls < 2 || last != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("progress: %d calls, last = %d/%d", calls, last, total)
+	}
+}
+
+// fileAdapter imports a single downloaded file as its dictionary manifest.
+type fileAdapter struct{}
+
+func (fileAdapter) ID() string          { return "test-file" }
+func (fileAdapter) DictID() string      { return "test-file" }
+func (fileAdapter) Description() string { return "test" }
+func (fileAdapter) DefaultURL() string  { return "" }
+func (fileAdapter) License() string     { return "CC0" }
+func (a fileAdapter) Import(ctx context.Context, sourceURL, outputDir string) error {
+	dictDir := filepath.Join(outputDir, a.DictID())
+	if err := ensureDir(dictDir); err != nil {
+		return err
+	}
+	if err := downloadFile(ctx, sourceURL, filepath.Join(dictDir, "data.csv")); err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dictDir, "manifest.yaml"), []byte("id: test-file\n"), 0o644)
+}
+
+func TestRun_SkipsUnchanged(t *testing.T) {
+	ts, _ := flakyServer(t, []byte("a;b\n"), 0)
+	dir := t.TempDir()
+	sdb, err := OpenSourceDB(filepath.Join(dir, "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+	if err := sdb.Seed([]Adapter{fileAdapter{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.SetURL("test-file", ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Run(context.Background(), sdb, fileAdapter{}, dir, RunOptions{}); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if err := Run(context.Background(), sdb, fileAdapter{}, dir, RunOptions{}); !errors.Is(err, ErrNotModified) {
+		t.Errorf("second run: err = %v, want ErrNotModified", err)
+	}
+	if err := Run(context.Background(), sdb, fileAdapter{}, dir, RunOptions{Force: true}); err != nil {
+		t.Errorf("forced run: %v", err)
+	}
+
+	if err := sdb.SetSHA256("test-file", strings.Repeat("ab", 32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(context.Background(), sdb, fileAdapter{}, dir, RunOptions{Force: true}); err == nil {
+		t.Error("expected SHA-256 mismatch")
+	}
+	d, _ := sdb.GetDownload("test-file")
+	if d.SHA256 != strings.Repeat("ab", 32) || d.ETag != `"v1"` {
+		t.Errorf("download state = %+v", d)
+	}
+}
@@ -0,0 +1,576 @@
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// refreshFixtures replaces the recorded sources of testdata/fixtures with
+// samples of the real ones before checking them:
+//
+//	go test ./pkg/importer -run TestAdapterFixtures -refresh
+var refreshFixtures = flag.Bool("refresh", false, "download the adapter fixtures from their real sources (needs network)")
+
+// fixtureLines is the number of leading lines -refresh keeps of each source
+// file, header included.
+const fixtureLines = 10
+
+// adapterFixture is the recorded miniature source of an adapter, in
+// testdata/fixtures/<id>/, and what its import must hold.
+type adapterFixture struct {
+	id      string // adapter ID, also the fixture directory
+	archive string // how the source serves the fixture files: "zip", "gzip", "" for a plain file, "static" for none
+	entries string // -refresh: archive entries to keep, default all
+	keep    []string
+	// api, when set, is the JSON served at /<id>/api and given to the
+	// adapter as its URL, %s standing for the URL of the archive; resolve
+	// finds the archive URL from the real API for -refresh.
+	api     string
+	resolve func(ctx context.Context, apiURL string) (string, error)
+
+	found  map[string]map[string]string // term → metadata it must carry
+	absent []string                     // terms the import must filter out
+}
+
+// adapterFixtures covers every registered adapter. keep lists, for
+// -refresh, source substrings whose lines are kept besides the first ones,
+// so that the lookups below still find their terms in a fresh sample.
+// Fixtures keep the encoding of their source: ansm-medicaments and finess
+// are Windows-1252.
+var adapterFixtures = []adapterFixture{
+	{id: "insee-arrondissements", keep: []string{",75101,", ",75120,"},
+		found: map[string]map[string]string{
+			"Paris 1er Arrondissement": {"code": "75101", "type": "arrondissement"},
+			"75120":                    {"name": "Paris 20e Arrondissement"},
+		},
+		absent: []string{"Paris", "Arbignieu"}},
+	{id: "who-atc", keep: []string{"N02BE01,", "C10AA05,"},
+		found: map[string]map[string]string{
+			"N02BE01":      {"name": "paracetamol", "ddd": "3", "uom": "g"},
+			"Atorvastatin": {"atc_code": "C10AA05"},
+		}},
+	{id: "ban-addresses", archive: "gzip", keep: []string{"75107_9474_00001;", "13202_7781_00012;"},
+		found: map[string]map[string]string{
+			"Rue de l'Université":                  {"code_postal": "75007", "commune": "Paris", "code_commune": "75107"},
+			"Rue de la République 13002 Marseille": {"code_commune": "13202"},
+		}},
+	{id: "insee-cog-pays", keep: []string{"99109,", "99132,"},
+		found: map[string]map[string]string{
+			"Allemagne": {"cog": "99109", "iso2": "DE"},
+			"99132":     {"iso3": "GBR"},
+		},
+		absent: []string{"Tchécoslovaquie"}},
+	{id: "corp-jurisdictions", archive: "static",
+		found: map[string]map[string]string{
+			"fr":             {"registry": "SIRENE / RNE (INPI)"},
+			"United Kingdom": {"code": "gb"},
+		}},
+	{id: "courts-fr", archive: "static",
+		found: map[string]map[string]string{
+			"Cour de cassation":   {"type": "cour_cassation"},
+			"Cour d'appel d'Agen": {"city": "Agen"},
+		}},
+	{id: "eba-credit-institutions", keep: []string{"R0MUWSFPU8MPRO8K5P83", "7LTWFZYICNSX8D621K86"},
+		found: map[string]map[string]string{
+			"BNP Paribas":          {"lei": "R0MUWSFPU8MPRO8K5P83", "country": "FR"},
+			"7LTWFZYICNSX8D621K86": {"name": "Deutsche Bank AG"},
+			"ING Bank N.V.":        {"country": "NL"},
+		}},
+	{id: "eu-institutions", archive: "static",
+		found: map[string]map[string]string{
+			"Commission européenne": {"abbr": "EC"},
+			"EC":                    {"name": "European Commission"},
+		}},
+	{id: "finess", keep: []string{";750000010;", ";690000880;"},
+		found: map[string]map[string]string{
+			"750000010":                   {"category_code": "101", "category": "Centre Hospitalier Régional (C.H.R.)", "dept": "75", "commune": "75010 PARIS"},
+			"Hôpital Edouard Herriot HCL": {"finess": "690000880"},
+		}},
+	{id: "geonames-firstnames", keep: []string{"Giulia,"},
+		found: map[string]map[string]string{
+			"Giulia": {"gender": "F", "country": "IT"},
+			"Jean":   {"country": "FR"},
+		},
+		absent: []string{"X"}},
+	{id: "gleif-lei", archive: "zip", keep: []string{"O2RNE8IBXP4R0TD8PU41", "7LTWFZYICNSX8D621K86"},
+		api:     `{"data": {"publish_date": "2026-10-18 08:00:00", "full_file": {"csv": {"url": %q}}}}`,
+		resolve: resolveGLEIFURL,
+		found: map[string]map[string]string{
+			"Société Générale":     {"lei": "O2RNE8IBXP4R0TD8PU41", "country": "FR"},
+			"7LTWFZYICNSX8D621K86": {"jurisdiction": "DE"},
+		},
+		absent: []string{"Lehman Brothers Equity Finance", "549300WCNBXGSVWH2H68"}},
+	{id: "honorifics", archive: "static",
+		found: map[string]map[string]string{
+			"Mme":    {"gender": "F", "lang": "fr"},
+			"Maître": {"category": "legal"},
+		}},
+	{id: "iana-tld",
+		found: map[string]map[string]string{
+			"FR":       {"tld": "fr"},
+			".com":     {"tld": "com"},
+			"xn--qxam": {"tld": "xn--qxam"},
+		}},
+	{id: "icd10", keep: []string{"J45.0;", "E11.9;"},
+		found: map[string]map[string]string{
+			"J45.0": {"label": "Asthme à prédominance allergique", "family": "J45", "chapter": "Maladies de l'appareil respiratoire"},
+			"E119":  {"code": "E11.9"},
+			"Diabète sucré de type 2, sans complication": {"code": "E11.9"},
+		},
+		absent: []string{"(J45-J46)"}},
+	{id: "insee-communes-fr", keep: []string{",01001,", ",33063,"},
+		found: map[string]map[string]string{
+			"L'Abergement-Clémenciat": {"departement": "01", "code_commune": "01001"},
+			"Bordeaux":                {"code_commune": "33063"},
+		},
+		absent: []string{"Arbignieu", "Paris 1er Arrondissement"}},
+	{id: "insee-patronymes-fr", archive: "zip", keep: []string{"MARTIN\t", "DUPONT\t"},
+		found: map[string]map[string]string{
+			"Dupont": {"frequency": "92424"},
+			"Martin": {"frequency": "235846"},
+		}},
+	{id: "insee-prenoms-fr", archive: "zip", keep: []string{";ÉLODIE;198", ";JEAN;1950;"},
+		found: map[string]map[string]string{
+			"Élodie": {"sexe": "2", "frequency": "9993"},
+			"Jean":   {"sexe": "1"},
+		},
+		absent: []string{"_PRENOMS_RARES"}},
+	{id: "isbn-groups", archive: "static",
+		found: map[string]map[string]string{
+			"978-2": {"name": "French language", "country": "FR"},
+			"9782":  {"prefix": "978-2"},
+		}},
+	{id: "iso-3166-countries", keep: []string{",CI,CIV,", ",KR,KOR,"},
+		found: map[string]map[string]string{
+			"Côte d'Ivoire":      {"alpha2": "CI", "numeric": "384"},
+			"KOR":                {"alpha2": "KR"},
+			"Korea, Republic of": {"region": "Asia"},
+		}},
+	{id: "iso-4217-currencies", keep: []string{",EUR,", ",CHF,"},
+		found: map[string]map[string]string{
+			"EUR":         {"name": "Euro", "entity": "FRANCE", "numeric": "978"},
+			"Swiss Franc": {"code": "CHF"},
+		}},
+	{id: "eurostat-lau", keep: []string{"FR_75056,", "DE_11000000,"},
+		found: map[string]map[string]string{
+			"75056":  {"name": "Paris", "country": "FR", "population": "2113705"},
+			"Berlin": {"lau_code": "11000000"},
+		}},
+	{id: "insee-legal-forms", keep: []string{"5710\t", "9220\t"},
+		found: map[string]map[string]string{
+			"5710":                 {"label": "SAS, société par actions simplifiée"},
+			"Association déclarée": {"code": "9220"},
+		}},
+	{id: "mcc-codes", keep: []string{"5411,", "4111,"},
+		found: map[string]map[string]string{
+			"5411":                        {"description": "Grocery Stores, Supermarkets", "irs_reportable": "No1.6041(c)"},
+			"Commuter Transport, Ferries": {"code": "4111"},
+		}},
+	{id: "ansm-medicaments", keep: []string{"60234100\t"},
+		found: map[string]map[string]string{
+			"60234100":          {"denomination": "DOLIPRANE 1000 mg, comprimé", "forme": "comprimé", "titulaire": "SANOFI AVENTIS FRANCE"},
+			"Doliprane 1000 mg": {"cis": "60234100"},
+		}},
+	{id: "europarl-meps", keep: []string{"197490,", "124831,"},
+		found: map[string]map[string]string{
+			"Manon Aubry": {"country": "FR", "group": "The Left"},
+			"Metsola":     {"given": "Roberta"},
+		}},
+	{id: "insee-naf-fr", keep: []string{"62.01Z;", "01.11Z;"},
+		found: map[string]map[string]string{
+			"62.01Z":                     {"label": "Programmation informatique", "section": "J"},
+			"62.01":                      {"code": "62.01Z"},
+			"Programmation informatique": {"code": "62.01Z"},
+			"01.11Z":                     {"section": "A"},
+		}},
+	{id: "eurostat-nuts", keep: []string{"FR101;", "FR1;"},
+		found: map[string]map[string]string{
+			"FR101":         {"name": "Paris", "level": "3", "parent": "FR10"},
+			"Ile-de-France": {"nuts_code": "FR1"},
+		}},
+	{id: "ourairports-world", keep: []string{`"LFPG"`, `"LFPB"`},
+		found: map[string]map[string]string{
+			"CDG":  {"icao": "LFPG", "city": "Paris (Roissy-en-France, Val-d'Oise)"},
+			"LFPB": {"iata": "LBG"},
+			"Charles de Gaulle International Airport": {"country": "FR", "type": "large_airport"},
+		},
+		absent: []string{"Ancien aérodrome de Issy-les-Moulineaux", "FR-0001"}},
+	{id: "laposte-postcodes-fr", keep: []string{"01001,", "33063,"},
+		found: map[string]map[string]string{
+			"33000":                   {"commune": "BORDEAUX", "code_commune": "33063"},
+			"L Abergement Clemenciat": {"postcode": "01400"},
+		}},
+	{id: "rna-associations-fr", archive: "zip", entries: "*_dpt_75.csv", keep: []string{"W751000001;"},
+		found: map[string]map[string]string{
+			"Amis du Louvre": {"rna_id": "W751000001", "code_postal": "75001", "commune": "Paris"},
+		},
+		absent: []string{"Club dissous"}},
+	{id: "inpi-rne", keep: []string{"552100554,"},
+		found: map[string]map[string]string{
+			"552100554": {"name": "DANONE", "naf": "70.10Z", "forme": "5599", "cp": "75009"},
+			"Danone":    {"siren": "552100554"},
+		}},
+	{id: "rpps", keep: []string{"|810001234567|", "|810009876543|"},
+		found: map[string]map[string]string{
+			"810001234567":  {"nom": "DURAND", "prenom": "Claire", "profession": "Médecin", "specialite": "Médecine générale"},
+			"Durand Claire": {"rpps": "810001234567"},
+			"Leroy":         {"profession": "Pharmacien"},
+		}},
+	{id: "sirene-fr", archive: "zip", keep: []string{"552032534,", "130025265,"},
+		found: map[string]map[string]string{
+			"Danone": {"siren": "552032534"},
+			"Direction interministérielle du numérique": {"siren": "130025265"},
+		},
+		absent: []string{"Ancienne SARL"}},
+	{id: "ssa-babynames-us", archive: "zip", entries: "yob202[23].txt", keep: []string{"Olivia,F,", "Avery,"},
+		found: map[string]map[string]string{
+			"Olivia": {"frequency": "31843", "sex": "F"},
+			"Avery":  {"frequency": "7900"},
+		}},
+	{id: "unlocode", keep: []string{",FR,PAR,", ",DE,MUC,"},
+		found: map[string]map[string]string{
+			"FRPAR":   {"country": "FR", "subdivision": "75", "function": "1-345---"},
+			"München": {"locode": "DEMUC"},
+		},
+		absent: []string{".FRANCE"}},
+
+	// Built-in declarative specs.
+	{id: "census-surnames-us", archive: "zip", entries: "*.csv", keep: []string{"SMITH,", "GARCIA,"},
+		found: map[string]map[string]string{
+			"Smith":  {"rank": "1", "frequency": "2442977"},
+			"Garcia": {"rank": "6"},
+		}},
+	{id: "companies-house-uk", archive: "zip", keep: []string{`"00445790"`},
+		found: map[string]map[string]string{
+			"Tesco PLC": {"company_number": "00445790"},
+		},
+		absent: []string{"Woolworths Group PLC"}},
+	{id: "geonames-postcodes", archive: "zip", entries: "allCountries.txt", keep: []string{"FR\t75007\t", "DE\t10115\t"},
+		found: map[string]map[string]string{
+			"FR-75007": {"place": "Paris 07"},
+			"10115":    {"country": "DE"},
+		}},
+	{id: "insee-cog-departements", keep: []string{"2A,", "75,"},
+		found: map[string]map[string]string{
+			"Corse-du-Sud": {"code": "2A", "region": "94"},
+			"2A":           {"chef_lieu": "2A004"},
+		}},
+}
+
+// fixtureServer serves testdata/fixtures: /<id> is the fixture directory of
+// an adapter as its source serves it, and /<id>/api its api JSON.
+func fixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	byID := make(map[string]adapterFixture, len(adapterFixtures))
+	for _, fx := range adapterFixtures {
+		byID[fx.id] = fx
+	}
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, api := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/api")
+		fx, ok := byID[id]
+		if !ok || (api && fx.api == "") {
+			http.NotFound(w, r)
+			return
+		}
+		if api {
+			fmt.Fprintf(w, fx.api, ts.URL+"/"+id)
+			return
+		}
+		if err := serveFixture(w, fx); err != nil {
+			t.Errorf("serve %s: %v", id, err)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// serveFixture writes the files of fx zipped, gzipped or as is.
+func serveFixture(w io.Writer, fx adapterFixture) error {
+	dir := filepath.Join("testdata/fixtures", fx.id)
+	names, err := fixtureFiles(dir)
+	if err != nil {
+		return err
+	}
+	switch fx.archive {
+	case "zip":
+		zw := zip.NewWriter(w)
+		for _, name := range names {
+			b, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			f, err := zw.Create(name)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(b); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	case "gzip":
+		b, err := os.ReadFile(filepath.Join(dir, names[0]))
+		if err != nil {
+			return err
+		}
+		gw := gzip.NewWriter(w)
+		if _, err := gw.Write(b); err != nil {
+			return err
+		}
+		return gw.Close()
+	}
+	b, err := os.ReadFile(filepath.Join(dir, names[0]))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// fixtureFiles lists the files of a fixture directory, sorted.
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no fixture file in %s", dir)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func TestAdapterFixtures(t *testing.T) {
+	covered := make(map[string]bool, len(adapterFixtures))
+	for _, fx := range adapterFixtures {
+		covered[fx.id] = true
+	}
+	for _, a := range All() {
+		if !covered[a.ID()] {
+			t.Errorf("adapter %s has no fixture in adapterFixtures", a.ID())
+		}
+	}
+
+	ts := fixtureServer(t)
+	ctx := context.Background()
+	for _, fx := range adapterFixtures {
+		t.Run(fx.id, func(t *testing.T) {
+			a, err := Get(fx.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sourceURL := ts.URL + "/" + fx.id
+			switch {
+			case fx.archive == "static":
+				sourceURL = a.DefaultURL()
+			case *refreshFixtures:
+				if err := refreshFixture(ctx, a, fx); err != nil {
+					t.Fatalf("refresh: %v", err)
+				}
+			}
+			if fx.api != "" {
+				sourceURL += "/api"
+			}
+
+			out := t.TempDir()
+			if err := a.Import(ctx, sourceURL, out); err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			d, err := dict.LoadDictionary(filepath.Join(out, a.DictID()))
+			if err != nil {
+				t.Fatalf("LoadDictionary: %v", err)
+			}
+			defer d.Close()
+			if d.EntryCount() == 0 {
+				t.Fatal("empty dictionary")
+			}
+			for term, want := range fx.found {
+				e, ok := d.Lookup(term)
+				if !ok {
+					t.Errorf("%q not found", term)
+					continue
+				}
+				for k, v := range want {
+					if e.Metadata[k] != v {
+						t.Errorf("%q: %s = %q, want %q", term, k, e.Metadata[k], v)
+					}
+				}
+			}
+			for _, term := range fx.absent {
+				if _, ok := d.Lookup(term); ok {
+					t.Errorf("%q must be filtered out", term)
+				}
+			}
+		})
+	}
+}
+
+// refreshFixture replaces the fixture of a with a sample of its real
+// source: the first fixtureLines lines of every file, and the lines holding
+// one of fx.keep, byte for byte.
+func refreshFixture(ctx context.Context, a Adapter, fx adapterFixture) error {
+	url := a.DefaultURL()
+	if fx.resolve != nil {
+		var err error
+		if url, err = fx.resolve(ctx, url); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.MkdirTemp("", "fixture-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	src := filepath.Join(tmp, "source")
+	if err := downloadFile(ctx, url, src); err != nil {
+		return err
+	}
+
+	dir := filepath.Join("testdata/fixtures", fx.id)
+	name := "source.csv"
+	if names, err := fixtureFiles(dir); err == nil {
+		name = names[0]
+	}
+	// The sample is built next to the fixture, so that it replaces it by a
+	// rename on the same file system.
+	sample, err := os.MkdirTemp("testdata/fixtures", "."+fx.id+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(sample)
+	if err := os.Chmod(sample, 0o755); err != nil {
+		return err
+	}
+
+	switch fx.archive {
+	case "zip":
+		zr, err := zip.OpenReader(src)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			base := path.Base(f.Name)
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			if ok, _ := path.Match(fx.entries, base); fx.entries != "" && !ok {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = sampleLines(rc, filepath.Join(sample, base), fx.keep)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	case "gzip":
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		gr, err := gzip.NewReader(in)
+		if err != nil {
+			return err
+		}
+		if err := sampleLines(gr, filepath.Join(sample, name), fx.keep); err != nil {
+			return err
+		}
+	default:
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		if err := sampleLines(in, filepath.Join(sample, name), fx.keep); err != nil {
+			return err
+		}
+	}
+
+	return replaceDir(dir, sample)
+}
+
+// replaceDir replaces dir with next, a directory of the same parent. The
+// old dir is moved aside and removed only once next is in place, and is
+// restored when next cannot be moved in.
+func replaceDir(dir, next string) error {
+	old := next + ".old"
+	if err := os.Rename(dir, old); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Rename(next, dir); err != nil {
+		if rerr := os.Rename(old, dir); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+			return fmt.Errorf("%w (restore %s: %v)", err, dir, rerr)
+		}
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// sampleLines copies to dest the first fixtureLines lines of r and the
+// later ones containing one of keep.
+func sampleLines(r io.Reader, dest string, keep []string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(out)
+	for n := 0; ; n++ {
+		line, err := br.ReadString('\n')
+		if line != "" && (n < fixtureLines || containsAny(line, keep)) {
+			if _, werr := bw.WriteString(line); werr != nil {
+				out.Close()
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			out.Close()
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+// CLAUDE:SUMMARY Post-import quality gates (minimum entries, maximum drop vs the previous build, required keys, metadata fill rates) checked on a new data.db before it replaces the previous one.
+// CLAUDE:DEPENDS pkg/dict/writer.go
+// CLAUDE:EXPORTS Gates, ErrGateFailed
+
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrGateFailed is returned, wrapped, when a new build fails its quality
+// gates. The previous dictionary is left in place.
+var ErrGateFailed = errors.New("quality gate failed")
+
+// Gates are the checks a new build must pass before it replaces the
+// previous dictionary. Zero fields check nothing.
+type Gates struct {
+	MinEntries   int                `json:"min_entries,omitempty"`
+	MaxDrop      float64            `json:"max_drop,omitempty"`      // largest allowed entry-count drop vs the previous build, 0–1
+	RequiredKeys []string           `json:"required_keys,omitempty"` // keys as stored, i.e. normalized
+	MinFillRate  map[string]float64 `json:"min_fill_rate,omitempty"` // metadata field → smallest share of entries where it is set, 0–1
+}
+
+// Validate reports gates that can never pass.
+func (g *Gates) Validate() error {
+	if g.MinEntries < 0 {
+		return fmt.Errorf("min_entries %d is negative", g.MinEntries)
+	}
+	if g.MaxDrop < 0 || g.MaxDrop > 1 {
+		return fmt.Errorf("max_drop %v is not between 0 and 1", g.MaxDrop)
+	}
+	for field, rate := range g.MinFillRate {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("min_fill_rate of %q: %v is not between 0 and 1", field, rate)
+		}
+	}
+	return nil
+}
+
+// check runs the gates against db, the build about to replace the data.db
+// at prevPath, and returns every failure.
+func (g *Gates) check(db *sql.DB, prevPath string) error {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM terms`).Scan(&count); err != nil {
+		return fmt.Errorf("count entries: %w", err)
+	}
+
+	var failures []string
+	if count < g.MinEntries {
+		failures = append(failures, fmt.Sprintf("%d entries, want at least %d", count, g.MinEntries))
+	}
+	if g.MaxDrop > 0 {
+		prev, err := countTerms(prevPath)
+		if err != nil {
+			return err
+		}
+		if prev > 0 && float64(prev-count)/float64(prev) > g.MaxDrop {
+			failures = append(failures, fmt.Sprintf("%d entries, down from %d (more than %.0f%%)", count, prev, g.MaxDrop*100))
+		}
+	}
+	for _, key := range g.RequiredKeys {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM terms WHERE key = ?`, key).Scan(&n); err != nil {
+			return fmt.Errorf("lookup %q: %w", key, err)
+		}
+		if n == 0 {
+			failures = append(failures, fmt.Sprintf("required key %q missing", key))
+		}
+	}
+	fields := make([]string, 0, len(g.MinFillRate))
+	for field := range g.MinFillRate {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		var set int
+		err := db.QueryRow(`SELECT COUNT(*) FROM terms
+			WHERE metadata != '' AND COALESCE(json_extract(metadata, '$."' || ? || '"'), '') != ''`, field).Scan(&set)
+		if err != nil {
+			return fmt.Errorf("fill rate of %q: %w", field, err)
+		}
+		if rate := fillRate(set, count); rate < g.MinFillRate[field] {
+			failures = append(failures, fmt.Sprintf("%q set in %.1f%% of entries, want at least %.1f%%", field, rate*100, g.MinFillRate[field]*100))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrGateFailed, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func fillRate(set, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(set) / float64(count)
+}
+
+// countTerms returns the number of entries of the data.db at path, 0 when
+// there is none.
+func countTerms(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, nil
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open previous build: %w", err)
+	}
+	defer db.Close()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM terms`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count previous entries: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// fillNames writes n entries name0…name<n-1>, the first withCode of them with
+// a "code" metadata field.
+func fillNames(n, withCode int) func(w dict.EntryWriter) error {
+	return func(w dict.EntryWriter) error {
+		for i := range n {
+			e := &dict.Entry{Metadata: map[string]string{"sexe": "F"}}
+			if i < withCode {
+				e.Metadata["code"] = strconv.Itoa(i)
+			}
+			if err := w.Put("name"+strconv.Itoa(i), e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestGates(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeSQLite(context.Background(), dir, fillNames(100, 100)); err != nil {
+		t.Fatal(err)
+	}
+	gated := func(g *Gates) context.Context {
+		return WithDownload(context.Background(), &Download{Gates: g})
+	}
+
+	for name, tc := range map[string]struct {
+		gates *Gates
+		n     int
+		want  string // failure message; empty passes
+	}{
+		"min entries":      {&Gates{MinEntries: 50}, 40, "40 entries, want at least 50"},
+		"drop":             {&Gates{MaxDrop: 0.2}, 70, "down from 100"},
+		"small drop":       {&Gates{MaxDrop: 0.2}, 85, ""},
+		"required key":     {&Gates{RequiredKeys: []string{"name1", "martin"}}, 100, `required key "martin" missing`},
+		"fill rate":        {&Gates{MinFillRate: map[string]float64{"code": 0.9}}, 100, `"code" set in 50.0% of entries`},
+		"fill rate passes": {&Gates{MinFillRate: map[string]float64{"sexe": 1}}, 100, ""},
+	} {
+		err := writeSQLite(gated(tc.gates), dir, fillNames(tc.n, 50))
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+			// Restore the 100-entry build for the next cases.
+			if err := writeSQLite(context.Background(), dir, fillNames(100, 100)); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrGateFailed) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", name, err, tc.want)
+		}
+		if n, _ := countTerms(filepath.Join(dir, "data.db")); n != 100 {
+			t.Errorf("%s: previous build replaced (%d entries)", name, n)
+		}
+	}
+}
+
+func TestGates_Validate(t *testing.T) {
+	for _, g := range []Gates{
+		{MinEntries: -1},
+		{MaxDrop: 1.5},
+		{MinFillRate: map[string]float64{"code": 2}},
+	} {
+		if err := g.Validate(); err == nil {
+			t.Errorf("%+v: expected error", g)
+		}
+	}
+	g := Gates{MinEntries: 10, MaxDrop: 0.5, MinFillRate: map[string]float64{"code": 0.5}}
+	if err := g.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
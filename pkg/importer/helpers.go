@@ -1,76 +1,24 @@
-// CLAUDE:SUMMARY Shared import utilities: HTTP download with retries, ZIP/GZIP extraction, manifest YAML writer, directory helpers.
+// CLAUDE:SUMMARY Shared import utilities: ZIP/GZIP extraction, manifest YAML writer, directory helpers, streaming SQLite writes.
 package importer
 
 import (
 	"archive/zip"
 	"compress/gzip"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"slices"
+	"strconv"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
 	"gopkg.in/yaml.v3"
 )
 
-// downloadFile downloads url to dest with retries and timeout.
-func downloadFile(ctx context.Context, url, dest string) error {
-	client := &http.Client{Timeout: 30 * time.Minute}
-
-	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		if attempt > 0 {
-			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return fmt.Errorf("create request: %w", err)
-		}
-		req.Header.Set("User-Agent", "touchstone-registry/1.0 (open-data-import)")
-
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
-			continue
-		}
-
-		f, err := os.Create(dest)
-		if err != nil {
-			resp.Body.Close()
-			return fmt.Errorf("create file: %w", err)
-		}
-
-		_, copyErr := io.Copy(f, resp.Body)
-		resp.Body.Close()
-		closeErr := f.Close()
-
-		if copyErr != nil {
-			lastErr = copyErr
-			continue
-		}
-		if closeErr != nil {
-			return closeErr
-		}
-		return nil
-	}
-	return fmt.Errorf("download %s failed after 3 attempts: %w", url, lastErr)
-}
-
 // unzipFile extracts a ZIP archive to destDir and returns the list of extracted file paths.
 func unzipFile(src, destDir string) ([]string, error) {
 	r, err := zip.OpenReader(src)
@@ -144,7 +92,180 @@ func gunzipFile(src, dest string) error {
 	return nil
 }
 
+// windows1252 decodes r, a source published in Windows-1252, to UTF-8.
+func windows1252(r io.Reader) io.Reader {
+	return transform.NewReader(r, charmap.Windows1252.NewDecoder())
+}
+
 // ensureDir creates a directory if it doesn't exist.
 func ensureDir(path string) error {
 	return os.MkdirAll(path, 0o755)
 }
+
+// entriesEvery is the number of entry writes between two progress reports
+// and cancellation checks of writeSQLite.
+const entriesEvery = 10000
+
+// writeSQLite streams the entries fill writes into dictDir/data.db,
+// reporting to the EntriesFunc of ctx and stopping once ctx is cancelled.
+// The new database is published only once it passes the Gates of ctx,
+// which compare it with the live build when Run stages the import; the
+// previous one is kept when fill or a gate fails.
+func writeSQLite(ctx context.Context, dictDir string, fill func(w dict.EntryWriter) error) error {
+	sw, err := dict.CreateSQLite(filepath.Join(dictDir, "data.db"))
+	if err != nil {
+		return fmt.Errorf("save sqlite: %w", err)
+	}
+	return fillSQLite(ctx, sw, dictDir, func(w *progressEntryWriter) error { return fill(w) })
+}
+
+// fillSQLite runs fill on sw, building dictDir/data.db, then checks the
+// gates and publishes it, or aborts it. The metadata totals risk estimates
+// divide by are summed here, at import time, and recorded with the row
+// counts for provenance.json.
+func fillSQLite(ctx context.Context, sw *dict.SQLiteWriter, dictDir string, fill func(w *progressEntryWriter) error) error {
+	w := &progressEntryWriter{EntryWriter: sw, ctx: ctx}
+	var gates *Gates
+	prev := filepath.Join(dictDir, "data.db") // the build being replaced
+	d, _ := ctx.Value(downloadKey{}).(*Download)
+	if d != nil {
+		w.fn, gates = d.Entries, d.Gates
+		if d.liveDir != "" {
+			prev = filepath.Join(d.liveDir, filepath.Base(dictDir), "data.db")
+		}
+	}
+	if err := fill(w); err != nil {
+		sw.Abort()
+		return fmt.Errorf("parse: %w", err)
+	}
+	if w.fn != nil {
+		w.fn(w.written, sw.Count())
+	}
+	w.rowRead() // closes the last row
+	rows := dict.RowCounts{Read: w.rows - 1, Filtered: w.filtered, Kept: sw.Count(), Collided: sw.Collisions()}
+	if gates != nil {
+		if err := sw.Check(func(db *sql.DB) error { return gates.check(db, prev) }); err != nil {
+			sw.Abort()
+			return err
+		}
+	}
+	var totals dict.Totals
+	if d != nil {
+		err := sw.Check(func(db *sql.DB) (err error) {
+			totals, err = dict.SumTotals(db)
+			return err
+		})
+		if err != nil {
+			sw.Abort()
+			return err
+		}
+	}
+	if err := sw.Close(); err != nil {
+		return fmt.Errorf("save sqlite: %w", err)
+	}
+	if d != nil {
+		d.recordBuild(filepath.Base(dictDir), rows, totals)
+	}
+	return nil
+}
+
+// putEntries writes the entries of a static table to w in key order, one
+// source row per entry.
+func putEntries(w dict.EntryWriter, entries map[string]*dict.Entry) error {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		rowRead(w)
+		if err := w.Put(k, entries[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowRead counts a source row read by a parser writing to w, for the row
+// counts of provenance.json. Parsers call it once per row, before writing
+// the row's entries.
+func rowRead(w dict.EntryWriter) {
+	if c, ok := w.(interface{ rowRead() }); ok {
+		c.rowRead()
+	}
+}
+
+// progressEntryWriter counts the writes to an EntryWriter, reporting them
+// and checking for cancellation every entriesEvery writes.
+type progressEntryWriter struct {
+	dict.EntryWriter
+	ctx     context.Context
+	fn      EntriesFunc
+	written int
+
+	rows, filtered int // source rows read, and those that wrote nothing
+	rowStart       int // written when the current row was read
+}
+
+// rowRead starts a new source row, counting the previous one as filtered
+// when it wrote nothing.
+func (w *progressEntryWriter) rowRead() {
+	if w.rows > 0 && w.written == w.rowStart {
+		w.filtered++
+	}
+	w.rows++
+	w.rowStart = w.written
+}
+
+func (w *progressEntryWriter) Put(key string, e *dict.Entry) error {
+	if err := w.tick(); err != nil {
+		return err
+	}
+	return w.EntryWriter.Put(key, e)
+}
+
+func (w *progressEntryWriter) PutNew(key string, e *dict.Entry) error {
+	if err := w.tick(); err != nil {
+		return err
+	}
+	return w.EntryWriter.PutNew(key, e)
+}
+
+func (w *progressEntryWriter) Merge(key string, e *dict.Entry, merge func(old, e *dict.Entry) *dict.Entry) error {
+	if err := w.tick(); err != nil {
+		return err
+	}
+	return w.EntryWriter.Merge(key, e, merge)
+}
+
+func (w *progressEntryWriter) tick() error {
+	w.written++
+	if w.written%entriesEvery != 0 {
+		return nil
+	}
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	if w.fn != nil {
+		w.fn(w.written, w.Count())
+	}
+	return nil
+}
+
+// sumFrequency merges two entries of an aggregated dictionary: frequencies
+// add up, other metadata keeps its first value.
+func sumFrequency(old, e *dict.Entry) *dict.Entry {
+	a, _ := strconv.Atoi(old.Metadata["frequency"])
+	b, _ := strconv.Atoi(e.Metadata["frequency"])
+	meta := make(map[string]string, len(old.Metadata)+1)
+	for k, v := range e.Metadata {
+		meta[k] = v
+	}
+	for k, v := range old.Metadata {
+		meta[k] = v
+	}
+	if a+b > 0 {
+		meta["frequency"] = strconv.Itoa(a + b)
+	}
+	return &dict.Entry{Metadata: meta}
+}
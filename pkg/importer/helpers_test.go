@@ -2,10 +2,12 @@ package importer
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/hazyhaar/touchstone-registry/pkg/dict"
@@ -94,3 +96,68 @@ func TestWriteManifest(t *testing.T) {
 		t.Errorf("DataFile = %q, want data.db", loaded.DataFile)
 	}
 }
+
+func TestWriteSQLite(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "t")
+	if err := ensureDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	var written, keys int
+	ctx := WithDownload(context.Background(), &Download{Entries: func(w, k int) { written, keys = w, k }})
+	err := writeSQLite(ctx, dir, func(w dict.EntryWriter) error {
+		for _, n := range []string{"12", "30"} {
+			e := &dict.Entry{Metadata: map[string]string{"frequency": n, "sexe": "F"}}
+			if err := w.Merge("marie", e, sumFrequency); err != nil {
+				return err
+			}
+		}
+		return w.PutNew("marie", &dict.Entry{})
+	})
+	if err != nil {
+		t.Fatalf("writeSQLite: %v", err)
+	}
+	if written != 3 || keys != 1 {
+		t.Errorf("progress = %d written, %d keys; want 3, 1", written, keys)
+	}
+
+	if err := writeManifest(dir, &dict.Manifest{ID: "t", EntityType: "first_name", DataFile: "data.db"}); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(root)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	defer reg.Close()
+	d, _ := reg.Get("t")
+	e, ok := d.Lookup("marie")
+	if !ok || e.Metadata["frequency"] != "42" || e.Metadata["sexe"] != "F" {
+		t.Errorf("marie = %+v", e)
+	}
+
+	boom := errors.New("boom")
+	err = writeSQLite(context.Background(), dir, func(w dict.EntryWriter) error {
+		_ = w.Put("other", &dict.Entry{})
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want wrapped parse error", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = writeSQLite(cancelled, dir, func(w dict.EntryWriter) error {
+		for i := range entriesEvery {
+			if err := w.Put(strconv.Itoa(i), &dict.Entry{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data.db")); err != nil {
+		t.Errorf("previous data.db must survive a failed parse: %v", err)
+	}
+}
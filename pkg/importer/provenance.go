@@ -0,0 +1,88 @@
+// CLAUDE:SUMMARY Stamps each dictionary an import staged with its provenance.json and a manifest Version derived from the source date.
+// CLAUDE:DEPENDS pkg/dict/provenance.go, pkg/importer/download.go
+// CLAUDE:EXPORTS VersionedAdapter
+
+package importer
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// VersionedAdapter is an Adapter that versions its parsing, recorded as the
+// adapter_version of provenance.json. Other adapters record "1".
+type VersionedAdapter interface {
+	Adapter
+	AdapterVersion() string
+}
+
+func adapterVersion(a Adapter) string {
+	if v, ok := a.(VersionedAdapter); ok {
+		return v.AdapterVersion()
+	}
+	return "1"
+}
+
+// stampStaged writes provenance.json into every dictionary built in staging
+// and sets its manifest Version to the date of the source: the latest
+// Last-Modified of the downloads of d, or the start of the import.
+func stampStaged(staging string, a Adapter, d *Download, started time.Time, since *time.Time) error {
+	p := dict.Provenance{
+		Adapter:        a.ID(),
+		AdapterVersion: adapterVersion(a),
+		Mode:           "full",
+		DeltaSince:     since,
+		SourceDate:     started.UTC(),
+		StartedAt:      started.UTC(),
+		FinishedAt:     time.Now().UTC(),
+	}
+	if since != nil {
+		p.Mode = "delta"
+	}
+	d.mu.Lock()
+	p.Downloads = append([]dict.SourceDownload{}, d.downloads...)
+	rows, totals := d.rows, d.totals
+	d.mu.Unlock()
+	latest := time.Time{}
+	for _, sd := range p.Downloads {
+		if t, err := http.ParseTime(sd.LastModified); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	if !latest.IsZero() {
+		p.SourceDate = latest.UTC()
+	}
+
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("read staging: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() || !dict.IsDictDir(e.Name()) {
+			continue
+		}
+		dir := filepath.Join(staging, e.Name())
+		m, err := dict.LoadManifest(filepath.Join(dir, "manifest.yaml"))
+		if err != nil {
+			continue // not a dictionary; publishStaged skips it too
+		}
+		m.Version = p.SourceDate.Format(time.DateOnly)
+		if err := dict.SaveManifest(dir, m); err != nil {
+			return err
+		}
+		dp := p
+		dp.Rows = rows[e.Name()]
+		if t, ok := totals[e.Name()]; ok {
+			dp.Totals = &t
+		}
+		if err := dict.WriteProvenance(dir, &dp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func TestRun_Provenance(t *testing.T) {
+	days := []string{}
+	files := deltaServer(t, "sirene", &days)
+	modified := time.Date(2026, 10, 1, 6, 0, 0, 0, time.UTC)
+	resp, err := http.Get(files.URL + "/sirene_stock.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stock, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(stock)
+	sum := hex.EncodeToString(h[:])
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		_, _ = w.Write(stock)
+	}))
+	defer ts.Close()
+
+	a := &sireneAdapter{}
+	dir := t.TempDir()
+	sdb, err := OpenSourceDB(filepath.Join(dir, "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+	if err := sdb.Seed([]Adapter{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.SetURL(a.ID(), ts.URL+"/stock.zip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(context.Background(), sdb, a, dir, RunOptions{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	live := filepath.Join(dir, a.DictID())
+	p, err := dict.LoadProvenance(live)
+	if err != nil || p == nil {
+		t.Fatalf("LoadProvenance = %v, %v", p, err)
+	}
+	if p.Adapter != a.ID() || p.AdapterVersion != "1" || p.Mode != "full" || p.DeltaSince != nil {
+		t.Errorf("provenance = %+v", p)
+	}
+	if !p.SourceDate.Equal(modified) {
+		t.Errorf("SourceDate = %v, want the Last-Modified %v", p.SourceDate, modified)
+	}
+	if len(p.Downloads) != 1 {
+		t.Fatalf("Downloads = %+v, want one", p.Downloads)
+	}
+	if d := p.Downloads[0]; d.URL != ts.URL+"/stock.zip" || d.SHA256 != sum || d.Bytes == 0 || d.LastModified == "" {
+		t.Errorf("download = %+v, want sha256 %s", d, sum)
+	}
+	if want := (dict.RowCounts{Read: 3, Filtered: 1, Kept: 2}); p.Rows != want {
+		t.Errorf("Rows = %+v, want %+v", p.Rows, want)
+	}
+	if p.Totals == nil {
+		t.Error("Totals missing")
+	}
+	if p.FinishedAt.Before(p.StartedAt) {
+		t.Errorf("FinishedAt %v before StartedAt %v", p.FinishedAt, p.StartedAt)
+	}
+
+	m, err := dict.LoadManifest(filepath.Join(live, "manifest.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != "2026-10-01" {
+		t.Errorf("Version = %q, want the source date", m.Version)
+	}
+}
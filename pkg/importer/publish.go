@@ -0,0 +1,201 @@
+// CLAUDE:SUMMARY Publishes the dictionaries an import built in its staging directory as builds under _builds/<id>, atomically repointing the <id> and <id>.prev links at the new and previous builds; Rollback swaps the two links.
+// CLAUDE:DEPENDS pkg/dict/registry.go
+// CLAUDE:EXPORTS Rollback, ErrNoPrevious
+
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// ErrNoPrevious is returned, wrapped, by Rollback when a dictionary has no
+// previous build.
+var ErrNoPrevious = errors.New("no previous build")
+
+// stagingDir returns the directory adapter a builds into, inside outputDir
+// so that publishing is a rename on the same filesystem.
+func stagingDir(outputDir string, a Adapter) string {
+	return filepath.Join(outputDir, "_staging", a.ID())
+}
+
+// publishStaged moves every dictionary built in staging into outputDir.
+func publishStaged(staging, outputDir string) error {
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("read staging: %w", err)
+	}
+	published := 0
+	for _, e := range entries {
+		if !e.IsDir() || !dict.IsDictDir(e.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(staging, e.Name(), "manifest.yaml")); err != nil {
+			continue
+		}
+		if err := publish(filepath.Join(staging, e.Name()), filepath.Join(outputDir, e.Name())); err != nil {
+			return err
+		}
+		published++
+	}
+	if published == 0 {
+		return errors.New("import built no dictionary")
+	}
+	return nil
+}
+
+// buildsDir holds the builds of dictionary id, relative to the dicts
+// directory; <id> and <id>.prev are symbolic links to two of them.
+func buildsDir(id string) string {
+	return filepath.Join("_builds", id)
+}
+
+// publish makes staged the live build of the dictionary at live: it moves
+// staged under the builds directory, then repoints the link live at it by
+// renaming a new link over it, so that live exists at every instant, even
+// to a concurrent Registry.Reload. live.prev is repointed at the build it
+// replaced and older builds are removed. Readers holding files of a removed
+// build keep reading them until they reload.
+func publish(staged, live string) error {
+	outputDir, id := filepath.Dir(live), filepath.Base(live)
+	old, err := linkTarget(outputDir, id, live)
+	if err != nil {
+		return err
+	}
+	build, err := newBuild(outputDir, id)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(staged, filepath.Join(outputDir, build)); err != nil {
+		return fmt.Errorf("move %s to %s: %w", staged, build, err)
+	}
+	if err := setLink(live, build); err != nil {
+		_ = os.RemoveAll(filepath.Join(outputDir, build))
+		return fmt.Errorf("publish %s: %w", live, err)
+	}
+	if old == "" {
+		return nil
+	}
+	prev := live + dict.PrevSuffix
+	if _, err := linkTarget(outputDir, id, prev); err != nil {
+		return err
+	}
+	if err := setLink(prev, old); err != nil {
+		return fmt.Errorf("keep previous build: %w", err)
+	}
+	return pruneBuilds(outputDir, id, build, old)
+}
+
+// newBuild returns an unused build directory for dictionary id, relative to
+// outputDir, creating its parent.
+func newBuild(outputDir, id string) (string, error) {
+	builds := filepath.Join(outputDir, buildsDir(id))
+	if err := os.MkdirAll(builds, 0o755); err != nil {
+		return "", fmt.Errorf("create %s: %w", builds, err)
+	}
+	stamp := time.Now().UTC().Format("20060102T150405.000000000")
+	for i := 0; ; i++ {
+		name := stamp
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", stamp, i)
+		}
+		if _, err := os.Lstat(filepath.Join(builds, name)); os.IsNotExist(err) {
+			return filepath.Join(buildsDir(id), name), nil
+		}
+	}
+}
+
+// linkTarget returns the build the link name points to, or "" when name
+// does not exist. A directory left there by an import predating builds is
+// moved into a build first, and name linked to it.
+func linkTarget(outputDir, id, name string) (string, error) {
+	fi, err := os.Lstat(name)
+	switch {
+	case os.IsNotExist(err):
+		return "", nil
+	case err != nil:
+		return "", err
+	case fi.Mode()&os.ModeSymlink != 0:
+		return os.Readlink(name)
+	}
+	build, err := newBuild(outputDir, id)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(name, filepath.Join(outputDir, build)); err != nil {
+		return "", fmt.Errorf("move %s to %s: %w", name, build, err)
+	}
+	if err := setLink(name, build); err != nil {
+		return "", fmt.Errorf("link %s: %w", name, err)
+	}
+	return build, nil
+}
+
+// setLink points the symbolic link name at target, replacing it atomically.
+func setLink(name, target string) error {
+	tmp := filepath.Join(filepath.Dir(name), "_link-"+filepath.Base(name))
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// pruneBuilds removes the builds of dictionary id other than keep.
+func pruneBuilds(outputDir, id string, keep ...string) error {
+	builds := filepath.Join(outputDir, buildsDir(id))
+	entries, err := os.ReadDir(builds)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", builds, err)
+	}
+	for _, e := range entries {
+		if slices.Contains(keep, filepath.Join(buildsDir(id), e.Name())) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(builds, e.Name())); err != nil {
+			return fmt.Errorf("remove old build: %w", err)
+		}
+	}
+	return nil
+}
+
+// Rollback swaps the dictionary dictID of outputDir with its previous build,
+// so that a second Rollback restores the build it replaced. Both are links,
+// each repointed atomically.
+func Rollback(outputDir, dictID string) error {
+	live := filepath.Join(outputDir, dictID)
+	prev := live + dict.PrevSuffix
+	if _, err := os.Stat(filepath.Join(prev, "manifest.yaml")); err != nil {
+		return fmt.Errorf("%w of %s", ErrNoPrevious, dictID)
+	}
+	cur, err := linkTarget(outputDir, dictID, live)
+	if err != nil {
+		return err
+	}
+	old, err := linkTarget(outputDir, dictID, prev)
+	if err != nil {
+		return err
+	}
+	if err := setLink(live, old); err != nil {
+		return fmt.Errorf("restore %s: %w", prev, err)
+	}
+	if cur == "" {
+		return os.Remove(prev)
+	}
+	if err := setLink(prev, cur); err != nil {
+		return fmt.Errorf("keep rolled-back build: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func TestRun_PublishAndRollback(t *testing.T) {
+	prevBackoff := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = prevBackoff })
+	body := "v1"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if body == "" {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+	sdb, err := OpenSourceDB(filepath.Join(dir, "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+	if err := sdb.Seed([]Adapter{fileAdapter{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdb.SetURL("test-file", ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	live := filepath.Join(dir, "test-file")
+	contents := func(d string) string {
+		b, _ := os.ReadFile(filepath.Join(d, "data.csv"))
+		return string(b)
+	}
+	run := func() error {
+		return Run(context.Background(), sdb, fileAdapter{}, dir, RunOptions{Force: true})
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if got := contents(live); got != "v1" {
+		t.Errorf("live = %q, want v1", got)
+	}
+	if _, err := os.Stat(live + ".prev"); !os.IsNotExist(err) {
+		t.Error("first build must not leave a previous build")
+	}
+
+	body = "v2"
+	if err := run(); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if got, prev := contents(live), contents(live+".prev"); got != "v2" || prev != "v1" {
+		t.Errorf("live = %q, prev = %q; want v2, v1", got, prev)
+	}
+	body = "v3"
+	if err := run(); err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	if got, prev := contents(live), contents(live+".prev"); got != "v3" || prev != "v2" {
+		t.Errorf("live = %q, prev = %q; want v3, v2", got, prev)
+	}
+	if fi, err := os.Lstat(live); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("live build is not a link: %v", err)
+	}
+	if builds, _ := os.ReadDir(filepath.Join(dir, "_builds", "test-file")); len(builds) != 2 {
+		t.Errorf("%d builds kept, want the live and the previous one", len(builds))
+	}
+
+	// A failed import leaves both builds as they were.
+	body = ""
+	if err := run(); err == nil {
+		t.Fatal("expected a download error")
+	}
+	if got, prev := contents(live), contents(live+".prev"); got != "v3" || prev != "v2" {
+		t.Errorf("after a failed run: live = %q, prev = %q", got, prev)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "_staging", "test-file")); !os.IsNotExist(err) {
+		t.Error("staging directory left behind")
+	}
+
+	if err := Rollback(dir, "test-file"); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got, prev := contents(live), contents(live+".prev"); got != "v2" || prev != "v3" {
+		t.Errorf("after rollback: live = %q, prev = %q", got, prev)
+	}
+	if err := Rollback(dir, "test-file"); err != nil || contents(live) != "v3" {
+		t.Errorf("second rollback: %v, live = %q", err, contents(live))
+	}
+	if err := Rollback(dir, "missing"); !errors.Is(err, ErrNoPrevious) {
+		t.Errorf("missing dictionary: err = %v", err)
+	}
+}
+
+func TestPublish_AdoptsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(d, data string) {
+		t.Helper()
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		manifest := "id: test-file\nversion: \"1\"\nmethod: exact\nentity_type: thing\ndata_file: data.csv\n"
+		if err := os.WriteFile(filepath.Join(d, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(d, "data.csv"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directories published before builds.
+	live := filepath.Join(dir, "test-file")
+	write(live, "v1")
+	write(live+dict.PrevSuffix, "v0")
+
+	staged := filepath.Join(dir, "_staging", "test-file")
+	write(staged, "v2")
+	if err := publish(staged, live); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	for name, want := range map[string]string{live: "v2", live + dict.PrevSuffix: "v1"} {
+		fi, err := os.Lstat(name)
+		if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("%s is not a link: %v", name, err)
+		}
+		if b, _ := os.ReadFile(filepath.Join(name, "data.csv")); string(b) != want {
+			t.Errorf("%s = %q, want %q", name, b, want)
+		}
+	}
+
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reg.Get("test-file"); !ok {
+		t.Error("linked dictionary not loaded")
+	}
+	if err := Rollback(dir, "test-file"); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(live, "data.csv")); string(b) != "v1" {
+		t.Errorf("after rollback: live = %q", b)
+	}
+}
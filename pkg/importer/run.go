@@ -0,0 +1,120 @@
+// CLAUDE:SUMMARY Runs one adapter import against its import_sources row: conditional download state in, deltas applied when the adapter is incremental, staged build published, new validators out, unchanged sources skipped.
+// CLAUDE:DEPENDS pkg/importer/adapter.go, pkg/importer/delta.go, pkg/importer/download.go, pkg/importer/provenance.go, pkg/importer/publish.go, pkg/importer/sourcedb.go
+// CLAUDE:EXPORTS Run, RunOptions
+
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunOptions configures Run.
+type RunOptions struct {
+	Progress ProgressFunc // download progress; nil reports nothing
+	Entries  EntriesFunc  // parse progress; nil reports nothing
+	Gates    *Gates       // quality gates of the new build; nil checks nothing
+	Logger   *slog.Logger // fallbacks from deltas to a full import; nil logs nothing
+	Force    bool         // ignore the validators of the last import and rebuild incremental sources in full
+}
+
+// Run imports the source of adapter a into outputDir, using the URL, the
+// conditional-request validators and the expected SHA-256 stored for it in
+// sdb. The adapter builds into a staging directory; the dictionaries it
+// built then replace the live ones by an atomic swap of the <id> link, each
+// previous build kept as <id>.prev for Rollback. It returns an error wrapping ErrNotModified when
+// the source is unchanged since the last import, leaving the dictionary as
+// it was. After a successful import the new validators are stored. Each
+// dictionary built gets a provenance.json and a Version from the source date.
+//
+// An IncrementalAdapter imported before gets the deltas published since its
+// last import applied to a copy of its live dictionary instead; when the
+// delta chain is broken, Run falls back to a full import.
+func Run(ctx context.Context, sdb *SourceDB, a Adapter, outputDir string, opts RunOptions) error {
+	d, err := sdb.GetDownload(a.ID())
+	if err != nil {
+		return err
+	}
+	d.Progress, d.Entries, d.Gates = opts.Progress, opts.Entries, opts.Gates
+	d.liveDir = outputDir
+	// A missing dictionary is rebuilt whatever the source validators say.
+	_, statErr := os.Stat(filepath.Join(outputDir, a.DictID(), "manifest.yaml"))
+	if opts.Force || statErr != nil {
+		d.ETag, d.LastModified = "", ""
+	}
+	ctx = WithDownload(ctx, d)
+
+	staging := stagingDir(outputDir, a)
+	resetStaging := func() error {
+		if err := os.RemoveAll(staging); err != nil {
+			return fmt.Errorf("clear staging: %w", err)
+		}
+		if err := ensureDir(staging); err != nil {
+			return fmt.Errorf("create staging: %w", err)
+		}
+		return nil
+	}
+	if err := resetStaging(); err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	started := time.Now()
+	if ia, ok := a.(IncrementalAdapter); ok && !opts.Force && statErr == nil {
+		since, err := sdb.ImportedAt(a.ID())
+		if err != nil {
+			return err
+		}
+		if !since.IsZero() {
+			err := importDelta(ctx, ia, since, staging, outputDir)
+			if err == nil {
+				err = stampStaged(staging, a, d, started, &since)
+			}
+			switch {
+			case err == nil:
+				if err := publishStaged(staging, outputDir); err != nil {
+					return err
+				}
+				return sdb.SetImportedAt(a.ID(), started)
+			case errors.Is(err, ErrNotModified):
+				return ErrNotModified
+			case !errors.Is(err, ErrDeltaChainBroken):
+				return err
+			}
+			if opts.Logger != nil {
+				opts.Logger.Warn("delta import: falling back to a full import", "adapter", a.ID(), "error", err)
+			}
+			if err := resetStaging(); err != nil {
+				return err
+			}
+			d.resetRecord()
+		}
+	}
+
+	if err := a.Import(ctx, d.URL, staging); err != nil {
+		if errors.Is(err, ErrNotModified) {
+			return ErrNotModified
+		}
+		return err
+	}
+	if err := stampStaged(staging, a, d, started, nil); err != nil {
+		return err
+	}
+	if err := publishStaged(staging, outputDir); err != nil {
+		return err
+	}
+	if err := sdb.SetValidators(a.ID(), d.ETag, d.LastModified); err != nil {
+		return err
+	}
+	// The source is as of its Last-Modified when the server sent one.
+	if t, err := http.ParseTime(d.LastModified); err == nil && t.Before(started) {
+		started = t
+	}
+	return sdb.SetImportedAt(a.ID(), started)
+}
@@ -4,6 +4,7 @@ package importer
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -20,6 +21,7 @@ type Source struct {
 	LastStatus  *int
 	LastError   *string
 	UpdatedAt   int64
+	SHA256      string // expected digest of the source download, "" when unchecked
 }
 
 // SourceDB manages the import_sources SQLite table.
@@ -51,6 +53,22 @@ func OpenSourceDB(path string) (*SourceDB, error) {
 		return nil, fmt.Errorf("create import_sources table: %w", err)
 	}
 
+	// Columns added after the first release.
+	for _, col := range []string{"etag", "last_modified", "sha256", "imported_at"} {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('import_sources') WHERE name = ?`, col).Scan(&n); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("inspect import_sources: %w", err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE import_sources ADD COLUMN ` + col + ` TEXT NOT NULL DEFAULT ''`); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("add import_sources.%s: %w", col, err)
+		}
+	}
+
 	return &SourceDB{db: db}, nil
 }
 
@@ -101,6 +119,73 @@ func (s *SourceDB) SetURL(adapterID, url string) error {
 	return nil
 }
 
+// GetDownload returns the download state of an adapter's source: its URL,
+// the validators of its last import and its expected SHA-256.
+func (s *SourceDB) GetDownload(adapterID string) (*Download, error) {
+	d := &Download{}
+	err := s.db.QueryRow(`SELECT source_url, etag, last_modified, sha256 FROM import_sources WHERE adapter_id = ?`, adapterID).
+		Scan(&d.URL, &d.ETag, &d.LastModified, &d.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("get download for %s: %w", adapterID, err)
+	}
+	return d, nil
+}
+
+// SetValidators records the ETag and Last-Modified of a successful import,
+// sent back as conditional request headers by the next one.
+func (s *SourceDB) SetValidators(adapterID, etag, lastModified string) error {
+	_, err := s.db.Exec(`UPDATE import_sources SET etag = ?, last_modified = ? WHERE adapter_id = ?`,
+		etag, lastModified, adapterID)
+	if err != nil {
+		return fmt.Errorf("set validators for %s: %w", adapterID, err)
+	}
+	return nil
+}
+
+// ImportedAt returns the time up to which the last import of an adapter
+// holds the source's changes, zero when it was never imported.
+func (s *SourceDB) ImportedAt(adapterID string) (time.Time, error) {
+	var at string
+	err := s.db.QueryRow(`SELECT imported_at FROM import_sources WHERE adapter_id = ?`, adapterID).Scan(&at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get import time for %s: %w", adapterID, err)
+	}
+	if at == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse import time for %s: %w", adapterID, err)
+	}
+	return t, nil
+}
+
+// SetImportedAt records the time up to which an import holds the source's
+// changes, from which the next delta import starts.
+func (s *SourceDB) SetImportedAt(adapterID string, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE import_sources SET imported_at = ? WHERE adapter_id = ?`,
+		at.UTC().Format(time.RFC3339), adapterID)
+	if err != nil {
+		return fmt.Errorf("set import time for %s: %w", adapterID, err)
+	}
+	return nil
+}
+
+// SetSHA256 sets the expected SHA-256 of an adapter's source download; ""
+// disables verification.
+func (s *SourceDB) SetSHA256(adapterID, sum string) error {
+	res, err := s.db.Exec(`UPDATE import_sources SET sha256 = ?, updated_at = ? WHERE adapter_id = ?`,
+		strings.ToLower(sum), time.Now().Unix(), adapterID)
+	if err != nil {
+		return fmt.Errorf("set sha256 for %s: %w", adapterID, err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("adapter %s not found in import_sources", adapterID)
+	}
+	return nil
+}
+
 // UpdateCheck persists the result of an availability check.
 func (s *SourceDB) UpdateCheck(adapterID string, status int, checkErr string) error {
 	now := time.Now().Unix()
@@ -121,7 +206,7 @@ func (s *SourceDB) UpdateCheck(adapterID string, status int, checkErr string) er
 // ListSources returns all rows from import_sources ordered by adapter_id.
 func (s *SourceDB) ListSources() ([]Source, error) {
 	rows, err := s.db.Query(`SELECT adapter_id, dict_id, description, source_url, license,
-		last_check, last_status, last_error, updated_at
+		last_check, last_status, last_error, updated_at, sha256
 		FROM import_sources ORDER BY adapter_id`)
 	if err != nil {
 		return nil, fmt.Errorf("list sources: %w", err)
@@ -132,7 +217,7 @@ func (s *SourceDB) ListSources() ([]Source, error) {
 	for rows.Next() {
 		var src Source
 		if err := rows.Scan(&src.AdapterID, &src.DictID, &src.Description, &src.SourceURL,
-			&src.License, &src.LastCheck, &src.LastStatus, &src.LastError, &src.UpdatedAt); err != nil {
+			&src.License, &src.LastCheck, &src.LastStatus, &src.LastError, &src.UpdatedAt, &src.SHA256); err != nil {
 			return nil, fmt.Errorf("scan source: %w", err)
 		}
 		sources = append(sources, src)
@@ -0,0 +1,204 @@
+// CLAUDE:SUMMARY Sensitivity-based access policies: per EntitySpec.Sensitivity level and caller class (anonymous, authenticated), whether a dictionary may be queried, which metadata fields are returned and whether Resolve exposes rich data.
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/dict/manifest.go
+// CLAUDE:EXPORTS Config, Rule, Policy, New, Anonymous, Authenticated, DefaultLevel, WithClass, ClassFrom
+
+package policy
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hazyhaar/pkg/kit"
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// Caller classes.
+const (
+	Anonymous     = "anonymous"     // FO visitors and API calls without a token
+	Authenticated = "authenticated" // API calls bearing one of the api_tokens
+)
+
+// DefaultLevel holds the rules of dictionaries without a sensitivity, and of
+// sensitivity levels the configuration does not list.
+const DefaultLevel = "default"
+
+// Rule is what one caller class may do with dictionaries of one sensitivity.
+// Unset fields allow.
+type Rule struct {
+	Query   *bool     `yaml:"query"`   // whether the dictionary may be queried at all
+	Resolve *bool     `yaml:"resolve"` // whether Resolve may expose rich data
+	Fields  *[]string `yaml:"fields"`  // metadata fields returned; all when unset, none when empty
+}
+
+// Config is the policies section of config.yaml:
+//
+//	policies:
+//	  api_tokens: ["..."]
+//	  mcp_class: authenticated
+//	  levels:
+//	    high:
+//	      anonymous: { query: false }
+//	      authenticated: { resolve: false, fields: [pattern] }
+type Config struct {
+	APITokens []string                   `yaml:"api_tokens"`
+	MCPClass  string                     `yaml:"mcp_class"` // class of MCP tool calls; anonymous when unset
+	Levels    map[string]map[string]Rule `yaml:"levels"`    // sensitivity → caller class → rule
+}
+
+// Policy enforces a Config. A nil *Policy allows everything.
+type Policy struct {
+	cfg Config
+}
+
+// New validates cfg and returns its policy.
+func New(cfg Config) (*Policy, error) {
+	if cfg.MCPClass == "" {
+		cfg.MCPClass = Anonymous
+	}
+	if !validClass(cfg.MCPClass) {
+		return nil, fmt.Errorf("policies: unknown mcp_class %q", cfg.MCPClass)
+	}
+	for level, rules := range cfg.Levels {
+		for class := range rules {
+			if !validClass(class) {
+				return nil, fmt.Errorf("policies: level %s: unknown caller class %q", level, class)
+			}
+		}
+	}
+	for _, t := range cfg.APITokens {
+		if t == "" {
+			return nil, fmt.Errorf("policies: empty api token")
+		}
+	}
+	return &Policy{cfg: cfg}, nil
+}
+
+func validClass(c string) bool {
+	return c == Anonymous || c == Authenticated
+}
+
+// WithClass returns ctx carrying the caller class, as the kit role.
+func WithClass(ctx context.Context, class string) context.Context {
+	return kit.WithRole(ctx, class)
+}
+
+// ClassFrom returns the caller class carried by ctx, Anonymous when none.
+func ClassFrom(ctx context.Context) string {
+	if c := kit.GetRole(ctx); validClass(c) {
+		return c
+	}
+	return Anonymous
+}
+
+// HTTPClass is the class of an HTTP request: Authenticated when it bears one
+// of the api_tokens as a bearer token.
+func (p *Policy) HTTPClass(r *http.Request) string {
+	if p == nil {
+		return Authenticated
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return Anonymous
+	}
+	for _, t := range p.cfg.APITokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return Authenticated
+		}
+	}
+	return Anonymous
+}
+
+// MCPClass is the class of MCP tool calls.
+func (p *Policy) MCPClass() string {
+	if p == nil {
+		return Authenticated
+	}
+	return p.cfg.MCPClass
+}
+
+// rule returns the rule for dictionaries of manifest m and class.
+func (p *Policy) rule(m *dict.Manifest, class string) Rule {
+	if p == nil {
+		return Rule{}
+	}
+	level := DefaultLevel
+	if m.EntitySpec != nil && m.EntitySpec.Sensitivity != "" {
+		level = m.EntitySpec.Sensitivity
+	}
+	rules, ok := p.cfg.Levels[level]
+	if !ok {
+		rules = p.cfg.Levels[DefaultLevel]
+	}
+	return rules[class]
+}
+
+// CanQuery reports whether class may query the dictionary of manifest m.
+func (p *Policy) CanQuery(m *dict.Manifest, class string) bool {
+	r := p.rule(m, class)
+	return r.Query == nil || *r.Query
+}
+
+// CanResolve reports whether class may see rich resolve data of manifest m.
+func (p *Policy) CanResolve(m *dict.Manifest, class string) bool {
+	r := p.rule(m, class)
+	return r.Resolve == nil || *r.Resolve
+}
+
+// CanSeeField reports whether class may see the metadata field of
+// dictionaries of manifest m.
+func (p *Policy) CanSeeField(m *dict.Manifest, class, field string) bool {
+	r := p.rule(m, class)
+	return r.Fields == nil || slices.Contains(*r.Fields, field)
+}
+
+// FilterMetadata returns the fields of meta class may see. meta is never
+// modified in place.
+func (p *Policy) FilterMetadata(m *dict.Manifest, class string, meta map[string]string) map[string]string {
+	r := p.rule(m, class)
+	if r.Fields == nil || meta == nil {
+		return meta
+	}
+	out := make(map[string]string)
+	for _, f := range *r.Fields {
+		if v, ok := meta[f]; ok {
+			out[f] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// Options returns a copy of opts that skips the dictionaries class may not
+// query.
+func (p *Policy) Options(opts *dict.ClassifyOptions, class string) *dict.ClassifyOptions {
+	out := &dict.ClassifyOptions{}
+	if opts != nil {
+		*out = *opts
+	}
+	if p == nil {
+		return out
+	}
+	prev := out.Filter
+	out.Filter = func(m *dict.Manifest) bool {
+		return p.CanQuery(m, class) && (prev == nil || prev(m))
+	}
+	return out
+}
+
+// FilterMatches trims the metadata of every match to what class may see.
+func (p *Policy) FilterMatches(reg *dict.Registry, class string, matches []dict.Match) {
+	if p == nil {
+		return
+	}
+	for i := range matches {
+		if d, ok := reg.Get(matches[i].DictID); ok {
+			matches[i].Metadata = p.FilterMetadata(d.Manifest, class, matches[i].Metadata)
+		}
+	}
+}
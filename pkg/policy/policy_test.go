@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfig = `
+api_tokens: ["s3cret-token"]
+levels:
+  high:
+    anonymous: { query: false }
+    authenticated: { resolve: false, fields: [pattern] }
+  default:
+    anonymous: { fields: [] }
+`
+
+func testPolicy(t *testing.T) *Policy {
+	t.Helper()
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(testConfig), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return p
+}
+
+func manifest(sensitivity string) *dict.Manifest {
+	m := &dict.Manifest{ID: "x"}
+	if sensitivity != "" {
+		m.EntitySpec = &dict.EntitySpec{Sensitivity: sensitivity}
+	}
+	return m
+}
+
+func TestPolicy_Rules(t *testing.T) {
+	p := testPolicy(t)
+	high, medium, none := manifest("high"), manifest("medium"), manifest("")
+
+	if p.CanQuery(high, Anonymous) || !p.CanQuery(high, Authenticated) {
+		t.Error("high: anonymous must not query, authenticated may")
+	}
+	if p.CanResolve(high, Authenticated) || !p.CanResolve(none, Authenticated) {
+		t.Error("resolve rules not applied")
+	}
+
+	meta := map[string]string{"pattern": "nir", "birth_date": "1985-01"}
+	if got := p.FilterMetadata(high, Authenticated, meta); len(got) != 1 || got["pattern"] != "nir" {
+		t.Errorf("high/authenticated fields = %v", got)
+	}
+	// Unlisted levels fall back to default.
+	if got := p.FilterMetadata(medium, Anonymous, meta); got != nil {
+		t.Errorf("medium/anonymous fields = %v, want none", got)
+	}
+	if got := p.FilterMetadata(medium, Authenticated, meta); len(got) != 2 {
+		t.Errorf("medium/authenticated fields = %v, want all", got)
+	}
+	if len(meta) != 2 {
+		t.Errorf("metadata modified in place: %v", meta)
+	}
+}
+
+func TestPolicy_Options(t *testing.T) {
+	p := testPolicy(t)
+	opts := p.Options(&dict.ClassifyOptions{Filter: func(m *dict.Manifest) bool { return m.ID != "skip" }}, Anonymous)
+	if opts.Filter(manifest("high")) {
+		t.Error("high dictionary must be filtered for anonymous callers")
+	}
+	if !opts.Filter(manifest("medium")) {
+		t.Error("medium dictionary must pass")
+	}
+	skip := manifest("")
+	skip.ID = "skip"
+	if opts.Filter(skip) {
+		t.Error("existing filter must still apply")
+	}
+
+	var nilPolicy *Policy
+	if o := nilPolicy.Options(nil, Anonymous); o.Filter != nil {
+		t.Error("nil policy must not filter")
+	}
+}
+
+func TestPolicy_Classes(t *testing.T) {
+	p := testPolicy(t)
+
+	r := httptest.NewRequest("GET", "/v1/dicts", nil)
+	if c := p.HTTPClass(r); c != Anonymous {
+		t.Errorf("no token: class = %s", c)
+	}
+	r.Header.Set("Authorization", "Bearer wrong")
+	if c := p.HTTPClass(r); c != Anonymous {
+		t.Errorf("wrong token: class = %s", c)
+	}
+	r.Header.Set("Authorization", "Bearer s3cret-token")
+	if c := p.HTTPClass(r); c != Authenticated {
+		t.Errorf("valid token: class = %s", c)
+	}
+
+	if c := p.MCPClass(); c != Anonymous {
+		t.Errorf("MCP class = %s, want anonymous by default", c)
+	}
+	if c := ClassFrom(WithClass(context.Background(), Authenticated)); c != Authenticated {
+		t.Errorf("ClassFrom = %s", c)
+	}
+	if c := ClassFrom(context.Background()); c != Anonymous {
+		t.Errorf("ClassFrom(empty) = %s", c)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(Config{MCPClass: "root"}); err == nil {
+		t.Error("expected error for unknown mcp_class")
+	}
+	if _, err := New(Config{Levels: map[string]map[string]Rule{"high": {"guest": {}}}}); err == nil {
+		t.Error("expected error for unknown caller class")
+	}
+}
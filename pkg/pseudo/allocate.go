@@ -0,0 +1,104 @@
+// CLAUDE:SUMMARY Deterministic per-key alias allocation: hands out distinct usable aliases of a pool to a batch of terms, skipping aliases flagged by the criblage.
+// CLAUDE:DEPENDS pkg/pseudo/strategies.go, pkg/dict/aliaspool.go
+// CLAUDE:EXPORTS AliasAllocation, AllocateAliases
+package pseudo
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// AliasAllocation is the alias handed out to one term.
+type AliasAllocation struct {
+	Term   string `json:"term"`
+	Alias  string `json:"alias"`
+	Mimics string `json:"mimics,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// AllocateAliases hands out an alias of domain to every term. Equal terms
+// (after normalization) share an alias and distinct terms get distinct ones;
+// aliases flagged by the last criblage are never handed out.
+//
+// Allocation is stateless and deterministic under key: each term starts from
+// its preferred alias, the one the alias pseudo_strategy would pick, and
+// moves to the next free one in pool order when a term ranked before it took
+// it. Ranks derive from the key, not from the request order, so a term keeps
+// its alias across requests unless it collides with another term of the
+// batch.
+func AllocateAliases(reg *dict.Registry, domain string, key []byte, terms []string) ([]AliasAllocation, error) {
+	if len(key) < MinKeyLen {
+		return nil, fmt.Errorf("key must be at least %d bytes", MinKeyLen)
+	}
+	pool, err := usableAliases(reg, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	canon := make([]string, len(terms))
+	var distinct []string
+	seen := make(map[string]bool)
+	for i, t := range terms {
+		canon[i] = strings.Join(strings.Fields(dict.NormalizeLowercaseASCII(t)), " ")
+		if !seen[canon[i]] {
+			seen[canon[i]] = true
+			distinct = append(distinct, canon[i])
+		}
+	}
+	if len(distinct) > len(pool) {
+		return nil, fmt.Errorf("alias pool %q exhausted: %d distinct terms, %d usable aliases", domain, len(distinct), len(pool))
+	}
+
+	rank := make(map[string][]byte, len(distinct))
+	for _, c := range distinct {
+		rank[c] = mac(key, "alias-rank", domain, c)
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		return bytes.Compare(rank[distinct[i]], rank[distinct[j]]) < 0
+	})
+
+	taken := make([]bool, len(pool))
+	assigned := make(map[string]int, len(distinct))
+	for _, c := range distinct {
+		j := preferredAlias(key, domain, c, len(pool))
+		for taken[j] {
+			j = (j + 1) % len(pool)
+		}
+		taken[j] = true
+		assigned[c] = j
+	}
+
+	out := make([]AliasAllocation, len(terms))
+	for i, t := range terms {
+		e := pool[assigned[canon[i]]]
+		out[i] = AliasAllocation{Term: t, Alias: e.Alias, Mimics: e.Mimics, Suffix: e.Suffix}
+	}
+	return out, nil
+}
+
+// usableAliases returns the aliases of domain that passed the last criblage.
+func usableAliases(reg *dict.Registry, domain string) ([]dict.AliasEntry, error) {
+	all := reg.GetAliases(domain)
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no alias pool for domain %q", domain)
+	}
+	pool := all[:0]
+	for _, e := range all {
+		if e.Usable() {
+			pool = append(pool, e)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("alias pool %q has no usable alias", domain)
+	}
+	return pool, nil
+}
+
+// preferredAlias is the pool index a term maps to under key.
+func preferredAlias(key []byte, domain, canonical string, n int) int {
+	return newKeystream(key, "alias", domain, canonical).intn(n)
+}
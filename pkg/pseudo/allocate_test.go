@@ -0,0 +1,101 @@
+package pseudo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func setupAllocRegistry(t *testing.T) *dict.Registry {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "company-aliases")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `id: company-aliases
+version: "1.0"
+jurisdiction: fr
+entity_type: alias
+source: manual
+type: alias_pool
+domain: company
+entries:
+  - alias: "ALDORA"
+  - alias: "PHARMEX"
+    collisions: [sirene-fr]
+  - alias: "NOVALIS"
+    mimics: "SANOFI"
+    suffix: "SA"
+  - alias: "ALDORA"
+    duplicate: true
+  - alias: "VERANCE"
+`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(filepath.Dir(dir))
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+func TestAllocateAliases(t *testing.T) {
+	reg := setupAllocRegistry(t)
+	terms := []string{"Sanofi", "Servier", "SANOFI", "Ipsen"}
+
+	got, err := AllocateAliases(reg, "company", testKey, terms)
+	if err != nil {
+		t.Fatalf("AllocateAliases: %v", err)
+	}
+	if got[0].Alias != got[2].Alias {
+		t.Errorf("case variants: %q != %q", got[0].Alias, got[2].Alias)
+	}
+	used := map[string]bool{}
+	for _, i := range []int{0, 1, 3} {
+		a := got[i].Alias
+		if a == "PHARMEX" || used[a] {
+			t.Errorf("allocation = %+v", got)
+		}
+		used[a] = true
+	}
+
+	// Independent of request order.
+	again, err := AllocateAliases(reg, "company", testKey, []string{"Ipsen", "Sanofi", "Servier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Alias != got[3].Alias || again[1].Alias != got[0].Alias || again[2].Alias != got[1].Alias {
+		t.Errorf("allocation depends on order: %+v / %+v", got, again)
+	}
+}
+
+func TestAllocateAliases_MatchesAliasStrategy(t *testing.T) {
+	reg := setupAllocRegistry(t)
+	got, err := AllocateAliases(reg, "company", testKey, []string{"Sanofi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := aliasStrategy(&Input{Key: testKey, Canonical: "sanofi", Registry: reg, AliasDomain: "company"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Alias != want {
+		t.Errorf("allocated %q, alias strategy picks %q", got[0].Alias, want)
+	}
+}
+
+func TestAllocateAliases_Errors(t *testing.T) {
+	reg := setupAllocRegistry(t)
+	if _, err := AllocateAliases(reg, "company", testKey, []string{"a", "b", "c", "d"}); err == nil {
+		t.Error("expected error when terms outnumber usable aliases")
+	}
+	if _, err := AllocateAliases(reg, "nope", testKey, []string{"a"}); err == nil {
+		t.Error("expected error for a missing pool")
+	}
+	if _, err := AllocateAliases(reg, "company", []byte("short"), []string{"a"}); err == nil {
+		t.Error("expected error for a short key")
+	}
+}
@@ -0,0 +1,124 @@
+// CLAUDE:SUMMARY FF1 format-preserving encryption (NIST SP 800-38G) over numeral strings of any radix, AES-based.
+// CLAUDE:DEPENDS pkg/pseudo/fpe.go
+package pseudo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// ff1 is an FF1 cipher for a fixed AES key and radix. Numeral strings are
+// slices of digits in [0, radix).
+type ff1 struct {
+	block cipher.Block
+	radix int
+}
+
+func newFF1(key []byte, radix int) (*ff1, error) {
+	if radix < 2 || radix > 1<<16 {
+		return nil, fmt.Errorf("ff1: radix %d out of range", radix)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("ff1: %w", err)
+	}
+	return &ff1{block: block, radix: radix}, nil
+}
+
+// encrypt applies FF1.Encrypt (SP 800-38G, algorithm 7) to x under tweak t.
+// x must hold at least 2 numerals.
+func (f *ff1) encrypt(x []int, t []byte) ([]int, error) {
+	n := len(x)
+	if n < 2 {
+		return nil, fmt.Errorf("ff1: input too short (%d numerals)", n)
+	}
+	u, v := n/2, n-n/2
+	a, b := x[:u], x[u:]
+
+	bLen := int(math.Ceil(math.Ceil(float64(v)*math.Log2(float64(f.radix))) / 8))
+	d := 4*((bLen+3)/4) + 4
+
+	p := make([]byte, 16)
+	p[0], p[1], p[2] = 1, 2, 1
+	p[3], p[4], p[5] = byte(f.radix>>16), byte(f.radix>>8), byte(f.radix)
+	p[6], p[7] = 10, byte(u)
+	binary.BigEndian.PutUint32(p[8:], uint32(n))
+	binary.BigEndian.PutUint32(p[12:], uint32(len(t)))
+
+	pad := (16 - (len(t)+bLen+1)%16) % 16
+	q := make([]byte, len(t)+pad+1+bLen)
+	copy(q, t)
+
+	radix := big.NewInt(int64(f.radix))
+	modU := new(big.Int).Exp(radix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(radix, big.NewInt(int64(v)), nil)
+	c, y := new(big.Int), new(big.Int)
+
+	for i := 0; i < 10; i++ {
+		q[len(t)+pad] = byte(i)
+		numB := num(b, radix).Bytes()
+		nb := q[len(t)+pad+1:]
+		for j := range nb {
+			nb[j] = 0
+		}
+		copy(nb[bLen-len(numB):], numB)
+
+		r := f.prf(append(append([]byte{}, p...), q...))
+		s := append([]byte{}, r...)
+		for j := 1; len(s) < d; j++ {
+			var blk [16]byte
+			copy(blk[:], r)
+			binary.BigEndian.PutUint64(blk[8:], binary.BigEndian.Uint64(blk[8:])^uint64(j))
+			f.block.Encrypt(blk[:], blk[:])
+			s = append(s, blk[:]...)
+		}
+		y.SetBytes(s[:d])
+
+		m, mod := u, modU
+		if i%2 == 1 {
+			m, mod = v, modV
+		}
+		c.Add(num(a, radix), y)
+		c.Mod(c, mod)
+		a, b = b, str(c, radix, m)
+	}
+	return append(append([]int{}, a...), b...), nil
+}
+
+// prf is the CBC-MAC of x with a zero IV; len(x) is a multiple of 16.
+func (f *ff1) prf(x []byte) []byte {
+	y := make([]byte, 16)
+	for i := 0; i < len(x); i += 16 {
+		for j := 0; j < 16; j++ {
+			y[j] ^= x[i+j]
+		}
+		f.block.Encrypt(y, y)
+	}
+	return y
+}
+
+// num interprets x as a big-endian number in the given radix.
+func num(x []int, radix *big.Int) *big.Int {
+	n := new(big.Int)
+	for _, d := range x {
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+	return n
+}
+
+// str writes n as m numerals in the given radix, big-endian.
+func str(n *big.Int, radix *big.Int, m int) []int {
+	out := make([]int, m)
+	n = new(big.Int).Set(n)
+	r := new(big.Int)
+	for i := m - 1; i >= 0; i-- {
+		n.QuoRem(n, radix, r)
+		out[i] = int(r.Int64())
+	}
+	return out
+}
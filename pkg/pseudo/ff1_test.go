@@ -0,0 +1,45 @@
+package pseudo
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+)
+
+// NIST SP 800-38G FF1 samples 1-3 (AES-128).
+func TestFF1_NISTSamples(t *testing.T) {
+	key, _ := hex.DecodeString("2B7E151628AED2A6ABF7158809CF4F3C")
+	tests := []struct {
+		radix int
+		tweak string
+		in    string
+		want  string
+	}{
+		{10, "", "0123456789", "2433477484"},
+		{10, "39383736353433323130", "0123456789", "6124200773"},
+		{36, "3737373770717273373737", "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+	}
+	for _, tt := range tests {
+		f, err := newFF1(key, tt.radix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tweak, _ := hex.DecodeString(tt.tweak)
+		x := make([]int, len(tt.in))
+		for i, c := range tt.in {
+			v, _ := strconv.ParseInt(string(c), 36, 0)
+			x[i] = int(v)
+		}
+		y, err := f.encrypt(x, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		for _, d := range y {
+			got += strconv.FormatInt(int64(d), 36)
+		}
+		if got != tt.want {
+			t.Errorf("FF1(radix %d, %s) = %s, want %s", tt.radix, tt.in, got, tt.want)
+		}
+	}
+}
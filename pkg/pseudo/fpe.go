@@ -0,0 +1,201 @@
+// CLAUDE:SUMMARY fpe pseudonymization strategy: FF1-encrypts the digits and letters of a pattern match, recomputes its check when the validator declares how, and cycle-walks until the result matches the same pattern and passes its validator.
+// CLAUDE:DEPENDS pkg/pseudo/ff1.go, pkg/pseudo/strategies.go, pkg/dict/dict.go
+package pseudo
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// maxCycles bounds cycle-walking, which runs FF1 and the pattern's validator
+// once per cycle.
+const maxCycles = 800
+
+// minDensity is the least share of valid values among the strings shaped like
+// a term (see dict.Dictionary.PatternDensity) fpe accepts: cycle-walking
+// then needs at most 160 cycles on average, and fails within maxCycles for
+// fewer than 1 term in 100. Sparser patterns are rejected without cycling.
+const minDensity = 1.0 / 160
+
+func init() {
+	RegisterStrategy("fpe", fpeStrategy)
+}
+
+// fpeStrategy replaces a pattern match with another value of the same pattern
+// that passes the same validator: a pseudonymized IBAN still passes mod97, a
+// pseudonymized NIR still looks like a NIR.
+//
+// Digits and ASCII letters are encrypted separately with FF1 (radix 10 and
+// 26, letters case-insensitively with their case restored), so every
+// character keeps its class and position; separators and the pattern's
+// literal prefix ("FR" of a French IBAN) are kept. The result is then
+// re-encrypted (cycle-walking) until it matches the pattern and validator
+// again. Cycle-walking a permutation yields a permutation of the valid
+// values, so distinct terms never collide and any validator, built-in or
+// registered, is honored without knowing how its check digits work.
+//
+// When the validator declares how its check is computed (the mod-97 key of
+// a NIR, see dict.RegisterCheckDigits), only the payload is encrypted and
+// the check is recomputed from it: the result always validates, and
+// cycle-walking only restores the shape of the payload (the sex digit and
+// month of a NIR).
+//
+// The AES key is derived from the client key; the tweak binds the dictionary
+// and pattern, so the same digits in two schemes encrypt differently.
+func fpeStrategy(in *Input) (string, error) {
+	name := in.Match.Metadata["pattern"]
+	d, ok := in.Registry.Get(in.Match.DictID)
+	if !ok || name == "" {
+		return "", fmt.Errorf("fpe requires a pattern dictionary")
+	}
+	if density := d.PatternDensity(name, in.Term); density < minDensity {
+		return "", fmt.Errorf("fpe: pattern %s has too few valid values to cycle-walk", name)
+	}
+	aesKey := mac(in.Key, "fpe")
+	tweak := []byte(in.Match.DictID + "\x00" + name)
+
+	digits, err := newFF1(aesKey, 10)
+	if err != nil {
+		return "", err
+	}
+	letters, err := newFF1(aesKey, 26)
+	if err != nil {
+		return "", err
+	}
+
+	check := d.PatternCheck(name, in.Term)
+	checkLen := 0
+	if check != nil {
+		checkLen = check.Len
+	}
+	s := newFPEString(in.Term, len(d.PatternPrefix(name, in.Term)), checkLen)
+	for i := 0; i < maxCycles; i++ {
+		if in.Ctx != nil {
+			if err := in.Ctx.Err(); err != nil {
+				return "", err
+			}
+		}
+		if err := s.encrypt(digits, letters, tweak, aesKey); err != nil {
+			return "", err
+		}
+		if check != nil && !s.setCheck(check) {
+			continue
+		}
+		out := s.String()
+		if d.MatchesPattern(name, out) {
+			return out, nil
+		}
+	}
+	return "", fmt.Errorf("fpe: no valid %s value found after %d cycles", name, maxCycles)
+}
+
+// fpeString splits a term into fixed characters and two numeral strings: the
+// digits and the letters it contains, in order.
+type fpeString struct {
+	runes   []rune
+	digits  []int // values, in order of appearance
+	letters []int
+	dPos    []int // rune positions of digits
+	lPos    []int
+	lower   []bool // letter was lowercase
+	cPos    []int  // rune positions of the check, set by setCheck
+}
+
+// newFPEString analyzes term; the first fixed non-space characters (the
+// pattern's literal prefix) are left untouched, and so are the last check
+// ones until setCheck computes them.
+func newFPEString(term string, fixed, check int) *fpeString {
+	s := &fpeString{runes: []rune(term)}
+	for i := len(s.runes) - 1; i >= 0 && len(s.cPos) < check; i-- {
+		if s.runes[i] != ' ' {
+			s.cPos = append([]int{i}, s.cPos...)
+		}
+	}
+	for i, r := range s.runes {
+		if r == ' ' || (len(s.cPos) > 0 && i >= s.cPos[0]) {
+			continue
+		}
+		if fixed > 0 {
+			fixed--
+			continue
+		}
+		switch {
+		case r >= '0' && r <= '9':
+			s.digits = append(s.digits, int(r-'0'))
+			s.dPos = append(s.dPos, i)
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+			s.letters = append(s.letters, int(unicode.ToUpper(r)-'A'))
+			s.lPos = append(s.lPos, i)
+			s.lower = append(s.lower, unicode.IsLower(r))
+		}
+	}
+	return s
+}
+
+// setCheck writes the check c computes from the rest of the string, and
+// reports false when it cannot compute one.
+func (s *fpeString) setCheck(c *dict.CheckDigits) bool {
+	out := []rune(s.String())
+	var payload []rune
+	for _, r := range out[:s.cPos[0]] {
+		if r != ' ' {
+			payload = append(payload, r)
+		}
+	}
+	check, ok := c.Compute(string(payload))
+	if !ok || len([]rune(check)) != len(s.cPos) {
+		return false
+	}
+	for i, r := range []rune(check) {
+		s.runes[s.cPos[i]] = r
+	}
+	return true
+}
+
+// encrypt applies one round of the permutation to both numeral strings.
+func (s *fpeString) encrypt(digits, letters *ff1, tweak, key []byte) error {
+	var err error
+	if s.digits, err = permute(digits, s.digits, tweak, key); err != nil {
+		return err
+	}
+	s.letters, err = permute(letters, s.letters, tweak, key)
+	return err
+}
+
+func (s *fpeString) String() string {
+	out := append([]rune{}, s.runes...)
+	for i, p := range s.dPos {
+		out[p] = '0' + rune(s.digits[i])
+	}
+	for i, p := range s.lPos {
+		r := 'A' + rune(s.letters[i])
+		if s.lower[i] {
+			r = unicode.ToLower(r)
+		}
+		out[p] = r
+	}
+	return string(out)
+}
+
+// permute encrypts x with FF1, which needs at least two numerals; a single
+// numeral goes through a keyed shuffle of the alphabet instead.
+func permute(f *ff1, x []int, tweak, key []byte) ([]int, error) {
+	switch len(x) {
+	case 0:
+		return x, nil
+	case 1:
+		ks := newKeystream(key, "fpe-single", string(tweak))
+		perm := make([]int, f.radix)
+		for i := range perm {
+			perm[i] = i
+		}
+		for i := len(perm) - 1; i > 0; i-- {
+			j := ks.intn(i + 1)
+			perm[i], perm[j] = perm[j], perm[i]
+		}
+		return []int{perm[x[0]]}, nil
+	}
+	return f.encrypt(x, tweak)
+}
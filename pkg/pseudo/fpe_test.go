@@ -0,0 +1,172 @@
+package pseudo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// loadRepoDicts builds a registry from manifests of the repository's dicts/.
+func loadRepoDicts(t *testing.T, ids ...string) *dict.Registry {
+	t.Helper()
+	dir := t.TempDir()
+	for _, id := range ids {
+		data, err := os.ReadFile(filepath.Join("..", "..", "dicts", id, "manifest.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, id), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, id, "manifest.yaml"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+func TestFPE_PassesValidators(t *testing.T) {
+	reg := loadRepoDicts(t, "iban", "credit-card", "national-ids-eu", "nir-fr")
+
+	tests := []struct {
+		term string
+		dict string
+	}{
+		{"FR7630006000011234567890189", "iban"},
+		{"FR76 3000 6000 0112 3456 7890 189", "iban"},
+		{"DE89370400440532013000", "iban"},
+		{"4111111111111111", "credit-card"},
+		{"44051401359", "national-ids-eu"}, // PESEL: checksum and birth date
+		{"185017512345609", "nir-fr"},      // key recomputed
+		{"2 85 02 2A 123 456 32", "nir-fr"},
+	}
+	for _, tt := range tests {
+		res := pseudonymize(t, reg, &Request{Terms: []string{tt.term}, Strategy: "fpe", Opts: &dict.ClassifyOptions{Dicts: []string{tt.dict}}})[0]
+		if !res.Replaced || res.Pseudonym == tt.term {
+			t.Errorf("%s: result = %+v", tt.term, res)
+			continue
+		}
+		d, _ := reg.Get(tt.dict)
+		pattern := reg.Classify(tt.term, &dict.ClassifyOptions{Dicts: []string{tt.dict}}).Matches[0].Metadata["pattern"]
+		if !d.MatchesPattern(pattern, res.Pseudonym) {
+			t.Errorf("%s → %s: does not match %s/%s", tt.term, res.Pseudonym, tt.dict, pattern)
+		}
+		if len(res.Pseudonym) != len(tt.term) || strings.Count(res.Pseudonym, " ") != strings.Count(tt.term, " ") {
+			t.Errorf("%s → %s: format not preserved", tt.term, res.Pseudonym)
+		}
+		if strings.HasPrefix(tt.term, "FR") && !strings.HasPrefix(res.Pseudonym, "FR") {
+			t.Errorf("%s → %s: IBAN country changed", tt.term, res.Pseudonym)
+		}
+
+		again := pseudonymize(t, reg, &Request{Terms: []string{tt.term}, Strategy: "fpe", Opts: &dict.ClassifyOptions{Dicts: []string{tt.dict}}})[0]
+		if again.Pseudonym != res.Pseudonym {
+			t.Errorf("%s: not deterministic (%s, %s)", tt.term, res.Pseudonym, again.Pseudonym)
+		}
+	}
+}
+
+func TestFPE_RegisteredValidator(t *testing.T) {
+	dict.RegisterValidator("test-digit-sum-even", func(s string) bool {
+		sum := 0
+		for _, c := range s {
+			sum += int(c - '0')
+		}
+		return sum%2 == 0
+	})
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "codes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "codes", "manifest.yaml"), []byte(`id: codes
+version: "1.0"
+entity_type: code
+source: test
+method: pattern
+entity_spec:
+  sensitivity: high
+  pseudo_strategy: fpe
+patterns:
+  - name: code
+    regex: "^\\d{8}$"
+    validator: test-digit-sum-even
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	terms := []string{"12345678", "11111111", "20000000"}
+	res := pseudonymize(t, reg, &Request{Terms: terms})
+	seen := map[string]bool{}
+	d, _ := reg.Get("codes")
+	for i, r := range res {
+		if r.Strategy != "fpe" || !d.MatchesPattern("code", r.Pseudonym) {
+			t.Errorf("%s → %+v", terms[i], r)
+		}
+		if seen[r.Pseudonym] {
+			t.Errorf("collision on %s", r.Pseudonym)
+		}
+		seen[r.Pseudonym] = true
+	}
+}
+
+func TestFPE_RejectsSparsePatterns(t *testing.T) {
+	dict.RegisterValidator("test-ends-000", func(s string) bool { return strings.HasSuffix(s, "000") })
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "codes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "codes", "manifest.yaml"), []byte(`id: codes
+version: "1.0"
+entity_type: code
+source: test
+method: pattern
+patterns:
+  - name: code
+    regex: "^\\d{8}$"
+    validator: test-ends-000
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	res := pseudonymize(t, reg, &Request{Terms: []string{"12345000", "FR7630006000011234567890189"}, Strategy: "fpe"})
+	if res[0].Replaced || !strings.Contains(res[0].Error, "too few valid values") {
+		t.Errorf("result = %+v, want the code rejected as too sparse", res[0])
+	}
+	if res[1].Error != "" {
+		t.Errorf("unmatched term: result = %+v", res[1])
+	}
+}
+
+func TestFPE_Cancelled(t *testing.T) {
+	reg := loadRepoDicts(t, "iban")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Pseudonymize(ctx, reg, &Request{Terms: []string{"FR7630006000011234567890189"}, Key: testKey, Strategy: "fpe"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestFPE_RequiresPatternDict(t *testing.T) {
+	reg := setupRegistry(t)
+	if res := pseudonymize(t, reg, &Request{Terms: []string{"DUPONT"}, Strategy: "fpe"}); res[0].Error == "" {
+		t.Errorf("result = %+v, want an error for fpe on a lookup dictionary", res[0])
+	}
+}
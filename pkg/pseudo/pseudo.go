@@ -0,0 +1,222 @@
+// CLAUDE:SUMMARY Deterministic keyed pseudonymization of classified terms, dispatched on EntitySpec.pseudo_strategy through a strategy registry.
+// CLAUDE:DEPENDS pkg/dict/registry.go, pkg/pseudo/strategies.go, pkg/pseudo/fpe.go
+// CLAUDE:EXPORTS Request, Result, Input, StrategyFunc, Pseudonymize, RegisterStrategy, GetStrategy, StrategyNames, MinKeyLen
+package pseudo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+// MinKeyLen is the minimum client key length in bytes.
+const MinKeyLen = 16
+
+// Request asks for pseudonyms of a list of terms. Key is supplied by the
+// client on every request and is never stored: the same key yields the same
+// pseudonyms, so a client keeps a document consistent without the server
+// remembering anything.
+type Request struct {
+	Terms []string
+	Key   []byte
+	Opts  *dict.ClassifyOptions
+	// Strategy overrides the pseudo_strategy of every matched dictionary.
+	Strategy string
+	// AliasDomain selects the alias pool of the alias strategy; it defaults
+	// to the entity type of the match.
+	AliasDomain string
+}
+
+// Result is the pseudonym of one term. Terms matching no dictionary with a
+// pseudonymization strategy, or only public ones, are returned unchanged with
+// Replaced false; so are terms their strategy failed on, with the reason in
+// Error.
+type Result struct {
+	Term        string `json:"term"`
+	Pseudonym   string `json:"pseudonym"`
+	Replaced    bool   `json:"replaced"`
+	Strategy    string `json:"strategy,omitempty"`
+	DictID      string `json:"dict_id,omitempty"`
+	EntityType  string `json:"entity_type,omitempty"`
+	Sensitivity string `json:"sensitivity,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Input is what a strategy gets to build a pseudonym from.
+type Input struct {
+	Ctx       context.Context // the request's; strategies that loop check it
+	Term      string          // as sent by the client
+	Canonical string          // normalized form: equal canonicals get equal pseudonyms
+	Key       []byte
+	Match     dict.Match
+	Registry  *dict.Registry
+	// AliasDomain is the alias pool requested for this term.
+	AliasDomain string
+}
+
+// StrategyFunc builds the pseudonym of a term.
+type StrategyFunc func(in *Input) (string, error)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = make(map[string]StrategyFunc)
+)
+
+// RegisterStrategy adds a named strategy to the global registry, replacing any
+// previous strategy with the same name.
+func RegisterStrategy(name string, fn func(in *Input) (string, error)) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	strategies[name] = fn
+}
+
+// GetStrategy returns a registered strategy by name, or an error if not found.
+func GetStrategy(name string) (StrategyFunc, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	fn, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown pseudo_strategy %q", name)
+	}
+	return fn, nil
+}
+
+// StrategyNames returns the names of all registered strategies, sorted.
+func StrategyNames() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sensitivityRank orders EntitySpec sensitivities: when a term matches several
+// dictionaries, the most sensitive one decides how it is pseudonymized.
+var sensitivityRank = map[string]int{
+	"public":    0,
+	"low":       1,
+	"":          1,
+	"medium":    2,
+	"high":      3,
+	"financial": 4,
+}
+
+// Pseudonymize classifies every term and replaces it according to the
+// pseudo_strategy of its most sensitive match (or req.Strategy). A term its
+// strategy fails on is left unchanged with the error on its Result; the
+// other terms are still replaced. Strategies that loop, such as fpe, stop
+// once ctx is cancelled, which fails the whole request.
+func Pseudonymize(ctx context.Context, reg *dict.Registry, req *Request) ([]Result, error) {
+	if len(req.Key) < MinKeyLen {
+		return nil, fmt.Errorf("key must be at least %d bytes", MinKeyLen)
+	}
+	if req.Strategy != "" {
+		if _, err := GetStrategy(req.Strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]Result, len(req.Terms))
+	for i, term := range req.Terms {
+		res, err := pseudonymizeTerm(ctx, reg, req, term)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			res.Error = err.Error()
+		}
+		results[i] = res
+	}
+	return results, nil
+}
+
+func pseudonymizeTerm(ctx context.Context, reg *dict.Registry, req *Request, term string) (Result, error) {
+	res := Result{Term: term, Pseudonym: term}
+	cr := reg.Classify(term, req.Opts)
+
+	best := -1
+	for i, m := range cr.Matches {
+		if !eligible(m, req.Strategy) {
+			continue
+		}
+		if best < 0 || rank(m) > rank(cr.Matches[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return res, nil
+	}
+	m := cr.Matches[best]
+
+	name := req.Strategy
+	if name == "" {
+		name = m.EntitySpec.PseudoStrategy
+	}
+	fn, err := GetStrategy(name)
+	if err != nil {
+		return res, fmt.Errorf("dict %s: %w", m.DictID, err)
+	}
+
+	domain := req.AliasDomain
+	if domain == "" {
+		domain = m.EntityType
+	}
+	pseudonym, err := fn(&Input{
+		Ctx:         ctx,
+		Term:        term,
+		Canonical:   canonical(cr, m),
+		Key:         req.Key,
+		Match:       m,
+		Registry:    reg,
+		AliasDomain: domain,
+	})
+	if err != nil {
+		return res, fmt.Errorf("dict %s: %w", m.DictID, err)
+	}
+
+	res.Pseudonym = pseudonym
+	res.Replaced = true
+	res.Strategy = name
+	res.DictID = m.DictID
+	res.EntityType = m.EntityType
+	if m.EntitySpec != nil {
+		res.Sensitivity = m.EntitySpec.Sensitivity
+	}
+	return res, nil
+}
+
+// eligible reports whether a match can drive pseudonymization: it needs a
+// strategy (its own or the request's) and must not be public data.
+func eligible(m dict.Match, override string) bool {
+	if m.EntitySpec == nil {
+		return override != ""
+	}
+	if m.EntitySpec.Sensitivity == "public" {
+		return false
+	}
+	return override != "" || m.EntitySpec.PseudoStrategy != ""
+}
+
+func rank(m dict.Match) int {
+	if m.EntitySpec == nil {
+		return sensitivityRank[""]
+	}
+	return sensitivityRank[m.EntitySpec.Sensitivity]
+}
+
+// canonical is the normalized term the pseudonym derives from. Pattern matches
+// ignore spaces like the matcher does, so "FR76 3000…" and "FR763000…" share a
+// pseudonym.
+func canonical(cr *dict.ClassifyResult, m dict.Match) string {
+	if _, ok := m.Metadata["pattern"]; ok {
+		return strings.ToUpper(strings.ReplaceAll(cr.Term, " ", ""))
+	}
+	return strings.Join(strings.Fields(cr.Normalized), " ")
+}
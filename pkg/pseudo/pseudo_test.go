@@ -0,0 +1,226 @@
+package pseudo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func setupRegistry(t *testing.T) *dict.Registry {
+	t.Helper()
+	dir := t.TempDir()
+	writeDict(t, dir, "noms-fr", `id: noms-fr
+version: "1.0"
+jurisdiction: fr
+entity_type: surname
+source: test
+data_file: data.csv
+entity_spec:
+  sensitivity: medium
+  pseudo_strategy: alias
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "term"
+`, "term\nDUPONT\nMARTIN\n")
+	writeDict(t, dir, "iban", `id: iban
+version: "1.0"
+jurisdiction: intl
+entity_type: iban
+source: test
+method: pattern
+entity_spec:
+  sensitivity: financial
+  pseudo_strategy: hash
+  pseudo_prefix: "IBAN-"
+patterns:
+  - name: iban_fr
+    regex: "^FR\\d{12}[A-Z0-9]{11}\\d{2}$"
+    validator: mod97
+`, "")
+	writeDict(t, dir, "plates", `id: plates
+version: "1.0"
+jurisdiction: fr
+entity_type: vehicle_plate
+source: test
+method: pattern
+entity_spec:
+  sensitivity: high
+  pseudo_strategy: format_preserving
+patterns:
+  - name: siv
+    regex: "^[A-Z]{2}-\\d{3}-[A-Z]{2}$"
+`, "")
+	writeDict(t, dir, "lei", `id: lei
+version: "1.0"
+jurisdiction: intl
+entity_type: lei
+source: test
+method: pattern
+entity_spec:
+  sensitivity: public
+  pseudo_strategy: hash
+patterns:
+  - name: lei
+    regex: "^[A-Z0-9]{18}\\d{2}$"
+`, "")
+	writeDict(t, dir, "surname-aliases", `id: surname-aliases
+version: "1.0"
+jurisdiction: fr
+entity_type: alias
+source: manual
+type: alias_pool
+domain: surname
+entries:
+  - alias: "LEBLANC"
+  - alias: "MOREAU"
+  - alias: "GIRARD"
+`, "")
+
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+// writeDict writes the dictionary id into dir: its manifest and, unless
+// data is empty, its data.csv.
+func writeDict(t *testing.T, dir, id, manifest, data string) {
+	t.Helper()
+	d := filepath.Join(dir, id)
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "manifest.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data != "" {
+		if err := os.WriteFile(filepath.Join(d, "data.csv"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func pseudonymize(t *testing.T, reg *dict.Registry, req *Request) []Result {
+	t.Helper()
+	if req.Key == nil {
+		req.Key = testKey
+	}
+	results, err := Pseudonymize(context.Background(), reg, req)
+	if err != nil {
+		t.Fatalf("Pseudonymize: %v", err)
+	}
+	return results
+}
+
+func TestPseudonymize_Hash(t *testing.T) {
+	reg := setupRegistry(t)
+	res := pseudonymize(t, reg, &Request{Terms: []string{"FR7630006000011234567890189", "FR76 3000 6000 0112 3456 7890 189"}})
+
+	if !regexp.MustCompile(`^IBAN-[0-9a-f]{16}$`).MatchString(res[0].Pseudonym) {
+		t.Errorf("pseudonym = %q, want IBAN-<16 hex>", res[0].Pseudonym)
+	}
+	if res[0].Pseudonym != res[1].Pseudonym {
+		t.Errorf("spaced IBAN: %q != %q", res[1].Pseudonym, res[0].Pseudonym)
+	}
+	if !res[0].Replaced || res[0].Strategy != "hash" || res[0].DictID != "iban" || res[0].Sensitivity != "financial" {
+		t.Errorf("result = %+v", res[0])
+	}
+
+	otherKey := pseudonymize(t, reg, &Request{Terms: []string{"FR7630006000011234567890189"}, Key: []byte("another key of 16+ bytes")})
+	if otherKey[0].Pseudonym == res[0].Pseudonym {
+		t.Error("different keys gave the same pseudonym")
+	}
+}
+
+func TestPseudonymize_FormatPreserving(t *testing.T) {
+	reg := setupRegistry(t)
+	res := pseudonymize(t, reg, &Request{Terms: []string{"AB-123-CD", "AB-123-CD"}})
+	if !regexp.MustCompile(`^[A-Z]{2}-\d{3}-[A-Z]{2}$`).MatchString(res[0].Pseudonym) {
+		t.Errorf("pseudonym = %q, want the AA-123-AA shape", res[0].Pseudonym)
+	}
+	if res[0].Pseudonym == "AB-123-CD" || res[0].Pseudonym != res[1].Pseudonym {
+		t.Errorf("pseudonyms = %q, %q", res[0].Pseudonym, res[1].Pseudonym)
+	}
+}
+
+func TestPseudonymize_Alias(t *testing.T) {
+	reg := setupRegistry(t)
+	res := pseudonymize(t, reg, &Request{Terms: []string{"DUPONT", "dupont", "MARTIN"}})
+	pool := []string{"LEBLANC", "MOREAU", "GIRARD"}
+	for _, r := range res {
+		if r.Strategy != "alias" || !containsString(pool, r.Pseudonym) {
+			t.Errorf("result = %+v, want an alias from %v", r, pool)
+		}
+	}
+	if res[0].Pseudonym != res[1].Pseudonym {
+		t.Errorf("case variants: %q != %q", res[0].Pseudonym, res[1].Pseudonym)
+	}
+
+	res = pseudonymize(t, reg, &Request{Terms: []string{"DUPONT", "xyzzy"}, AliasDomain: "nope"})
+	if res[0].Replaced || res[0].Pseudonym != "DUPONT" || res[0].Error == "" {
+		t.Errorf("missing alias pool: result = %+v, want the term kept with an error", res[0])
+	}
+	if res[1].Error != "" {
+		t.Errorf("unmatched term: result = %+v", res[1])
+	}
+}
+
+func TestPseudonymize_Unchanged(t *testing.T) {
+	reg := setupRegistry(t)
+	// Unknown term, and a public LEI.
+	res := pseudonymize(t, reg, &Request{Terms: []string{"xyzzy", "529900T8BM49AURSDO55"}})
+	for _, r := range res {
+		if r.Replaced || r.Pseudonym != r.Term {
+			t.Errorf("result = %+v, want unchanged", r)
+		}
+	}
+}
+
+func TestPseudonymize_StrategyOverride(t *testing.T) {
+	reg := setupRegistry(t)
+	res := pseudonymize(t, reg, &Request{Terms: []string{"DUPONT"}, Strategy: "hash"})
+	if res[0].Strategy != "hash" || strings.Contains(res[0].Pseudonym, "DUPONT") || len(res[0].Pseudonym) != hashLen {
+		t.Errorf("result = %+v", res[0])
+	}
+	if _, err := Pseudonymize(context.Background(), reg, &Request{Terms: []string{"DUPONT"}, Key: testKey, Strategy: "rot13"}); err == nil {
+		t.Error("expected error for an unknown strategy")
+	}
+}
+
+func TestPseudonymize_ShortKey(t *testing.T) {
+	reg := setupRegistry(t)
+	if _, err := Pseudonymize(context.Background(), reg, &Request{Terms: []string{"DUPONT"}, Key: []byte("short")}); err == nil {
+		t.Error("expected error for a short key")
+	}
+}
+
+func TestKeystream_Intn(t *testing.T) {
+	ks := newKeystream(testKey, "test")
+	seen := make([]int, 10)
+	for i := 0; i < 10000; i++ {
+		seen[ks.intn(10)]++
+	}
+	for d, n := range seen {
+		if n < 800 || n > 1200 {
+			t.Errorf("digit %d drawn %d times out of 10000", d, n)
+		}
+	}
+}
+
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
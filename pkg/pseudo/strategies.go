@@ -0,0 +1,119 @@
+// CLAUDE:SUMMARY Built-in pseudonymization strategies: keyed HMAC hash with prefix, format-preserving character substitution, alias pool pick.
+// CLAUDE:DEPENDS pkg/pseudo/pseudo.go
+package pseudo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"unicode"
+)
+
+// hashLen is the number of hex digits kept from the HMAC (64 bits).
+const hashLen = 16
+
+func init() {
+	RegisterStrategy("hash", hashStrategy)
+	RegisterStrategy("format_preserving", formatPreservingStrategy)
+	RegisterStrategy("alias", aliasStrategy)
+}
+
+// hashStrategy returns the pseudo_prefix followed by a truncated
+// HMAC-SHA256 of the canonical term ("IBAN-3f9a04c1d2e8b7a6").
+func hashStrategy(in *Input) (string, error) {
+	sum := mac(in.Key, "hash", in.Match.EntityType, in.Canonical)
+	return prefix(in) + hex.EncodeToString(sum)[:hashLen], nil
+}
+
+// formatPreservingStrategy substitutes every digit with a digit and every
+// letter with a letter of the same case, keeping punctuation and spaces, so
+// "AB-123-CD" becomes "QF-804-ZT". It keeps the shape of an identifier but
+// not its checksum.
+func formatPreservingStrategy(in *Input) (string, error) {
+	ks := newKeystream(in.Key, "format_preserving", in.Match.EntityType, in.Canonical)
+	out := make([]rune, 0, len(in.Term))
+	for _, r := range in.Term {
+		switch {
+		case r >= '0' && r <= '9':
+			r = '0' + rune(ks.intn(10))
+		case unicode.IsUpper(r):
+			r = 'A' + rune(ks.intn(26))
+		case unicode.IsLower(r):
+			r = 'a' + rune(ks.intn(26))
+		}
+		out = append(out, r)
+	}
+	return string(out), nil
+}
+
+// aliasStrategy picks a usable alias from the pool of the requested domain.
+// The pick depends only on the key and the canonical term; it is the
+// preferred alias of AllocateAliases.
+func aliasStrategy(in *Input) (string, error) {
+	pool, err := usableAliases(in.Registry, in.AliasDomain)
+	if err != nil {
+		return "", err
+	}
+	return pool[preferredAlias(in.Key, in.AliasDomain, in.Canonical, len(pool))].Alias, nil
+}
+
+func prefix(in *Input) string {
+	if in.Match.EntitySpec == nil {
+		return ""
+	}
+	return in.Match.EntitySpec.PseudoPrefix
+}
+
+// mac computes HMAC-SHA256 over length-prefixed parts, so ("ab", "c") and
+// ("a", "bc") never collide.
+func mac(key []byte, parts ...string) []byte {
+	h := hmac.New(sha256.New, key)
+	var n [4]byte
+	for _, p := range parts {
+		binary.BigEndian.PutUint32(n[:], uint32(len(p)))
+		h.Write(n[:])
+		h.Write([]byte(p))
+	}
+	return h.Sum(nil)
+}
+
+// keystream is a deterministic byte stream derived from a key and a context:
+// HMAC-SHA256(key, seed || counter) blocks, seed being the MAC of the parts.
+type keystream struct {
+	key     []byte
+	seed    []byte
+	counter uint32
+	buf     []byte
+}
+
+func newKeystream(key []byte, parts ...string) *keystream {
+	return &keystream{key: key, seed: mac(key, parts...)}
+}
+
+func (ks *keystream) next() byte {
+	if len(ks.buf) == 0 {
+		h := hmac.New(sha256.New, ks.key)
+		h.Write(ks.seed)
+		var n [4]byte
+		binary.BigEndian.PutUint32(n[:], ks.counter)
+		h.Write(n[:])
+		ks.buf = h.Sum(nil)
+		ks.counter++
+	}
+	b := ks.buf[0]
+	ks.buf = ks.buf[1:]
+	return b
+}
+
+// intn returns a uniform integer in [0, n) by rejection sampling on 32-bit
+// draws.
+func (ks *keystream) intn(n int) int {
+	limit := ^uint32(0) - ^uint32(0)%uint32(n)
+	for {
+		v := uint32(ks.next())<<24 | uint32(ks.next())<<16 | uint32(ks.next())<<8 | uint32(ks.next())
+		if v < limit {
+			return int(v % uint32(n))
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// CLAUDE:SUMMARY Realistic surrogates: replaces a dictionary term with another entry of the same dictionary sharing its department, gender, form and frequency class, picked deterministically from a seed.
+// CLAUDE:DEPENDS pkg/dict/dict.go, pkg/pseudo/strategies.go
+// CLAUDE:EXPORTS SurrogateRequest, SurrogateResult, Surrogate
+package pseudo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+)
+
+func init() {
+	RegisterStrategy("surrogate", surrogateStrategy)
+}
+
+// SurrogateRequest asks for realistic replacements of a list of terms. The
+// same seed yields the same surrogates.
+type SurrogateRequest struct {
+	Terms []string
+	Seed  string
+	Opts  *dict.ClassifyOptions
+}
+
+// SurrogateResult is the surrogate of one term. Conditions are the entry
+// attributes the surrogate shares with the term.
+type SurrogateResult struct {
+	Term       string            `json:"term"`
+	Surrogate  string            `json:"surrogate"`
+	Replaced   bool              `json:"replaced"`
+	DictID     string            `json:"dict_id,omitempty"`
+	EntityType string            `json:"entity_type,omitempty"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// Surrogate replaces every term with another entry of the first dictionary it
+// matches: "DUPONT" becomes another surname of similar frequency,
+// "Jean-Pierre" another male compound first name, a commune another commune
+// of the same department. Terms matching no lookup dictionary, or alone in
+// their stratum, are returned unchanged with Replaced false.
+func Surrogate(reg *dict.Registry, req *SurrogateRequest) ([]SurrogateResult, error) {
+	if req.Seed == "" {
+		return nil, fmt.Errorf("seed is required")
+	}
+
+	results := make([]SurrogateResult, len(req.Terms))
+	for i, term := range req.Terms {
+		res := SurrogateResult{Term: term, Surrogate: term}
+		cr := reg.Classify(term, req.Opts)
+		for _, m := range cr.Matches {
+			d, ok := reg.Get(m.DictID)
+			if !ok || !samplable(d) {
+				continue
+			}
+			out, conds, ok, err := pickSurrogate(d, term, []byte(req.Seed))
+			if err != nil {
+				return nil, fmt.Errorf("term %d: dict %s: %w", i, m.DictID, err)
+			}
+			if ok {
+				res.Surrogate = out
+				res.Replaced = true
+				res.DictID = m.DictID
+				res.EntityType = m.EntityType
+				res.Conditions = conds
+			}
+			break
+		}
+		results[i] = res
+	}
+	return results, nil
+}
+
+// surrogateStrategy exposes surrogates as a pseudo_strategy, the client key
+// acting as seed.
+func surrogateStrategy(in *Input) (string, error) {
+	d, ok := in.Registry.Get(in.Match.DictID)
+	if !ok || !samplable(d) {
+		return "", fmt.Errorf("surrogate requires a lookup dictionary")
+	}
+	out, _, ok, err := pickSurrogate(d, in.Term, in.Key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("no surrogate for %q", in.Term)
+	}
+	return out, nil
+}
+
+// maxSurrogateEntries caps the dictionaries surrogates are drawn from: their
+// index is held in memory, at about 50 bytes per entry, enough for the 1.3
+// million surnames of patronymes-fr.
+const maxSurrogateEntries = 2_000_000
+
+// samplable reports whether surrogates may be drawn from d: a lookup
+// dictionary whose manifest opts in with surrogates: true, small enough to
+// index in memory.
+func samplable(d *dict.Dictionary) bool {
+	n := d.EntryCount()
+	return d.Manifest.Surrogates && d.Manifest.Method != "pattern" && d.Manifest.Type != "alias_pool" &&
+		n > 1 && n <= maxSurrogateEntries
+}
+
+// condition is one stratum dimension derived from an entry.
+type condition struct {
+	name  string
+	value func(key string, meta map[string]string) string
+}
+
+// conditions, from the most to the least binding. Strata are relaxed from the
+// end when a term is alone in its own: frequency first, then form. Department
+// and gender are never relaxed.
+var conditions = []condition{
+	{"department", func(_ string, meta map[string]string) string {
+		return firstMeta(meta, "departement", "department")
+	}},
+	{"gender", func(_ string, meta map[string]string) string {
+		return normalizeGender(firstMeta(meta, "gender", "sexe", "sex"))
+	}},
+	{"form", func(key string, _ map[string]string) string {
+		if strings.ContainsAny(key, "- ") {
+			return "compound"
+		}
+		return "simple"
+	}},
+	{"frequency", func(_ string, meta map[string]string) string {
+		return frequencyClass(firstMeta(meta, "frequency"))
+	}},
+}
+
+// hardConditions is the number of leading conditions never relaxed.
+const hardConditions = 2
+
+func firstMeta(meta map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := meta[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalizeGender maps INSEE codes (1, 2) and spelled-out genders to M and F.
+func normalizeGender(g string) string {
+	switch strings.ToLower(g) {
+	case "1", "m", "male", "masculin":
+		return "M"
+	case "2", "f", "female", "feminin":
+		return "F"
+	}
+	return g
+}
+
+// frequencyClass buckets a count by order of magnitude ("1e3" for 1000-9999);
+// labels such as "very_common" are classes already.
+func frequencyClass(f string) string {
+	n, err := strconv.ParseFloat(f, 64)
+	if err != nil {
+		return f
+	}
+	if n < 1 {
+		return "0"
+	}
+	return "1e" + strconv.Itoa(int(math.Log10(n)))
+}
+
+// surrogateIndex groups the keys of a dictionary by stratum, at every
+// relaxation level. Strata are numbered and hold positions in keys, so the
+// index of a large dictionary stays compact.
+type surrogateIndex struct {
+	d      *dict.Dictionary
+	once   sync.Once
+	err    error
+	keys   []string   // sorted
+	levels [][]int32  // levels[l][i]: stratum of keys[i] under the first hardConditions+l conditions
+	strata [][]int32  // stratum → positions in keys, ascending
+	values [][]string // stratum → its condition values
+}
+
+var (
+	surrogateMu      sync.Mutex
+	surrogateIndexes = make(map[string]*surrogateIndex) // dict ID → index
+)
+
+// indexFor returns the index of d, building it on first use and again after a
+// reload replaced the dictionary. The build holds no global lock: requests
+// for other dictionaries go on while one is indexed.
+func indexFor(d *dict.Dictionary) (*surrogateIndex, error) {
+	surrogateMu.Lock()
+	ix := surrogateIndexes[d.Manifest.ID]
+	if ix == nil || ix.d != d {
+		ix = &surrogateIndex{d: d}
+		surrogateIndexes[d.Manifest.ID] = ix
+	}
+	surrogateMu.Unlock()
+
+	ix.once.Do(func() { ix.err = ix.build() })
+	if ix.err != nil {
+		return nil, ix.err
+	}
+	return ix, nil
+}
+
+func (ix *surrogateIndex) build() error {
+	type row struct {
+		key    string
+		strata []int32
+	}
+	var rows []row
+	ids := make(map[string]int32)
+	err := ix.d.Each(func(key string, e *dict.Entry) bool {
+		var meta map[string]string
+		if e != nil {
+			meta = e.Metadata
+		}
+		vals := make([]string, len(conditions))
+		for i, c := range conditions {
+			vals[i] = c.value(key, meta)
+		}
+		r := row{key: key, strata: make([]int32, len(conditions)-hardConditions+1)}
+		for l := range r.strata {
+			sk := stratumKey(vals[:hardConditions+l])
+			id, ok := ids[sk]
+			if !ok {
+				id = int32(len(ix.values))
+				ids[sk] = id
+				ix.values = append(ix.values, vals[:hardConditions+l])
+			}
+			r.strata[l] = id
+		}
+		rows = append(rows, r)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].key < rows[j].key })
+
+	ix.keys = make([]string, len(rows))
+	ix.levels = make([][]int32, len(conditions)-hardConditions+1)
+	for l := range ix.levels {
+		ix.levels[l] = make([]int32, len(rows))
+	}
+	ix.strata = make([][]int32, len(ix.values))
+	for i, r := range rows {
+		ix.keys[i] = r.key
+		for l, id := range r.strata {
+			ix.levels[l][i] = id
+			ix.strata[id] = append(ix.strata[id], int32(i))
+		}
+	}
+	return nil
+}
+
+func stratumKey(vals []string) string {
+	return strconv.Itoa(len(vals)) + "\x00" + strings.Join(vals, "\x00")
+}
+
+// pickSurrogate picks another key of term's stratum, relaxing the soft
+// conditions until the stratum holds a second key, and renders it in the
+// case of term. ok is false when no other key qualifies.
+func pickSurrogate(d *dict.Dictionary, term string, seed []byte) (out string, conds map[string]string, ok bool, err error) {
+	ix, err := indexFor(d)
+	if err != nil {
+		return "", nil, false, err
+	}
+	key := d.NormalizeTerm(term)
+	i := sort.SearchStrings(ix.keys, key)
+	if i == len(ix.keys) || ix.keys[i] != key {
+		return "", nil, false, nil
+	}
+
+	for l := len(ix.levels) - 1; l >= 0; l-- {
+		id := ix.levels[l][i]
+		members := ix.strata[id]
+		if len(members) < 2 {
+			continue
+		}
+		ks := newKeystream(seed, "surrogate", d.Manifest.ID, key)
+		self := sort.Search(len(members), func(k int) bool { return members[k] >= int32(i) })
+		// Skip the term itself: draw among the others and shift past it.
+		j := ks.intn(len(members) - 1)
+		if j >= self {
+			j++
+		}
+
+		conds = make(map[string]string)
+		for c, v := range ix.values[id] {
+			if v != "" {
+				conds[conditions[c].name] = v
+			}
+		}
+		return matchCase(ix.keys[members[j]], term), conds, true, nil
+	}
+	return "", nil, false, nil
+}
+
+// matchCase renders a normalized key like term: all caps, all lowercase, or
+// capitalized at the start of every word ("Jean-Pierre", "Saint-Etienne").
+func matchCase(key, term string) string {
+	hasUpper, hasLower := false, false
+	for _, r := range term {
+		hasUpper = hasUpper || unicode.IsUpper(r)
+		hasLower = hasLower || unicode.IsLower(r)
+	}
+	switch {
+	case hasUpper && !hasLower:
+		return strings.ToUpper(key)
+	case hasLower && !hasUpper:
+		return key
+	}
+
+	out := []rune(key)
+	start := true
+	for i, r := range out {
+		if start {
+			out[i] = unicode.ToUpper(r)
+		}
+		start = r == '-' || r == ' ' || r == '\''
+	}
+	return string(out)
+}
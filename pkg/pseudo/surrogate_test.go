@@ -0,0 +1,228 @@
+package pseudo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hazyhaar/touchstone-registry/pkg/dict"
+	_ "modernc.org/sqlite"
+)
+
+func setupSurrogateRegistry(t *testing.T) *dict.Registry {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(id, entityType, data string, meta ...string) {
+		t.Helper()
+		manifest := "id: " + id + `
+version: "1.0"
+jurisdiction: fr
+entity_type: ` + entityType + `
+source: test
+data_file: data.csv
+surrogates: true
+format:
+  delimiter: ";"
+  has_header: true
+  key_column: "term"
+metadata_columns:
+`
+		for _, m := range meta {
+			manifest += "  - name: " + m + "\n    column: " + m + "\n"
+		}
+		writeDict(t, dir, id, manifest, data)
+	}
+
+	write("patronymes-fr", "surname", `term;frequency
+MARTIN;235846
+BERNARD;105463
+DUPONT;64217
+LAMBERT;70412
+FONTAINE;60288
+ZYLBERSTEIN;112
+`, "frequency")
+	write("prenoms-fr", "first_name", `term;frequency;sexe
+JEAN-PIERRE;402311;1
+JEAN-MARC;120744;1
+MARIE-CLAIRE;98421;2
+PIERRE;610341;1
+MARIE;2250513;2
+`, "frequency", "sexe")
+	write("communes-fr", "city", `term;departement
+LYON;69
+VILLEURBANNE;69
+VENISSIEUX;69
+MARSEILLE;13
+`, "departement")
+
+	reg := dict.NewRegistry(dir)
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return reg
+}
+
+func surrogate(t *testing.T, reg *dict.Registry, seed string, terms ...string) []SurrogateResult {
+	t.Helper()
+	results, err := Surrogate(reg, &SurrogateRequest{Terms: terms, Seed: seed})
+	if err != nil {
+		t.Fatalf("Surrogate: %v", err)
+	}
+	return results
+}
+
+func TestSurrogate_Conditions(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+
+	for _, seed := range []string{"a", "b", "c", "d", "e", "f"} {
+		res := surrogate(t, reg, seed, "DUPONT", "Jean-Pierre", "Lyon")
+
+		// Same frequency class (1e4): never MARTIN or BERNARD (1e5).
+		if r := res[0]; !r.Replaced || r.DictID != "patronymes-fr" {
+			t.Fatalf("DUPONT: %+v", r)
+		} else if !containsString([]string{"LAMBERT", "FONTAINE"}, r.Surrogate) {
+			t.Errorf("seed %s: DUPONT -> %q, want a surname of the same frequency class", seed, r.Surrogate)
+		}
+
+		// Male compound first name, case preserved.
+		if r := res[1]; r.Surrogate != "Jean-Marc" || r.Conditions["gender"] != "M" || r.Conditions["form"] != "compound" {
+			t.Errorf("seed %s: Jean-Pierre -> %+v", seed, r)
+		}
+
+		// Commune of the same department.
+		if r := res[2]; !containsString([]string{"Villeurbanne", "Venissieux"}, r.Surrogate) || r.Conditions["department"] != "69" {
+			t.Errorf("seed %s: Lyon -> %+v", seed, r)
+		}
+	}
+}
+
+func TestSurrogate_Deterministic(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+
+	a := surrogate(t, reg, "seed-1", "DUPONT", "dupont", "Lyon")
+	b := surrogate(t, reg, "seed-1", "DUPONT", "Lyon")
+	if a[0].Surrogate != b[0].Surrogate || a[2].Surrogate != b[1].Surrogate {
+		t.Errorf("same seed gave different surrogates: %+v / %+v", a, b)
+	}
+	if lower := a[1].Surrogate; lower == "" || lower != dict.NormalizeLowercaseASCII(a[0].Surrogate) {
+		t.Errorf("dupont -> %q, want lowercase of %q", lower, a[0].Surrogate)
+	}
+}
+
+func TestSurrogate_Relaxation(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+
+	// ZYLBERSTEIN is alone in its frequency class: frequency is relaxed.
+	r := surrogate(t, reg, "seed", "ZYLBERSTEIN")[0]
+	if !r.Replaced || r.Surrogate == "ZYLBERSTEIN" {
+		t.Fatalf("ZYLBERSTEIN: %+v", r)
+	}
+	if _, ok := r.Conditions["frequency"]; ok {
+		t.Errorf("frequency still in conditions: %v", r.Conditions)
+	}
+
+	// MARSEILLE is alone in its department, which is never relaxed.
+	if r := surrogate(t, reg, "seed", "MARSEILLE")[0]; r.Replaced || r.Surrogate != "MARSEILLE" {
+		t.Errorf("MARSEILLE: %+v", r)
+	}
+
+	// Unknown terms are kept.
+	if r := surrogate(t, reg, "seed", "Quux")[0]; r.Replaced {
+		t.Errorf("Quux: %+v", r)
+	}
+}
+
+func TestSurrogate_OptIn(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+	d, _ := reg.Get("communes-fr")
+	d.Manifest.Surrogates = false
+
+	if r := surrogate(t, reg, "seed", "Lyon")[0]; r.Replaced {
+		t.Errorf("Lyon replaced from a dictionary without surrogates: %+v", r)
+	}
+	if r := surrogate(t, reg, "seed", "DUPONT")[0]; !r.Replaced {
+		t.Errorf("DUPONT: %+v", r)
+	}
+}
+
+func TestSurrogate_SQLiteSurnames(t *testing.T) {
+	// Built like the insee-patronymes-fr import: data.db, surrogates: true.
+	dir := filepath.Join(t.TempDir(), "patronymes-fr")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sw, err := dict.CreateSQLite(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, freq := range map[string]string{"martin": "235846", "dupont": "64217", "lambert": "70412", "fontaine": "60288"} {
+		if err := sw.Put(key, &dict.Entry{Metadata: map[string]string{"frequency": freq}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(`id: patronymes-fr
+version: "1.0"
+jurisdiction: fr
+entity_type: surname
+source: test
+data_file: data.db
+surrogates: true
+format:
+  normalize: lowercase_ascii
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := dict.NewRegistry(filepath.Dir(dir))
+	if err := reg.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	r := surrogate(t, reg, "seed", "DUPONT")[0]
+	if !r.Replaced || r.DictID != "patronymes-fr" || !containsString([]string{"LAMBERT", "FONTAINE"}, r.Surrogate) {
+		t.Errorf("DUPONT -> %+v, want a surname of the same frequency class", r)
+	}
+}
+
+func TestSurrogate_RequiresSeed(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+	if _, err := Surrogate(reg, &SurrogateRequest{Terms: []string{"DUPONT"}}); err == nil {
+		t.Error("expected error without seed")
+	}
+}
+
+func TestSurrogate_Strategy(t *testing.T) {
+	reg := setupSurrogateRegistry(t)
+
+	results, err := Pseudonymize(context.Background(), reg, &Request{
+		Terms:    []string{"Lyon"},
+		Key:      testKey,
+		Strategy: "surrogate",
+	})
+	if err != nil {
+		t.Fatalf("Pseudonymize: %v", err)
+	}
+	want := surrogate(t, reg, string(testKey), "Lyon")[0].Surrogate
+	if r := results[0]; !r.Replaced || r.Pseudonym != want {
+		t.Errorf("surrogate strategy = %+v, want %q", r, want)
+	}
+}
+
+func TestFrequencyClass(t *testing.T) {
+	tests := map[string]string{
+		"64217":       "1e4",
+		"9":           "1e0",
+		"1000":        "1e3",
+		"0":           "0",
+		"very_common": "very_common",
+		"":            "",
+	}
+	for in, want := range tests {
+		if got := frequencyClass(in); got != want {
+			t.Errorf("frequencyClass(%q) = %q, want %q", in, got, want)
+		}
+	}
+}